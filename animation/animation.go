@@ -0,0 +1,92 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package animation animates values, such as the properties of controls,
+// over the frames of a gxui.Driver.
+//
+// An Animation is advanced by the time elapsed between frames. Tweens
+// interpolate between two values along an Easing curve, Springs move towards
+// a target with a physical spring, and Sequence, Parallel, Delay and Repeat
+// compose animations. Start plays an animation on the frames of a driver.
+package animation
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// Animation is a change of values over time.
+type Animation interface {
+	// Advance moves the animation forward by dt and applies the new values.
+	// Advance returns true while the animation is running, and false once it
+	// has finished, in which case the final values have been applied.
+	Advance(dt time.Duration) bool
+
+	// Reset returns the animation to its start, so that it can be played
+	// again.
+	Reset()
+}
+
+// Player plays an Animation on the frames of a driver.
+type Player struct {
+	animation Animation
+	frame     gxui.EventSubscription
+	detach    gxui.EventSubscription
+	last      time.Duration
+	started   bool
+	onFinish  gxui.Event
+}
+
+// Start plays the animation a on the frames of driver. The first frame
+// applies the starting values of a. If c is not nil, the animation is
+// stopped when c is detached. Start must be called on the UI go-routine.
+func Start(driver gxui.Driver, c gxui.Control, a Animation) *Player {
+	p := &Player{animation: a}
+	p.frame = driver.OnFrame(p.advance)
+	if c != nil {
+		p.detach = c.OnDetach(p.Stop)
+	}
+	return p
+}
+
+func (p *Player) advance(t time.Duration) {
+	dt := time.Duration(0)
+	if p.started {
+		dt = t - p.last
+	}
+	p.started, p.last = true, t
+	if !p.animation.Advance(dt) {
+		p.Stop()
+		if p.onFinish != nil {
+			p.onFinish.Fire()
+		}
+	}
+}
+
+// Playing returns true if the animation has not finished or been stopped.
+func (p *Player) Playing() bool {
+	return p.frame != nil
+}
+
+// Stop stops the animation, leaving the values as they were last applied.
+func (p *Player) Stop() {
+	if p.frame != nil {
+		p.frame.Unlisten()
+		p.frame = nil
+	}
+	if p.detach != nil {
+		p.detach.Unlisten()
+		p.detach = nil
+	}
+}
+
+// OnFinish subscribes f to be called when the animation finishes. f is not
+// called if the animation is stopped before it finishes.
+func (p *Player) OnFinish(f func()) gxui.EventSubscription {
+	if p.onFinish == nil {
+		p.onFinish = gxui.CreateEvent(func() {})
+	}
+	return p.onFinish.Listen(f)
+}
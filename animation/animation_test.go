@@ -0,0 +1,163 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/animation"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+const ms = time.Millisecond
+
+func TestEasingEndpoints(t *testing.T) {
+	for name, e := range map[string]animation.Easing{
+		"Linear":       animation.Linear,
+		"EaseIn":       animation.EaseIn,
+		"EaseOut":      animation.EaseOut,
+		"EaseInOut":    animation.EaseInOut,
+		"EaseInCubic":  animation.EaseInCubic,
+		"EaseOutCubic": animation.EaseOutCubic,
+		"EaseOutBack":  animation.EaseOutBack,
+		"CubicBezier":  animation.CubicBezier(0.25, 0.1, 0.25, 1),
+	} {
+		if got := e(0); math.Absf(got) > 1e-4 {
+			t.Errorf("%s(0) was %v, expected 0", name, got)
+		}
+		if got := e(1); math.Absf(got-1) > 1e-4 {
+			t.Errorf("%s(1) was %v, expected 1", name, got)
+		}
+	}
+}
+
+func TestCubicBezierLinear(t *testing.T) {
+	e := animation.CubicBezier(1.0/3, 1.0/3, 2.0/3, 2.0/3)
+	for _, x := range []float32{0.1, 0.25, 0.5, 0.75, 0.9} {
+		if got := e(x); math.Absf(got-x) > 1e-3 {
+			t.Errorf("e(%v) was %v, expected %v", x, got, x)
+		}
+	}
+}
+
+func TestTween(t *testing.T) {
+	values := []float32{}
+	tween := animation.Float(10, 20, 100*ms, animation.Linear, func(v float32) {
+		values = append(values, v)
+	})
+	running := []bool{
+		tween.Advance(0),
+		tween.Advance(50 * ms),
+		tween.Advance(60 * ms),
+	}
+	expectedValues := []float32{10, 15, 20}
+	expectedRunning := []bool{true, true, false}
+	for i := range expectedValues {
+		if values[i] != expectedValues[i] || running[i] != expectedRunning[i] {
+			t.Errorf("Frame %d: got (%v, %v), expected (%v, %v)",
+				i, values[i], running[i], expectedValues[i], expectedRunning[i])
+		}
+	}
+}
+
+func TestSpringSettles(t *testing.T) {
+	var value float32
+	spring := animation.CreateSpring(0, 100, func(v float32) { value = v })
+	frames, overshoot := 0, false
+	for spring.Advance(16 * ms) {
+		if value > 100 {
+			overshoot = true
+		}
+		if frames++; frames > 1000 {
+			t.Fatalf("Spring still running after %d frames, value %v", frames, value)
+		}
+	}
+	if value != 100 {
+		t.Errorf("Spring settled at %v, expected 100", value)
+	}
+	if overshoot {
+		t.Error("Critically damped spring overshot the target")
+	}
+
+	// An underdamped spring overshoots.
+	spring = animation.CreateSpring(0, 100, func(v float32) { value = v })
+	spring.Damping = 5
+	overshoot = false
+	for spring.Advance(16*ms) && !overshoot {
+		overshoot = value > 100
+	}
+	if !overshoot {
+		t.Error("Underdamped spring did not overshoot the target")
+	}
+}
+
+func TestSequenceParallelRepeat(t *testing.T) {
+	log := []string{}
+	a := animation.Float(0, 1, 20*ms, nil, func(float32) {})
+	b := animation.Float(0, 1, 40*ms, nil, func(float32) {})
+	anim := animation.Repeat(animation.Sequence(
+		animation.Call(func() { log = append(log, "start") }),
+		animation.Parallel(a, b),
+		animation.Delay(10*ms),
+		animation.Call(func() { log = append(log, "end") }),
+	), 2)
+
+	frames := 1
+	for anim.Advance(10 * ms) {
+		frames++
+	}
+	// Each repetition takes 6 frames, as every animation starts on the frame
+	// the previous one finishes: the parallel tweens start on the first frame
+	// and finish 40ms later on the fifth, then the delay ends on the sixth.
+	if got, expected := frames, 12; got != expected {
+		t.Errorf("Animation ran for %d frames, expected %d", got, expected)
+	}
+	expected := []string{"start", "end", "start", "end"}
+	if len(log) != len(expected) {
+		t.Fatalf("Log was %v, expected %v", log, expected)
+	}
+	for i := range log {
+		if log[i] != expected[i] {
+			t.Errorf("Log was %v, expected %v", log, expected)
+			break
+		}
+	}
+}
+
+func TestStartStopsOnDetach(t *testing.T) {
+	// The driver is terminated by the animation, as the frames arrive after
+	// the application function returns.
+	soft.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window := theme.CreateWindow(64, 32, "test")
+		label := theme.CreateLabel()
+		label.SetText("Hello")
+		window.AddChild(label)
+
+		frames := 0
+		var player *animation.Player
+		player = animation.Start(driver, label, animation.Repeat(
+			animation.Opacity(label, 0, 100*ms, animation.EaseInOut), 0))
+		driver.OnFrame(func(time.Duration) {
+			frames++
+			if frames == 3 {
+				window.RemoveChild(label)
+				if player.Playing() {
+					t.Error("Expected the animation to stop when the label was detached")
+				}
+				if label.Opacity() == 1 {
+					t.Error("Expected the animation to fade the label")
+				}
+				window.Close()
+				driver.Terminate()
+			}
+		})
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"time"
+)
+
+type sequence struct {
+	animations []Animation
+	index      int
+}
+
+// Sequence returns an Animation that plays each of the animations in turn.
+// Each animation starts on the frame that the previous one finishes.
+func Sequence(animations ...Animation) Animation {
+	return &sequence{animations: append([]Animation{}, animations...)}
+}
+
+func (s *sequence) Advance(dt time.Duration) bool {
+	for s.index < len(s.animations) {
+		if s.animations[s.index].Advance(dt) {
+			return true
+		}
+		s.index++
+		dt = 0
+	}
+	return false
+}
+
+func (s *sequence) Reset() {
+	for _, a := range s.animations {
+		a.Reset()
+	}
+	s.index = 0
+}
+
+type parallel struct {
+	animations []Animation
+	done       []bool
+}
+
+// Parallel returns an Animation that plays all of the animations at the same
+// time, finishing when the longest finishes.
+func Parallel(animations ...Animation) Animation {
+	return &parallel{
+		animations: append([]Animation{}, animations...),
+		done:       make([]bool, len(animations)),
+	}
+}
+
+func (p *parallel) Advance(dt time.Duration) bool {
+	running := false
+	for i, a := range p.animations {
+		if !p.done[i] {
+			if a.Advance(dt) {
+				running = true
+			} else {
+				p.done[i] = true
+			}
+		}
+	}
+	return running
+}
+
+func (p *parallel) Reset() {
+	for i, a := range p.animations {
+		a.Reset()
+		p.done[i] = false
+	}
+}
+
+type delay struct {
+	duration, elapsed time.Duration
+}
+
+// Delay returns an Animation that does nothing for duration. It is used to
+// pause between the animations of a Sequence.
+func Delay(duration time.Duration) Animation {
+	return &delay{duration: duration}
+}
+
+func (d *delay) Advance(dt time.Duration) bool {
+	d.elapsed += dt
+	return d.elapsed < d.duration
+}
+
+func (d *delay) Reset() {
+	d.elapsed = 0
+}
+
+type repeat struct {
+	animation Animation
+	count     int
+	played    int
+}
+
+// Repeat returns an Animation that plays a count times, or forever if count
+// is 0.
+func Repeat(a Animation, count int) Animation {
+	return &repeat{animation: a, count: count}
+}
+
+func (r *repeat) Advance(dt time.Duration) bool {
+	if r.animation.Advance(dt) {
+		return true
+	}
+	r.played++
+	if r.count > 0 && r.played >= r.count {
+		return false
+	}
+	r.animation.Reset()
+	return true
+}
+
+func (r *repeat) Reset() {
+	r.animation.Reset()
+	r.played = 0
+}
+
+type call struct {
+	f func()
+}
+
+// Call returns an Animation that calls f and finishes immediately. It is
+// used to run code between the animations of a Sequence.
+func Call(f func()) Animation {
+	return &call{f}
+}
+
+func (c *call) Advance(time.Duration) bool {
+	c.f()
+	return false
+}
+
+func (c *call) Reset() {}
+
+type deferred struct {
+	create    func() Animation
+	animation Animation
+}
+
+// Deferred returns an Animation that calls create on its first frame, and
+// then plays the returned animation. It is used to start animations from
+// the values at the time they start, rather than when they are created.
+func Deferred(create func() Animation) Animation {
+	return &deferred{create: create}
+}
+
+func (d *deferred) Advance(dt time.Duration) bool {
+	if d.animation == nil {
+		d.animation = d.create()
+	}
+	return d.animation.Advance(dt)
+}
+
+func (d *deferred) Reset() {
+	d.animation = nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// The animations of this file start from the values of the properties on
+// the first frame they are played.
+
+// Colored is implemented by controls with a color, such as gxui.Label.
+type Colored interface {
+	Color() gxui.Color
+	SetColor(gxui.Color)
+}
+
+// BackgroundBrushed is implemented by controls with a background brush.
+type BackgroundBrushed interface {
+	BackgroundBrush() gxui.Brush
+	SetBackgroundBrush(gxui.Brush)
+}
+
+// Opacity returns an Animation fading the opacity of c to to.
+func Opacity(c gxui.Control, to float32, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		return Float(c.Opacity(), to, duration, easing, c.SetOpacity)
+	})
+}
+
+// ColorTo returns an Animation changing the color of c to to.
+func ColorTo(c Colored, to gxui.Color, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		return Color(c.Color(), to, duration, easing, c.SetColor)
+	})
+}
+
+// BackgroundTo returns an Animation changing the background of c to a solid
+// brush of the color to.
+func BackgroundTo(c BackgroundBrushed, to gxui.Color, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		return Color(c.BackgroundBrush().Color, to, duration, easing, func(color gxui.Color) {
+			c.SetBackgroundBrush(gxui.CreateBrush(color))
+		})
+	})
+}
+
+// Layout returns an Animation moving and resizing child within its parent
+// to the rectangle to, in the parent's coordinates. The parent places its
+// children again when it is next laid out, so Layout is typically used to
+// animate a child from its old bounds to those chosen by the latest layout.
+func Layout(child *gxui.Child, to math.Rect, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		from := child.Control.Size().Rect().Offset(child.Offset)
+		return Rect(from, to, duration, easing, func(r math.Rect) {
+			child.Layout(r)
+			if p := child.Control.Parent(); p != nil {
+				p.Redraw()
+			}
+		})
+	})
+}
+
+// Move returns an Animation moving child within its parent to the offset to,
+// keeping its size. See Layout.
+func Move(child *gxui.Child, to math.Point, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		return Layout(child, child.Control.Size().Rect().Offset(to), duration, easing)
+	})
+}
+
+// Resize returns an Animation resizing child to the size to, keeping its
+// offset within its parent. See Layout.
+func Resize(child *gxui.Child, to math.Size, duration time.Duration, easing Easing) Animation {
+	return Deferred(func() Animation {
+		return Layout(child, to.Rect().Offset(child.Offset), duration, easing)
+	})
+}
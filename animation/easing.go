@@ -0,0 +1,85 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Easing maps the linear progress of a Tween, from 0 to 1, to the fraction
+// of the way from the start value to the end value. Easings return 0 for 0
+// and 1 for 1, but may leave the range in between to overshoot.
+type Easing func(t float32) float32
+
+var (
+	// Linear moves at a constant speed.
+	Linear Easing = func(t float32) float32 { return t }
+
+	// EaseIn starts slowly and accelerates.
+	EaseIn Easing = func(t float32) float32 { return t * t }
+
+	// EaseOut starts quickly and decelerates.
+	EaseOut Easing = func(t float32) float32 { return 1 - (1-t)*(1-t) }
+
+	// EaseInOut accelerates and then decelerates.
+	EaseInOut Easing = func(t float32) float32 { return math.SmoothStep(t, 0, 1) }
+
+	// EaseInCubic starts more slowly than EaseIn.
+	EaseInCubic Easing = func(t float32) float32 { return t * t * t }
+
+	// EaseOutCubic decelerates more sharply than EaseOut.
+	EaseOutCubic Easing = func(t float32) float32 { u := 1 - t; return 1 - u*u*u }
+
+	// EaseOutBack overshoots the end value before settling on it.
+	EaseOutBack Easing = func(t float32) float32 {
+		const s = 1.70158
+		u := t - 1
+		return 1 + u*u*((s+1)*u+s)
+	}
+)
+
+// CubicBezier returns the Easing of the cubic Bézier curve from (0, 0) to
+// (1, 1) with the control points (x1, y1) and (x2, y2), as used by CSS
+// transitions. x1 and x2 must be between 0 and 1.
+func CubicBezier(x1, y1, x2, y2 float32) Easing {
+	bezier := func(t, p1, p2 float32) float32 {
+		u := 1 - t
+		return 3*u*u*t*p1 + 3*u*t*t*p2 + t*t*t
+	}
+	slope := func(t, p1, p2 float32) float32 {
+		u := 1 - t
+		return 3*u*u*p1 + 6*u*t*(p2-p1) + 3*t*t*(1-p2)
+	}
+	return func(x float32) float32 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		// Solve bezier(t, x1, x2) = x with Newton's method, falling back to
+		// bisection where the slope is too shallow.
+		t := x
+		for i := 0; i < 8; i++ {
+			e := bezier(t, x1, x2) - x
+			if math.Absf(e) < 1e-5 {
+				return bezier(t, y1, y2)
+			}
+			d := slope(t, x1, x2)
+			if math.Absf(d) < 1e-6 {
+				break
+			}
+			t = math.Saturate(t - e/d)
+		}
+		lo, hi := float32(0), float32(1)
+		t = x
+		for i := 0; i < 32; i++ {
+			if bezier(t, x1, x2) < x {
+				lo = t
+			} else {
+				hi = t
+			}
+			t = (lo + hi) / 2
+		}
+		return bezier(t, y1, y2)
+	}
+}
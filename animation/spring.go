@@ -0,0 +1,118 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+const (
+	// DefaultStiffness is the stiffness of a Spring created by CreateSpring.
+	DefaultStiffness = 170
+
+	// DefaultDamping is the damping of a Spring created by CreateSpring.
+	DefaultDamping = 26
+)
+
+// springStep is the time step used to simulate springs.
+const springStep = time.Millisecond
+
+// Spring is an Animation that moves a value towards a target as if attached
+// to it by a damped spring of unit mass. Unlike a Tween, a Spring has no fixed
+// duration, and its target can be changed while it is moving without a jump
+// in velocity.
+type Spring struct {
+	// Stiffness is the force pulling the value towards the target, per unit of
+	// distance.
+	Stiffness float32
+
+	// Damping is the force slowing the value, per unit of velocity. A damping
+	// of 2·√Stiffness reaches the target as quickly as possible without
+	// overshooting it. Less damping overshoots and oscillates.
+	Damping float32
+
+	// Precision is the distance from the target, and the speed per second,
+	// below which the spring comes to rest. A Precision of 0 uses a thousandth
+	// of the distance between the starting value and the target.
+	Precision float32
+
+	from, to        float32
+	value, velocity float32
+	set             func(float32)
+	remainder       time.Duration
+}
+
+// CreateSpring returns a Spring moving from from to to with the default
+// stiffness and damping, calling set with the value every frame.
+func CreateSpring(from, to float32, set func(float32)) *Spring {
+	return &Spring{
+		Stiffness: DefaultStiffness,
+		Damping:   DefaultDamping,
+		from:      from,
+		to:        to,
+		value:     from,
+		set:       set,
+	}
+}
+
+// Value returns the current value of the spring.
+func (s *Spring) Value() float32 {
+	return s.value
+}
+
+// Velocity returns the current velocity of the spring, in units per second.
+func (s *Spring) Velocity() float32 {
+	return s.velocity
+}
+
+// Target returns the value the spring is moving towards.
+func (s *Spring) Target() float32 {
+	return s.to
+}
+
+// SetTarget changes the value the spring is moving towards, keeping the
+// current value and velocity.
+func (s *Spring) SetTarget(to float32) {
+	s.to = to
+}
+
+func (s *Spring) precision() float32 {
+	if s.Precision > 0 {
+		return s.Precision
+	}
+	if p := math.Absf(s.to-s.from) / 1000; p > 1e-4 {
+		return p
+	}
+	return 1e-4
+}
+
+// Animation compliance
+func (s *Spring) Advance(dt time.Duration) bool {
+	h := float32(springStep.Seconds())
+	dt += s.remainder
+	for ; dt >= springStep; dt -= springStep {
+		// Semi-implicit Euler integration, which is stable for stiff springs
+		// at small time steps.
+		a := -s.Stiffness*(s.value-s.to) - s.Damping*s.velocity
+		s.velocity += a * h
+		s.value += s.velocity * h
+	}
+	s.remainder = dt
+
+	p := s.precision()
+	if math.Absf(s.value-s.to) < p && math.Absf(s.velocity) < p {
+		s.value, s.velocity, s.remainder = s.to, 0, 0
+		s.set(s.value)
+		return false
+	}
+	s.set(s.value)
+	return true
+}
+
+func (s *Spring) Reset() {
+	s.value, s.velocity, s.remainder = s.from, 0, 0
+}
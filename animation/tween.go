@@ -0,0 +1,114 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package animation
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Tween is an Animation that calls a function with the eased progress of the
+// animation over a fixed duration.
+type Tween struct {
+	duration time.Duration
+	easing   Easing
+	apply    func(f float32)
+	elapsed  time.Duration
+}
+
+// CreateTween returns a Tween lasting duration, that calls apply every frame
+// with the progress through the animation mapped by easing. apply is called
+// with easing(0) on the first frame, and easing(1) on the last. A nil easing
+// is Linear.
+func CreateTween(duration time.Duration, easing Easing, apply func(f float32)) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+	return &Tween{duration: duration, easing: easing, apply: apply}
+}
+
+// Duration returns the length of the tween.
+func (t *Tween) Duration() time.Duration {
+	return t.duration
+}
+
+// Animation compliance
+func (t *Tween) Advance(dt time.Duration) bool {
+	t.elapsed += dt
+	if t.elapsed >= t.duration {
+		t.apply(t.easing(1))
+		return false
+	}
+	t.apply(t.easing(float32(t.elapsed) / float32(t.duration)))
+	return true
+}
+
+func (t *Tween) Reset() {
+	t.elapsed = 0
+}
+
+// Float returns a Tween calling set with the values from from to to.
+func Float(from, to float32, duration time.Duration, easing Easing, set func(float32)) *Tween {
+	return CreateTween(duration, easing, func(f float32) {
+		set(math.Lerpf(from, to, f))
+	})
+}
+
+// Color returns a Tween calling set with the colors from from to to.
+func Color(from, to gxui.Color, duration time.Duration, easing Easing, set func(gxui.Color)) *Tween {
+	return CreateTween(duration, easing, func(f float32) {
+		set(LerpColor(from, to, f))
+	})
+}
+
+// Point returns a Tween calling set with the points from from to to.
+func Point(from, to math.Point, duration time.Duration, easing Easing, set func(math.Point)) *Tween {
+	return CreateTween(duration, easing, func(f float32) {
+		set(LerpPoint(from, to, f))
+	})
+}
+
+// Size returns a Tween calling set with the sizes from from to to.
+func Size(from, to math.Size, duration time.Duration, easing Easing, set func(math.Size)) *Tween {
+	return CreateTween(duration, easing, func(f float32) {
+		set(LerpSize(from, to, f))
+	})
+}
+
+// Rect returns a Tween calling set with the rectangles from from to to.
+func Rect(from, to math.Rect, duration time.Duration, easing Easing, set func(math.Rect)) *Tween {
+	return CreateTween(duration, easing, func(f float32) {
+		set(math.Rect{Min: LerpPoint(from.Min, to.Min, f), Max: LerpPoint(from.Max, to.Max, f)})
+	})
+}
+
+// LerpColor returns the color a fraction f of the way from a to b.
+func LerpColor(a, b gxui.Color, f float32) gxui.Color {
+	return gxui.Color{
+		R: math.Lerpf(a.R, b.R, f),
+		G: math.Lerpf(a.G, b.G, f),
+		B: math.Lerpf(a.B, b.B, f),
+		A: math.Lerpf(a.A, b.A, f),
+	}
+}
+
+// LerpPoint returns the point a fraction f of the way from a to b, rounded
+// to the nearest integer coordinates.
+func LerpPoint(a, b math.Point, f float32) math.Point {
+	return math.Point{X: lerpRound(a.X, b.X, f), Y: lerpRound(a.Y, b.Y, f)}
+}
+
+// LerpSize returns the size a fraction f of the way from a to b, rounded to
+// the nearest integer dimensions. Negative dimensions are clamped to 0.
+func LerpSize(a, b math.Size, f float32) math.Size {
+	return math.Size{W: lerpRound(a.W, b.W, f), H: lerpRound(a.H, b.H, f)}.Max(math.ZeroSize)
+}
+
+func lerpRound(a, b int, f float32) int {
+	return math.Round(math.Lerpf(float32(a), float32(b), f))
+}
@@ -0,0 +1,234 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// commandParams holds the union of the parameters of all commands.
+type commandParams struct {
+	Path     string                `json:"path"`
+	TestID   string                `json:"testId"`
+	Text     string                `json:"text"`
+	Key      string                `json:"key"`
+	Modifier gxui.KeyboardModifier `json:"modifier"`
+	Index    *int                  `json:"index"`
+}
+
+type command func(s *Server, p commandParams) (interface{}, error)
+
+var commands = map[string]command{
+	"tree":        (*Server).tree,
+	"click":       (*Server).click,
+	"doubleClick": (*Server).doubleClick,
+	"type":        (*Server).typeText,
+	"pressKey":    (*Server).pressKey,
+	"select":      (*Server).selectItem,
+	"screenshot":  (*Server).screenshot,
+}
+
+// Screenshot is the result of the screenshot command.
+type Screenshot struct {
+	PNG    []byte `json:"png"` // Encoded as base64 in JSON
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+func (s *Server) tree(p commandParams) (interface{}, error) {
+	nodes := make([]Node, len(s.windows))
+	for i, w := range s.windows {
+		nodes[i] = windowNode(strconv.Itoa(i), w)
+	}
+	return nodes, nil
+}
+
+func (s *Server) click(p commandParams) (interface{}, error) {
+	c, err := s.control(p)
+	if err != nil {
+		return nil, err
+	}
+	s.clock.Click()
+	gxui.ClickControl(c)
+	return nil, nil
+}
+
+func (s *Server) doubleClick(p commandParams) (interface{}, error) {
+	c, err := s.control(p)
+	if err != nil {
+		return nil, err
+	}
+	s.clock.Click()
+	gxui.DoubleClickControl(c)
+	return nil, nil
+}
+
+func (s *Server) typeText(p commandParams) (interface{}, error) {
+	w, err := s.focusTarget(p)
+	if err != nil {
+		return nil, err
+	}
+	gxui.TypeText(w, p.Text)
+	return nil, nil
+}
+
+func (s *Server) pressKey(p commandParams) (interface{}, error) {
+	key := gxui.KeyUnknown
+	for k := gxui.KeyUnknown + 1; k < gxui.KeyLast; k++ {
+		if strings.EqualFold(k.String(), p.Key) {
+			key = k
+			break
+		}
+	}
+	if key == gxui.KeyUnknown {
+		return nil, &rpcError{invalidParams, fmt.Sprintf("Unknown key '%s'", p.Key)}
+	}
+	w, err := s.focusTarget(p)
+	if err != nil {
+		return nil, err
+	}
+	gxui.PressKey(w, key, p.Modifier)
+	return nil, nil
+}
+
+func (s *Server) selectItem(p commandParams) (interface{}, error) {
+	c, err := s.control(p)
+	if err != nil {
+		return nil, err
+	}
+	adapter := listAdapter(c)
+	if adapter == nil {
+		return nil, fmt.Errorf("Control %T is not a list with an adapter", c)
+	}
+	if p.Index == nil {
+		return nil, &rpcError{invalidParams, "Missing index"}
+	}
+	if count := adapter.Count(); *p.Index < 0 || *p.Index >= count {
+		return nil, fmt.Errorf("Index %d is out of range for %d items", *p.Index, count)
+	}
+	switch l := c.(type) {
+	case gxui.List:
+		l.Select(adapter.ItemAt(*p.Index))
+	case gxui.DropDownList:
+		l.Select(adapter.ItemAt(*p.Index))
+	}
+	return nil, nil
+}
+
+func (s *Server) screenshot(p commandParams) (interface{}, error) {
+	w, c, err := s.target(p)
+	if err != nil {
+		return nil, err
+	}
+	rasterizer, ok := s.driver.(gxui.CanvasRasterizer)
+	if !ok {
+		return nil, fmt.Errorf("Driver %T does not support screenshots", s.driver)
+	}
+	var canvas gxui.Canvas
+	if c != nil {
+		canvas = c.Draw()
+	} else if d, ok := w.(interface {
+		Draw() gxui.Canvas
+	}); ok {
+		canvas = d.Draw()
+	} else {
+		return nil, fmt.Errorf("Window %T cannot be drawn", w)
+	}
+	if canvas == nil {
+		return nil, fmt.Errorf("Target has no area to draw")
+	}
+	img := rasterizer.RasterizeCanvas(canvas, w.Scale())
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	size := img.Bounds().Size()
+	return Screenshot{PNG: buf.Bytes(), Width: size.X, Height: size.Y}, nil
+}
+
+// target returns the window and control identified by p. The control is nil
+// if p identifies a window, or if p has no target, in which case the first
+// window is returned.
+func (s *Server) target(p commandParams) (gxui.Window, gxui.Control, error) {
+	switch {
+	case p.TestID != "":
+		for _, w := range s.windows {
+			if c := gxui.FindControlByTestID(w, p.TestID); c != nil {
+				return w, c, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("No control has the test identifier '%s'", p.TestID)
+
+	case p.Path != "":
+		parts := strings.Split(p.Path, "/")
+		i, err := strconv.Atoi(parts[0])
+		if err != nil || i < 0 || i >= len(s.windows) {
+			return nil, nil, fmt.Errorf("Path '%s' does not start with a window index", p.Path)
+		}
+		w := s.windows[i]
+		var parent gxui.Parent = w
+		var c gxui.Control
+		for _, part := range parts[1:] {
+			i, err := strconv.Atoi(part)
+			if parent == nil || err != nil || i < 0 || i >= len(parent.Children()) {
+				return nil, nil, fmt.Errorf("Path '%s' does not identify a control", p.Path)
+			}
+			c = parent.Children()[i].Control
+			parent, _ = c.(gxui.Parent)
+		}
+		return w, c, nil
+
+	default:
+		if len(s.windows) == 0 {
+			return nil, nil, fmt.Errorf("No windows have been added to the automation server")
+		}
+		return s.windows[0], nil, nil
+	}
+}
+
+// control returns the control identified by p, which must not be a window.
+func (s *Server) control(p commandParams) (gxui.Control, error) {
+	if p.Path == "" && p.TestID == "" {
+		return nil, &rpcError{invalidParams, "Missing path or testId"}
+	}
+	_, c, err := s.target(p)
+	if err == nil && c == nil {
+		err = fmt.Errorf("Path '%s' identifies a window, not a control", p.Path)
+	}
+	return c, err
+}
+
+// focusTarget gives focus to the control identified by p, if any, returning
+// its window.
+func (s *Server) focusTarget(p commandParams) (gxui.Window, error) {
+	w, c, err := s.target(p)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		f, ok := c.(gxui.Focusable)
+		if !ok || !f.IsFocusable() {
+			return nil, fmt.Errorf("Control %T cannot take focus", c)
+		}
+		w.SetFocus(f)
+	}
+	return w, nil
+}
+
+// bounds returns the bounds of the control c in the coordinates of window w.
+func bounds(c gxui.Control, w gxui.Window) Bounds {
+	r := c.Size().Rect()
+	a := gxui.ChildToParent(r.Min, c, w)
+	b := gxui.ChildToParent(r.Max, c, w)
+	return rectBounds(math.Rect{Min: a.Min(b), Max: a.Max(b)})
+}
@@ -0,0 +1,240 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package automation provides an opt-in JSON-RPC 2.0 endpoint for scripting
+// a running gxui application from another process, for example from a test
+// written in Python.
+//
+// A Server listens on a localhost TCP port or a Unix socket. Each connection
+// carries a stream of JSON-RPC requests and responses, one JSON object per
+// line. The supported methods are:
+//
+//	tree        {}                             returns the control tree of every window as a list of Nodes
+//	click       {target}                       clicks the center of the target control
+//	doubleClick {target}                       double-clicks the center of the target control
+//	type        {target?, "text"}              focuses the target, if given, then types text
+//	pressKey    {target?, "key", "modifier"?}  focuses the target, if given, then presses and releases key
+//	select      {target, "index"}              selects the item at index of the target List or DropDownList
+//	screenshot  {target?}                      returns {"png", "width", "height"} of the target or first window
+//
+// A target is identified by either {"path": "0/2/1"}, the Node.Path returned
+// by tree, or {"testId": "ok"}, a gxui.Testable identifier. Keys are named as
+// by gxui.KeyboardKey.String, such as "Enter" or "A", and modifiers are
+// gxui.KeyboardModifier values.
+//
+// Every command is run on the UI go-routine with Driver.Call.
+package automation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// JSON-RPC error codes.
+const (
+	parseError     = -32700
+	invalidRequest = -32600
+	methodNotFound = -32601
+	invalidParams  = -32602
+	commandFailed  = -32000
+)
+
+// Server is an automation endpoint serving the windows added with AddWindow.
+type Server struct {
+	driver   gxui.Driver
+	listener net.Listener
+	windows  []gxui.Window // Only accessed on the UI go-routine
+
+	// clock is the input clock of the windows, which is advanced before each
+	// automated click. Only used on the UI go-routine.
+	clock *gxui.InputClock
+
+	mutex  sync.Mutex
+	conns  map[net.Conn]bool
+	closed bool
+}
+
+// CreateServer starts an automation server for the application using driver,
+// listening with net.Listen on the given network and address, such as "tcp"
+// and "localhost:7070" or "unix" and "/tmp/app.sock". The server serves no
+// windows until they are added with AddWindow.
+func CreateServer(driver gxui.Driver, network, address string) (*Server, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		driver:   driver,
+		listener: listener,
+		clock:    gxui.CreateInputClock(nil),
+		conns:    make(map[net.Conn]bool),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// AddWindow makes the window w available to automation clients. The window is
+// removed when it closes. AddWindow replaces the input clock of w, so that
+// automated clicks are never mistaken for double-clicks. AddWindow must be
+// called on the UI go-routine.
+func (s *Server) AddWindow(w gxui.Window) {
+	s.driver.AssertUIGoroutine()
+	s.windows = append(s.windows, w)
+	w.SetInputClock(s.clock.Now)
+	w.OnClose(func() {
+		for i, o := range s.windows {
+			if o == w {
+				s.windows = append(s.windows[:i], s.windows[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// Close stops the server and closes all client connections.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+	return s.listener.Close()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mutex.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+
+	decoder := json.NewDecoder(bufio.NewReader(conn))
+	encoder := json.NewEncoder(conn)
+	for {
+		raw := json.RawMessage{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err != io.EOF {
+				// The stream cannot be resynchronized after invalid JSON.
+				encoder.Encode(response{
+					JSONRPC: "2.0",
+					Error:   &rpcError{parseError, err.Error()},
+					ID:      json.RawMessage("null"),
+				})
+			}
+			return
+		}
+		req := request{}
+		if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+			encoder.Encode(response{
+				JSONRPC: "2.0",
+				Error:   &rpcError{invalidRequest, "Invalid JSON-RPC 2.0 request"},
+				ID:      json.RawMessage("null"),
+			})
+			continue
+		}
+		result, err := s.handle(req.Method, req.Params)
+		if req.ID == nil {
+			continue // Notifications have no response
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID}
+		if err == nil {
+			resp.Result, err = json.Marshal(result)
+		}
+		if err != nil {
+			rerr, ok := err.(*rpcError)
+			if !ok {
+				rerr = &rpcError{commandFailed, err.Error()}
+			}
+			resp.Result, resp.Error = nil, rerr
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle runs the command method on the UI go-routine, returning its result.
+func (s *Server) handle(method string, params json.RawMessage) (result interface{}, err error) {
+	cmd, found := commands[method]
+	if !found {
+		return nil, &rpcError{methodNotFound, fmt.Sprintf("Unknown method '%s'", method)}
+	}
+	p := commandParams{}
+	if len(params) > 0 && string(params) != "null" {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{invalidParams, err.Error()}
+		}
+	}
+
+	done := make(chan struct{})
+	if !s.driver.Call(func() {
+		defer close(done)
+		defer func() {
+			// Commands on controls in an unexpected state may panic. Report
+			// this to the client rather than crashing the application.
+			if r := recover(); r != nil {
+				result, err = nil, fmt.Errorf("%v", r)
+			}
+		}()
+		result, err = cmd(s, p)
+	}) {
+		return nil, fmt.Errorf("Driver has been terminated")
+	}
+	<-done
+	return result, err
+}
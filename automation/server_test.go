@@ -0,0 +1,262 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/automation"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/dark"
+)
+
+type app struct {
+	driver  gxui.Driver
+	server  *automation.Server
+	clicks  int
+	textBox gxui.TextBox
+	list    gxui.List
+}
+
+// start runs an application with a button, a text box and a list, served by
+// an automation server listening on network and address. The application is
+// terminated at the end of the test.
+func start(t *testing.T, network, address string) *app {
+	a := &app{}
+	started := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		soft.StartDriver(func(driver gxui.Driver) {
+			a.driver = driver
+			theme := dark.CreateTheme(driver)
+
+			button := theme.CreateButton()
+			button.SetText("OK")
+			button.(gxui.Testable).SetTestID("ok")
+			button.OnClick(func(gxui.MouseEvent) { a.clicks++ })
+
+			a.textBox = theme.CreateTextBox()
+			a.textBox.(gxui.Testable).SetTestID("name")
+
+			adapter := gxui.CreateDefaultAdapter()
+			adapter.SetItems([]string{"red", "green", "blue"})
+			a.list = theme.CreateList()
+			a.list.SetAdapter(adapter)
+			a.list.(gxui.Testable).SetTestID("colors")
+
+			layout := theme.CreateLinearLayout()
+			layout.AddChild(button)
+			layout.AddChild(a.textBox)
+			layout.AddChild(a.list)
+			window := theme.CreateWindow(200, 200, "Automation")
+			window.AddChild(layout)
+
+			var err error
+			a.server, err = automation.CreateServer(driver, network, address)
+			if err == nil {
+				a.server.AddWindow(window)
+			}
+			started <- err
+		})
+		close(done)
+	}()
+	if err := <-started; err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() {
+		a.server.Close()
+		a.driver.Terminate()
+		<-done
+	})
+	return a
+}
+
+// clickCount returns the number of times the button has been clicked.
+func (a *app) clickCount() int {
+	var clicks int
+	a.driver.CallSync(func() { clicks = a.clicks })
+	return clicks
+}
+
+type rpcResponse struct {
+	Result json.RawMessage
+	Error  *struct {
+		Code    int
+		Message string
+	}
+	ID int
+}
+
+type client struct {
+	t       *testing.T
+	encoder *json.Encoder
+	decoder *json.Decoder
+	nextID  int
+}
+
+func connect(t *testing.T, a *app) *client {
+	conn, err := net.Dial(a.server.Addr().Network(), a.server.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &client{t: t, encoder: json.NewEncoder(conn), decoder: json.NewDecoder(conn)}
+}
+
+// call calls method, decoding the result into result if not nil. call fails
+// the test if the call returns an error.
+func (c *client) call(method string, params interface{}, result interface{}) {
+	c.t.Helper()
+	if resp := c.request(method, params); resp.Error != nil {
+		c.t.Fatalf("%s returned error %d: %s", method, resp.Error.Code, resp.Error.Message)
+	} else if result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			c.t.Fatalf("Invalid result of %s: %v", method, err)
+		}
+	}
+}
+
+func (c *client) request(method string, params interface{}) rpcResponse {
+	c.t.Helper()
+	c.nextID++
+	req := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params, "id": c.nextID}
+	if err := c.encoder.Encode(req); err != nil {
+		c.t.Fatalf("Failed to send request: %v", err)
+	}
+	resp := rpcResponse{}
+	if err := c.decoder.Decode(&resp); err != nil {
+		c.t.Fatalf("Failed to read response: %v", err)
+	}
+	if resp.ID != c.nextID {
+		c.t.Fatalf("Response id was %d, expected %d", resp.ID, c.nextID)
+	}
+	return resp
+}
+
+func find(nodes []automation.Node, testID string) *automation.Node {
+	for i := range nodes {
+		if nodes[i].TestID == testID {
+			return &nodes[i]
+		}
+		if n := find(nodes[i].Children, testID); n != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+func TestTree(t *testing.T) {
+	a := start(t, "tcp", "localhost:0")
+	c := connect(t, a)
+
+	tree := []automation.Node{}
+	c.call("tree", nil, &tree)
+	if len(tree) != 1 || tree[0].Path != "0" || tree[0].Text == nil || *tree[0].Text != "Automation" {
+		t.Fatalf("Tree was %+v, expected one window titled 'Automation'", tree)
+	}
+	ok := find(tree, "ok")
+	if ok == nil {
+		t.Fatalf("Button 'ok' was not found in the tree")
+	}
+	if ok.Text == nil || *ok.Text != "OK" || !ok.Visible || ok.Bounds.W == 0 || ok.Bounds.H == 0 {
+		t.Errorf("Button node was %+v, expected visible text 'OK' with an area", ok)
+	}
+	colors := find(tree, "colors")
+	if colors == nil || colors.Selected == nil || *colors.Selected != -1 {
+		t.Errorf("List node was %+v, expected no selection", colors)
+	}
+
+	// Paths can be used in place of test identifiers.
+	c.call("click", map[string]string{"path": ok.Path}, nil)
+	if clicks := a.clickCount(); clicks != 1 {
+		t.Errorf("Button was clicked %d times, expected 1", clicks)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	a := start(t, "tcp", "localhost:0")
+	c := connect(t, a)
+
+	c.call("click", map[string]string{"testId": "ok"}, nil)
+	c.call("click", map[string]string{"testId": "ok"}, nil)
+	if clicks := a.clickCount(); clicks != 2 {
+		t.Errorf("Button was clicked %d times, expected 2", clicks)
+	}
+
+	c.call("type", map[string]string{"testId": "name", "text": "abc"}, nil)
+	c.call("pressKey", map[string]string{"key": "Backspace"}, nil)
+	var text string
+	a.driver.CallSync(func() { text = a.textBox.Text() })
+	if text != "ab" {
+		t.Errorf("TextBox text was '%s', expected 'ab'", text)
+	}
+
+	c.call("select", map[string]interface{}{"testId": "colors", "index": 1}, nil)
+	var selected gxui.AdapterItem
+	a.driver.CallSync(func() { selected = a.list.Selected() })
+	if selected != "green" {
+		t.Errorf("Selected item was %v, expected green", selected)
+	}
+	tree := []automation.Node{}
+	c.call("tree", nil, &tree)
+	if n := find(tree, "name"); n == nil || !n.Focused || n.Text == nil || *n.Text != "ab" {
+		t.Errorf("TextBox node was %+v, expected focused with text 'ab'", n)
+	}
+	if n := find(tree, "colors"); n == nil || n.Selected == nil || *n.Selected != 1 {
+		t.Errorf("List node was %+v, expected item 1 selected", n)
+	}
+
+	shot := automation.Screenshot{}
+	c.call("screenshot", nil, &shot)
+	img, err := png.Decode(bytes.NewReader(shot.PNG))
+	if err != nil {
+		t.Fatalf("Screenshot is not a PNG: %v", err)
+	}
+	if s := img.Bounds().Size(); s.X != 200 || s.Y != 200 || shot.Width != 200 || shot.Height != 200 {
+		t.Errorf("Screenshot size was %v (%dx%d), expected 200x200", s, shot.Width, shot.Height)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	a := start(t, "tcp", "localhost:0")
+	c := connect(t, a)
+
+	for _, test := range []struct {
+		method string
+		params interface{}
+		code   int
+	}{
+		{"explode", nil, -32601},
+		{"click", nil, -32602},
+		{"click", map[string]string{"testId": "missing"}, -32000},
+		{"click", map[string]string{"path": "0/9/9"}, -32000},
+		{"select", map[string]interface{}{"testId": "ok", "index": 0}, -32000},
+		{"select", map[string]interface{}{"testId": "colors", "index": 3}, -32000},
+		{"pressKey", map[string]string{"key": "NoSuchKey"}, -32602},
+	} {
+		resp := c.request(test.method, test.params)
+		if resp.Error == nil || resp.Error.Code != test.code {
+			t.Errorf("%s %v returned %+v, expected error code %d", test.method, test.params, resp.Error, test.code)
+		}
+	}
+
+	// The connection is still usable after errors.
+	c.call("tree", nil, nil)
+}
+
+func TestUnixSocket(t *testing.T) {
+	a := start(t, "unix", filepath.Join(t.TempDir(), "automation.sock"))
+	c := connect(t, a)
+	c.call("click", map[string]string{"testId": "ok"}, nil)
+	if clicks := a.clickCount(); clicks != 1 {
+		t.Errorf("Button was clicked %d times, expected 1", clicks)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package automation
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Node describes a window or control in the result of the tree command.
+type Node struct {
+	// Path identifies the node in commands. It is the index of the window,
+	// followed by the index of each child down to the control, separated by
+	// slashes, such as "0/2/1".
+	Path string `json:"path"`
+
+	// TestID is the gxui.Testable identifier of the control, if it has one.
+	TestID string `json:"testId,omitempty"`
+
+	// Type is the Go type of the window or control.
+	Type string `json:"type"`
+
+	// Bounds is the area of the control in the coordinates of its window, in
+	// DIPs.
+	Bounds Bounds `json:"bounds"`
+
+	// Text is the text of controls with a Text method, or the window title.
+	Text *string `json:"text,omitempty"`
+
+	// Visible is true if the control and all of its ancestors are visible.
+	Visible bool `json:"visible"`
+
+	// Focused is true if the control has the focus of its window.
+	Focused bool `json:"focused"`
+
+	// Selected is the index of the selected item of a List or DropDownList,
+	// or -1 if no item is selected.
+	Selected *int `json:"selected,omitempty"`
+
+	Children []Node `json:"children,omitempty"`
+}
+
+// Bounds is a rectangle in DIPs.
+type Bounds struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+func rectBounds(r math.Rect) Bounds {
+	return Bounds{X: r.Min.X, Y: r.Min.Y, W: r.W(), H: r.H()}
+}
+
+// listAdapter returns the adapter of c if it is a List or DropDownList, or
+// nil.
+func listAdapter(c gxui.Control) gxui.ListAdapter {
+	switch l := c.(type) {
+	case gxui.List:
+		return l.Adapter()
+	case gxui.DropDownList:
+		return l.Adapter()
+	}
+	return nil
+}
+
+func windowNode(path string, w gxui.Window) Node {
+	title := w.Title()
+	return Node{
+		Path:     path,
+		Type:     fmt.Sprintf("%T", w),
+		Bounds:   rectBounds(w.Size().Rect()),
+		Text:     &title,
+		Visible:  true,
+		Children: childNodes(path, w, w, true),
+	}
+}
+
+func childNodes(path string, p gxui.Parent, w gxui.Window, visible bool) []Node {
+	children := p.Children()
+	if len(children) == 0 {
+		return nil
+	}
+	nodes := make([]Node, len(children))
+	for i, child := range children {
+		nodes[i] = controlNode(path+"/"+strconv.Itoa(i), child.Control, w, visible)
+	}
+	return nodes
+}
+
+func controlNode(path string, c gxui.Control, w gxui.Window, visible bool) Node {
+	n := Node{
+		Path:    path,
+		Type:    fmt.Sprintf("%T", c),
+		Bounds:  bounds(c, w),
+		Visible: visible && c.IsVisible(),
+	}
+	if t, ok := c.(gxui.Testable); ok {
+		n.TestID = t.TestID()
+	}
+	if t, ok := c.(gxui.Texter); ok {
+		text := t.Text()
+		n.Text = &text
+	}
+	if f, ok := c.(gxui.Focusable); ok {
+		n.Focused = f.HasFocus()
+	}
+	if adapter := listAdapter(c); adapter != nil {
+		index := -1
+		if item := c.(gxui.ItemSelector).Selected(); item != nil {
+			index = adapter.ItemIndex(item)
+		}
+		n.Selected = &index
+	}
+	if p, ok := c.(gxui.Parent); ok {
+		n.Children = childNodes(path, p, w, n.Visible)
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+// BlendMode is the way the colors of a layer are combined with the colors
+// beneath it when the layer is composited.
+type BlendMode int
+
+const (
+	// NormalBlend draws the layer over the colors beneath it.
+	NormalBlend BlendMode = iota
+
+	// MultiplyBlend multiplies the layer's colors with those beneath it,
+	// darkening the result.
+	MultiplyBlend
+
+	// ScreenBlend inverts, multiplies and inverts again the layer's colors
+	// and those beneath it, lightening the result.
+	ScreenBlend
+
+	// AdditiveBlend adds the layer's colors to those beneath it.
+	AdditiveBlend
+)
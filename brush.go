@@ -4,15 +4,210 @@
 
 package gxui
 
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
 var WhiteBrush = CreateBrush(White)
 var TransparentBrush = CreateBrush(Transparent)
 var BlackBrush = CreateBrush(Black)
 var DefaultBrush = WhiteBrush
 
+// Brush describes how the interior of a shape is filled. A Brush is either a
+// solid Color, a Gradient or a Pattern.
 type Brush struct {
+	// Color is the fill color of a solid brush. Gradient and pattern brushes
+	// hold an approximation of their overall color, used by Canvases that
+	// cannot draw gradients or patterns.
 	Color Color
+
+	// Gradient, if not nil, fills the shape with a color gradient.
+	Gradient *Gradient `json:",omitempty"`
+
+	// Pattern, if not nil and Gradient is nil, fills the shape with a tiled
+	// texture.
+	Pattern *Pattern `json:",omitempty"`
 }
 
 func CreateBrush(color Color) Brush {
-	return Brush{color}
+	return Brush{Color: color}
+}
+
+// CreateLinearGradientBrush returns a Brush with a gradient varying along the
+// line from start to end. start and end are in units of the bounds of the
+// filled shape, so (0, 0) to (0, 1) is a vertical gradient from the top of
+// the shape to the bottom.
+func CreateLinearGradientBrush(start, end math.Vec2, stops ...GradientStop) Brush {
+	return createGradientBrush(&Gradient{
+		Kind:  LinearGradient,
+		Start: start,
+		End:   end,
+		Stops: append([]GradientStop{}, stops...),
+	})
+}
+
+// CreateRadialGradientBrush returns a Brush with a gradient varying with the
+// distance from center, reaching the last stop at radius. center and radius
+// are in units of the bounds of the filled shape, so a radial gradient
+// stretches into an ellipse when filling a non-square shape.
+func CreateRadialGradientBrush(center math.Vec2, radius float32, stops ...GradientStop) Brush {
+	return createGradientBrush(&Gradient{
+		Kind:   RadialGradient,
+		Start:  center,
+		Radius: radius,
+		Stops:  append([]GradientStop{}, stops...),
+	})
+}
+
+func createGradientBrush(g *Gradient) Brush {
+	var sum Color
+	for _, s := range g.Stops {
+		sum = Color{sum.R + s.Color.R, sum.G + s.Color.G, sum.B + s.Color.B, sum.A + s.Color.A}
+	}
+	if n := float32(len(g.Stops)); n > 0 {
+		sum = Color{sum.R / n, sum.G / n, sum.B / n, sum.A / n}
+	}
+	return Brush{Color: sum, Gradient: g}
+}
+
+// CreatePatternBrush returns a Brush that tiles the texture t across the
+// shape, starting from the top-left of the shape's bounds. Each tile is drawn
+// at the texture's size in DIPs.
+func CreatePatternBrush(t Texture) Brush {
+	return Brush{Color: White, Pattern: &Pattern{Texture: t}}
+}
+
+// IsSolid returns true if the brush fills with the single color b.Color.
+func (b Brush) IsSolid() bool {
+	return b.Gradient == nil && b.Pattern == nil
+}
+
+// ColorAt returns the color of the brush at the point p, when filling a shape
+// with the given bounds. Points and bounds are in DIPs. ColorAt is intended
+// for software Canvas implementations.
+func (b Brush) ColorAt(p math.Vec2, bounds math.Rect) Color {
+	switch {
+	case b.Gradient != nil:
+		size := bounds.Size().Vec2()
+		if size.X == 0 || size.Y == 0 {
+			return b.Color
+		}
+		return b.Gradient.ColorAt(b.Gradient.Offset(p.Sub(bounds.Min.Vec2()).Div(size)))
+	case b.Pattern != nil && b.Pattern.Texture != nil:
+		return b.Pattern.ColorAt(p.Sub(bounds.Min.Vec2()))
+	default:
+		return b.Color
+	}
+}
+
+// GradientKind is the shape of a Gradient.
+type GradientKind int
+
+const (
+	// LinearGradient varies color along the line from Gradient.Start to
+	// Gradient.End.
+	LinearGradient GradientKind = iota
+
+	// RadialGradient varies color with the distance from Gradient.Start,
+	// reaching the last stop at Gradient.Radius.
+	RadialGradient
+)
+
+// GradientStop is a color at a position along a Gradient.
+type GradientStop struct {
+	// Offset is the position of the stop along the gradient, from 0 to 1.
+	Offset float32
+	Color  Color
+}
+
+// Gradient describes a color gradient. Positions are in units of the bounds
+// of the filled shape, where (0, 0) is the top-left and (1, 1) is the
+// bottom-right corner. Colors before the first stop and after the last stop
+// are those of the first and last stops.
+type Gradient struct {
+	Kind   GradientKind
+	Start  math.Vec2 // Start of a linear gradient, or center of a radial gradient
+	End    math.Vec2 // End of a linear gradient
+	Radius float32   // Radius of a radial gradient
+	Stops  []GradientStop
+}
+
+// Offset returns the position along the gradient, clamped to [0, 1], of the
+// point p in units of the filled shape's bounds.
+func (g *Gradient) Offset(p math.Vec2) float32 {
+	switch g.Kind {
+	case RadialGradient:
+		if g.Radius <= 0 {
+			return 1
+		}
+		return math.Saturate(p.Sub(g.Start).Len() / g.Radius)
+	default:
+		d := g.End.Sub(g.Start)
+		l := d.SqrLen()
+		if l == 0 {
+			return 1
+		}
+		return math.Saturate(p.Sub(g.Start).Dot(d) / l)
+	}
+}
+
+// ColorAt returns the color of the gradient at offset, linearly
+// interpolating between the surrounding stops.
+func (g *Gradient) ColorAt(offset float32) Color {
+	if len(g.Stops) == 0 {
+		return Transparent
+	}
+	prev := g.Stops[0]
+	if offset <= prev.Offset {
+		return prev.Color
+	}
+	for _, s := range g.Stops[1:] {
+		if offset <= s.Offset {
+			if s.Offset <= prev.Offset {
+				return s.Color
+			}
+			f := (offset - prev.Offset) / (s.Offset - prev.Offset)
+			return Color{
+				R: math.Lerpf(prev.Color.R, s.Color.R, f),
+				G: math.Lerpf(prev.Color.G, s.Color.G, f),
+				B: math.Lerpf(prev.Color.B, s.Color.B, f),
+				A: math.Lerpf(prev.Color.A, s.Color.A, f),
+			}
+		}
+		prev = s
+	}
+	return prev.Color
+}
+
+// Pattern is a texture tiled across a filled shape.
+type Pattern struct {
+	Texture Texture `json:"-"`
+}
+
+// ColorAt returns the color of the pattern at the point p, in DIPs relative
+// to the top-left of the first tile.
+func (p *Pattern) ColorAt(pt math.Vec2) Color {
+	size := p.Texture.Size()
+	pixels := p.Texture.SizePixels()
+	if size.W <= 0 || size.H <= 0 || pixels.W <= 0 || pixels.H <= 0 {
+		return Transparent
+	}
+	x := math.Mod(int(pt.X*float32(pixels.W)/float32(size.W)), pixels.W)
+	y := math.Mod(int(pt.Y*float32(pixels.H)/float32(size.H)), pixels.H)
+	if p.Texture.FlipY() {
+		y = pixels.H - 1 - y
+	}
+	img := p.Texture.Image()
+	b := img.Bounds()
+	r, g, bl, a := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+	if a == 0 {
+		return Transparent
+	}
+	// image/color values are alpha-premultiplied.
+	return Color{
+		R: float32(r) / float32(a),
+		G: float32(g) / float32(a),
+		B: float32(bl) / float32(a),
+		A: float32(a) / 0xffff,
+	}
 }
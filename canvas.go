@@ -13,7 +13,21 @@ type Canvas interface {
 	IsComplete() bool
 	Complete()
 	Push()
+
+	// PushTransform is like Push, but also transforms all following drawing,
+	// up to the matching Pop, by the 2D affine transform m. The transform is
+	// applied to local coordinates before any existing transform. Clip
+	// rectangles added under a transform that rotates or skews clip to the
+	// bounding rectangle of the transformed rectangle.
+	PushTransform(m math.Mat3)
 	Pop()
+
+	// PushLayer is like Push, but also draws all following drawing, up to the
+	// matching PopLayer, into an off-screen layer. PopLayer composites the
+	// layer onto the canvas beneath it with opacity, between 0 and 1, and the
+	// blend mode, so that overlapping drawing in the layer fades as a group.
+	PushLayer(opacity float32, blend BlendMode)
+	PopLayer()
 	AddClip(math.Rect)
 	Clear(Color)
 	DrawCanvas(c Canvas, position math.Point)
@@ -23,4 +37,8 @@ type Canvas interface {
 	DrawPolygon(Polygon, Pen, Brush)
 	DrawRect(math.Rect, Brush)
 	DrawRoundedRect(rect math.Rect, tl, tr, bl, br float32, p Pen, b Brush)
+
+	// DrawShadow draws the shadow s cast by the rectangle r, outside of r,
+	// and blurs the content already drawn inside r by s.BackdropBlur.
+	DrawShadow(r math.Rect, s Shadow)
 }
@@ -0,0 +1,104 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"sync"
+)
+
+// MIME types of the standard clipboard formats. Applications may use any
+// other MIME type, such as "application/x-myapp-shape", for their own data.
+const (
+	TextMimeType = "text/plain"
+	HTMLMimeType = "text/html"
+	PNGMimeType  = "image/png"
+)
+
+// ClipboardEntry is one format of the data held by a Clipboard.
+type ClipboardEntry struct {
+	MimeType string
+	Data     []byte
+}
+
+// Clipboard holds data copied by the user in one or more formats, such as
+// the rich text of a document together with its plain text. A Clipboard is
+// safe to use from any go-routine.
+type Clipboard interface {
+	// Formats returns the MIME types of the entries on the clipboard, in the
+	// order they were set.
+	Formats() []string
+
+	// Get returns the data of the entry with the MIME type, or false if the
+	// clipboard holds no entry of that type.
+	Get(mimeType string) (data []byte, ok bool)
+
+	// Set replaces the content of the clipboard with entries. Set with no
+	// entries clears the clipboard.
+	Set(entries ...ClipboardEntry)
+}
+
+// ClipboardText returns the TextMimeType entry of the clipboard c as a
+// string, or false if c holds no text.
+func ClipboardText(c Clipboard) (string, bool) {
+	data, ok := c.Get(TextMimeType)
+	return string(data), ok
+}
+
+// SetClipboardText replaces the content of the clipboard c with the single
+// TextMimeType entry str.
+func SetClipboardText(c Clipboard, str string) {
+	c.Set(ClipboardEntry{MimeType: TextMimeType, Data: []byte(str)})
+}
+
+// MemoryClipboard is a Clipboard held in memory, used by the drivers that
+// have no access to the clipboard of the operating system and by tests.
+type MemoryClipboard struct {
+	sync.Mutex
+	entries []ClipboardEntry
+}
+
+// CreateMemoryClipboard returns an empty MemoryClipboard.
+func CreateMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{}
+}
+
+// gxui.Clipboard compliance
+func (c *MemoryClipboard) Formats() []string {
+	c.Lock()
+	defer c.Unlock()
+	formats := make([]string, len(c.entries))
+	for i, e := range c.entries {
+		formats[i] = e.MimeType
+	}
+	return formats
+}
+
+func (c *MemoryClipboard) Get(mimeType string) ([]byte, bool) {
+	c.Lock()
+	defer c.Unlock()
+	for _, e := range c.entries {
+		if e.MimeType == mimeType {
+			return append([]byte(nil), e.Data...), true
+		}
+	}
+	return nil, false
+}
+
+func (c *MemoryClipboard) Set(entries ...ClipboardEntry) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries = make([]ClipboardEntry, 0, len(entries))
+	for _, e := range entries {
+		e.Data = append([]byte(nil), e.Data...)
+		for i, f := range c.entries {
+			if f.MimeType == e.MimeType {
+				// Later entries replace earlier ones of the same type.
+				c.entries = append(c.entries[:i], c.entries[i+1:]...)
+				break
+			}
+		}
+		c.entries = append(c.entries, e)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"fmt"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// CompositionEventType is the stage of an input method composition session.
+type CompositionEventType int
+
+const (
+	// CompositionStart begins a session. The Text of the event is the
+	// initial pre-edit text, which is usually empty.
+	CompositionStart CompositionEventType = iota
+
+	// CompositionUpdate replaces the pre-edit text of the session.
+	CompositionUpdate
+
+	// CompositionEnd ends the session. The Text of the event is the committed
+	// text, which replaces the pre-edit text, or is empty if the session was
+	// cancelled. Input methods that commit text with key-strokes instead end
+	// the session with an empty Text before the key-strokes.
+	CompositionEnd
+)
+
+func (t CompositionEventType) String() string {
+	switch t {
+	case CompositionStart:
+		return "Start"
+	case CompositionUpdate:
+		return "Update"
+	case CompositionEnd:
+		return "End"
+	default:
+		return fmt.Sprintf("CompositionEventType(%d)", int(t))
+	}
+}
+
+// CompositionAttribute is the conversion state of a rune of pre-edit text.
+type CompositionAttribute int
+
+const (
+	// CompositionInput runes are being typed and are not yet converted.
+	CompositionInput CompositionAttribute = iota
+
+	// CompositionTarget runes are the clause being converted, which the
+	// candidate window offers alternatives for.
+	CompositionTarget
+
+	// CompositionConverted runes are converted clauses other than the target.
+	CompositionConverted
+)
+
+// CompositionEvent is raised by input methods, such as those used to type
+// Chinese, Japanese and Korean, while the user composes text that is not yet
+// committed. Composition events are delivered to the focused Composer.
+type CompositionEvent struct {
+	Type CompositionEventType
+
+	// Text is the pre-edit text of CompositionStart and CompositionUpdate
+	// events, or the committed text of CompositionEnd events.
+	Text string
+
+	// Cursor is the index of the rune of Text the caret is displayed before.
+	Cursor int
+
+	// Attributes holds the attribute of each rune of Text. If Attributes is
+	// shorter than Text, the remaining runes are CompositionInput.
+	Attributes []CompositionAttribute
+}
+
+// Attribute returns the attribute of the rune of Text at index i.
+func (e CompositionEvent) Attribute(i int) CompositionAttribute {
+	if i < len(e.Attributes) {
+		return e.Attributes[i]
+	}
+	return CompositionInput
+}
+
+func (e CompositionEvent) String() string {
+	return fmt.Sprintf("%v %q cursor: %d", e.Type, e.Text, e.Cursor)
+}
+
+// Composer is the optional interface implemented by focusable Controls that
+// accept text from input methods. Controls that do not implement Composer
+// only receive the committed text, as key-strokes.
+type Composer interface {
+	// Composition is called when a composition event is raised while the
+	// control (or non-consuming child) has focus. If Composition returns
+	// true, then the event is consumed and the caret rectangle of the control
+	// is reported to the viewport.
+	Composition(ev CompositionEvent) (consume bool)
+
+	// CompositionRect returns the rectangle of the caret in the pre-edit text,
+	// local to the control. Input methods position their candidate windows
+	// next to this rectangle.
+	CompositionRect() math.Rect
+}
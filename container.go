@@ -14,6 +14,12 @@ import (
 type Child struct {
 	Control Control
 	Offset  math.Point
+
+	// Transform, if not nil, is a 2D affine transform applied to the child
+	// control about its origin, before it is offset. Points in the child's
+	// coordinates map to the parent as Offset + Transform(point). Transform
+	// is set with Container.SetChildTransform.
+	Transform *math.Mat3
 }
 
 type Parent interface {
@@ -29,6 +35,11 @@ type Container interface {
 	RemoveChild(child Control)
 	RemoveChildAt(index int)
 	RemoveAll()
+
+	// SetChildTransform sets the 2D affine transform of the child control c,
+	// or removes it if m is nil, and redraws the container.
+	SetChildTransform(c Control, m *math.Mat3)
+
 	Padding() math.Spacing
 	SetPadding(math.Spacing)
 }
@@ -38,11 +49,44 @@ func (c *Child) String() string {
 	return fmt.Sprintf("Type: %T, Bounds: %v", c.Control, c.Bounds())
 }
 
-// Bounds returns the Child bounds relative to the parent.
+// Bounds returns the Child bounds relative to the parent. If the child is
+// transformed, Bounds returns the smallest rectangle holding the transformed
+// child.
 func (c *Child) Bounds() math.Rect {
+	if c.Transform != nil {
+		return c.Matrix().TransformRect(c.Control.Size().Rect())
+	}
 	return c.Control.Size().Rect().Offset(c.Offset)
 }
 
+// Matrix returns the 2D affine transform from the child's coordinates to the
+// parent's, combining the Transform and the Offset.
+func (c *Child) Matrix() math.Mat3 {
+	m := math.CreateMat3Translate(c.Offset.Vec2())
+	if c.Transform != nil {
+		m = c.Transform.Mul(m)
+	}
+	return m
+}
+
+// ToParent converts the point p from the child's coordinates to the
+// parent's.
+func (c *Child) ToParent(p math.Point) math.Point {
+	if c.Transform == nil {
+		return p.Add(c.Offset)
+	}
+	return c.Matrix().Transform(p.Vec2()).Point()
+}
+
+// ToChild converts the point p from the parent's coordinates to the
+// child's.
+func (c *Child) ToChild(p math.Point) math.Point {
+	if c.Transform == nil {
+		return p.Sub(c.Offset)
+	}
+	return c.Matrix().Invert().Transform(p.Vec2()).Point()
+}
+
 // Layout sets the Child size and offset relative to the parent.
 // Layout should only be called by the Child's parent.
 func (c *Child) Layout(rect math.Rect) {
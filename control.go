@@ -59,6 +59,15 @@ type Control interface {
 	// SetVisible sets the visibility of the control.
 	SetVisible(bool)
 
+	// Opacity returns the opacity of the control, from 0 (fully transparent)
+	// to 1 (fully opaque).
+	Opacity() float32
+
+	// SetOpacity sets the opacity of the control. The control and all of its
+	// children are painted into a layer faded as a whole. A transparent
+	// control still receives input.
+	SetOpacity(float32)
+
 	// ContainsPoint returns true if the specified local-space point is considered
 	// within the control.
 	ContainsPoint(math.Point) bool
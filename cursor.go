@@ -0,0 +1,160 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// CursorShape is the shape of the mouse cursor.
+type CursorShape int
+
+const (
+	// DefaultCursor is the shape of a control that does not choose a cursor.
+	// The control displays the cursor of its parent, or ArrowCursor if no
+	// ancestor chooses a cursor.
+	DefaultCursor CursorShape = iota
+
+	// ArrowCursor is the regular arrow pointer.
+	ArrowCursor
+
+	// IBeamCursor is the text insertion cursor.
+	IBeamCursor
+
+	// HandCursor is the pointing hand, used over links and draggable items.
+	HandCursor
+
+	// ResizeNSCursor is a vertical, north-south resize arrow.
+	ResizeNSCursor
+
+	// ResizeEWCursor is a horizontal, east-west resize arrow.
+	ResizeEWCursor
+
+	// ResizeNWSECursor is a diagonal, north-west to south-east resize arrow.
+	ResizeNWSECursor
+
+	// MoveCursor is a four-way arrow.
+	MoveCursor
+
+	// WaitCursor indicates that the application is busy.
+	WaitCursor
+
+	// NotAllowedCursor indicates that an action, such as a drop, is not
+	// allowed.
+	NotAllowedCursor
+
+	// CustomCursor displays the Image of the Cursor.
+	CustomCursor
+)
+
+func (s CursorShape) String() string {
+	switch s {
+	case DefaultCursor:
+		return "Default"
+	case ArrowCursor:
+		return "Arrow"
+	case IBeamCursor:
+		return "IBeam"
+	case HandCursor:
+		return "Hand"
+	case ResizeNSCursor:
+		return "ResizeNS"
+	case ResizeEWCursor:
+		return "ResizeEW"
+	case ResizeNWSECursor:
+		return "ResizeNWSE"
+	case MoveCursor:
+		return "Move"
+	case WaitCursor:
+		return "Wait"
+	case NotAllowedCursor:
+		return "NotAllowed"
+	case CustomCursor:
+		return "Custom"
+	default:
+		return fmt.Sprintf("CursorShape(%d)", int(s))
+	}
+}
+
+// Cursor is the appearance of the mouse cursor: either one of the standard
+// shapes, displayed as drawn by the platform, or a custom image.
+type Cursor struct {
+	Shape CursorShape
+
+	// Image is the image of a CustomCursor, in pixels.
+	Image image.Image
+
+	// Hotspot is the point of Image, in pixels, that is at the position of
+	// the mouse.
+	Hotspot math.Point
+}
+
+// Equal returns true if c and o have the same shape and hotspot and display
+// the same image. Images are compared by identity, so an image that is not a
+// pointer, which has none, is never equal to another.
+// Cursors must not be compared with ==, which panics if Image is of a type
+// that is not comparable.
+func (c Cursor) Equal(o Cursor) bool {
+	if c.Shape != o.Shape || c.Hotspot != o.Hotspot {
+		return false
+	}
+	if c.Image == nil || o.Image == nil {
+		return c.Image == nil && o.Image == nil
+	}
+	t := reflect.TypeOf(c.Image)
+	return t.Kind() == reflect.Ptr && t == reflect.TypeOf(o.Image) && c.Image == o.Image
+}
+
+// CreateCursor returns the Cursor with the standard shape. CreateCursor
+// panics if shape is CustomCursor, see CreateCustomCursor.
+func CreateCursor(shape CursorShape) Cursor {
+	if shape == CustomCursor {
+		panic("CustomCursor requires an image. Use CreateCustomCursor")
+	}
+	return Cursor{Shape: shape}
+}
+
+// CreateCustomCursor returns a CustomCursor displaying img, with the point
+// hotspot of img at the position of the mouse.
+func CreateCustomCursor(img image.Image, hotspot math.Point) Cursor {
+	if img == nil {
+		panic("CreateCustomCursor requires an image")
+	}
+	if b := img.Bounds(); !image.Pt(hotspot.X, hotspot.Y).Add(b.Min).In(b) {
+		panic(fmt.Errorf("Cursor hotspot %v is outside of the image of size %v", hotspot, b.Size()))
+	}
+	return Cursor{Shape: CustomCursor, Image: img, Hotspot: hotspot}
+}
+
+// CursorOwner is the optional interface implemented by Controls that choose
+// the mouse cursor displayed while the mouse is over them. The cursor is
+// applied by the MouseController of the window when the mouse moves.
+type CursorOwner interface {
+	// Cursor returns the cursor of the control. A Shape of DefaultCursor
+	// displays the cursor of the control's parent.
+	Cursor() Cursor
+
+	// SetCursor sets the cursor of the control.
+	SetCursor(Cursor)
+}
+
+// ResolveCursor returns the cursor displayed over the controls cl, ordered
+// from the outermost to the innermost control as returned by
+// TopControlsUnder. The innermost CursorOwner with a Shape other than
+// DefaultCursor determines the cursor, which is ArrowCursor if there is none.
+func ResolveCursor(cl ControlPointList) Cursor {
+	for i := len(cl) - 1; i >= 0; i-- {
+		if o, ok := cl[i].C.(CursorOwner); ok {
+			if c := o.Cursor(); c.Shape != DefaultCursor {
+				return c
+			}
+		}
+	}
+	return CreateCursor(ArrowCursor)
+}
@@ -0,0 +1,38 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/robertt-smg/gxui/math"
+	test "github.com/robertt-smg/gxui/testing"
+)
+
+// sliceImage is an image.Image of a type that cannot be compared with ==.
+type sliceImage struct {
+	pix []color.Color
+}
+
+func (i sliceImage) ColorModel() color.Model { return color.RGBAModel }
+func (i sliceImage) Bounds() image.Rectangle { return image.Rect(0, 0, len(i.pix), 1) }
+func (i sliceImage) At(x, y int) color.Color { return i.pix[x] }
+
+func TestCursorEqual(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	s := sliceImage{pix: []color.Color{color.Black, color.White}}
+
+	test.AssertEquals(t, true, CreateCursor(IBeamCursor).Equal(CreateCursor(IBeamCursor)))
+	test.AssertEquals(t, false, CreateCursor(IBeamCursor).Equal(CreateCursor(ArrowCursor)))
+	test.AssertEquals(t, true, CreateCustomCursor(a, math.Point{X: 1, Y: 2}).Equal(CreateCustomCursor(a, math.Point{X: 1, Y: 2})))
+	test.AssertEquals(t, false, CreateCustomCursor(a, math.Point{X: 1, Y: 2}).Equal(CreateCustomCursor(a, math.Point{X: 2, Y: 1})))
+	test.AssertEquals(t, false, CreateCustomCursor(a, math.Point{}).Equal(CreateCustomCursor(b, math.Point{})))
+	test.AssertEquals(t, false, CreateCustomCursor(a, math.Point{}).Equal(CreateCursor(ArrowCursor)))
+	test.AssertEquals(t, false, CreateCustomCursor(s, math.Point{}).Equal(CreateCustomCursor(s, math.Point{})))
+	test.AssertEquals(t, false, CreateCustomCursor(s, math.Point{}).Equal(CreateCustomCursor(a, math.Point{})))
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Damager is the optional interface implemented by Windows that only redraw
+// the regions of the window that have changed since the last frame.
+type Damager interface {
+	// Damage marks the rectangle r, in window coordinates, as needing to be
+	// redrawn with the next frame.
+	Damage(r math.Rect)
+}
+
+// Damage marks the rectangle r, in the coordinates of the control c, as
+// needing to be redrawn by the window containing c. Damage does nothing if c
+// is not attached to a window, or if the window is not a Damager.
+func Damage(c Control, r math.Rect) {
+	for {
+		p := c.Parent()
+		if p == nil {
+			return
+		}
+		child := p.Children().Find(c)
+		if child == nil {
+			return // The control is being added or removed
+		}
+		if child.Transform != nil {
+			r = child.Matrix().TransformRect(r)
+		} else {
+			r = r.Offset(child.Offset)
+		}
+		if d, ok := p.(Damager); ok {
+			d.Damage(r)
+			return
+		}
+		control, ok := p.(Control)
+		if !ok {
+			return
+		}
+		c = control
+	}
+}
+
+// maxDamageRects is the number of damaged rectangles above which
+// DamagePixels merges the rectangles into their union.
+const maxDamageRects = 16
+
+// DamagePixels returns the rectangles, in the pixels of a viewport of the
+// given size, that need to be redrawn to cover the damaged rectangles in
+// DIPs. Each rectangle is grown by a pixel to cover antialiased edges.
+// DamagePixels is intended for Viewport implementations.
+func DamagePixels(damage []math.Rect, pixelsPerDip float32, size math.Size) []math.Rect {
+	bounds := size.Rect()
+	rects := make([]math.Rect, 0, len(damage))
+	for _, d := range damage {
+		r := math.CreateRect(
+			int(math.Floorf(float32(d.Min.X)*pixelsPerDip))-1,
+			int(math.Floorf(float32(d.Min.Y)*pixelsPerDip))-1,
+			int(math.Ceilf(float32(d.Max.X)*pixelsPerDip))+1,
+			int(math.Ceilf(float32(d.Max.Y)*pixelsPerDip))+1,
+		)
+		if r.Max.X <= bounds.Min.X || r.Max.Y <= bounds.Min.Y ||
+			r.Min.X >= bounds.Max.X || r.Min.Y >= bounds.Max.Y {
+			continue
+		}
+		rects = append(rects, r.Intersect(bounds))
+	}
+	if len(rects) > maxDamageRects {
+		u := rects[0]
+		for _, r := range rects[1:] {
+			u = u.Union(r)
+		}
+		rects = []math.Rect{u}
+	}
+	return rects
+}
+
+// RedrawStats holds counters of the frames presented by a Viewport, used to
+// measure the savings of redrawing only the damaged regions of a window.
+type RedrawStats struct {
+	// Frames is the number of canvases presented with SetCanvas or
+	// SetCanvasDamaged.
+	Frames int
+
+	// FullFrames is the number of frames that were redrawn entirely.
+	FullFrames int
+
+	// PixelsDrawn is the total number of pixels redrawn by all frames.
+	PixelsDrawn int64
+
+	// PixelsPresented is the total number of pixels of all frames. The ratio
+	// of PixelsDrawn to PixelsPresented is the fraction of the work done
+	// compared to redrawing every frame entirely.
+	PixelsPresented int64
+}
+
+// Add accumulates a frame of size pixels, of which drawn pixels were redrawn.
+func (s *RedrawStats) Add(size math.Size, drawn int, full bool) {
+	s.Frames++
+	if full {
+		s.FullFrames++
+	}
+	s.PixelsDrawn += int64(drawn)
+	s.PixelsPresented += int64(size.Area())
+}
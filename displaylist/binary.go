@@ -0,0 +1,476 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package displaylist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	gomath "math"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+const (
+	binaryMagic   = "GXDL"
+	binaryVersion = 3 // Version 2 added gradient and pattern brushes, 3 pen styles
+)
+
+// Brush kinds in the binary encoding.
+const (
+	brushSolid = iota
+	brushGradient
+	brushPattern
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The encoding is a magic header followed by the list, where integers are
+// encoded as varints, floats as little-endian IEEE 754 and each op is
+// prefixed with its kind.
+func (l List) MarshalBinary() ([]byte, error) {
+	e := &encoder{}
+	e.buf.WriteString(binaryMagic)
+	e.uvarint(binaryVersion)
+	if err := e.list(&l); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (l *List) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return errors.New("Data is not a binary display list")
+	}
+	d := &decoder{r: bytes.NewReader(data[len(binaryMagic):])}
+	d.version = d.uvarint()
+	if d.err == nil && (d.version < 1 || d.version > binaryVersion) {
+		return fmt.Errorf("Unsupported binary display list version %d", d.version)
+	}
+	d.list(l)
+	return d.err
+}
+
+type encoder struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf.Write(e.scratch[:n])
+}
+
+func (e *encoder) varint(v int) {
+	n := binary.PutVarint(e.scratch[:], int64(v))
+	e.buf.Write(e.scratch[:n])
+}
+
+func (e *encoder) float(v float32) {
+	binary.LittleEndian.PutUint32(e.scratch[:4], gomath.Float32bits(v))
+	e.buf.Write(e.scratch[:4])
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.buf.WriteByte(1)
+	} else {
+		e.buf.WriteByte(0)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
+
+func (e *encoder) point(p math.Point) {
+	e.varint(p.X)
+	e.varint(p.Y)
+}
+
+func (e *encoder) size(s math.Size) {
+	e.varint(s.W)
+	e.varint(s.H)
+}
+
+func (e *encoder) rect(r math.Rect) {
+	e.point(r.Min)
+	e.point(r.Max)
+}
+
+func (e *encoder) color(c gxui.Color) {
+	e.float(c.R)
+	e.float(c.G)
+	e.float(c.B)
+	e.float(c.A)
+}
+
+func (e *encoder) pen(p gxui.Pen) {
+	e.float(p.Width)
+	e.color(p.Color)
+	e.uvarint(uint64(p.Cap))
+	e.uvarint(uint64(p.Join))
+	if p.Dashes == nil {
+		e.bool(false)
+		return
+	}
+	e.bool(true)
+	e.float(p.DashOffset)
+	e.uvarint(uint64(len(*p.Dashes)))
+	for _, l := range *p.Dashes {
+		e.float(l)
+	}
+}
+
+func (e *encoder) brush(b gxui.Brush) {
+	e.color(b.Color)
+	switch {
+	case b.Gradient != nil:
+		g := b.Gradient
+		e.uvarint(brushGradient)
+		e.uvarint(uint64(g.Kind))
+		e.float(g.Start.X)
+		e.float(g.Start.Y)
+		e.float(g.End.X)
+		e.float(g.End.Y)
+		e.float(g.Radius)
+		e.uvarint(uint64(len(g.Stops)))
+		for _, s := range g.Stops {
+			e.float(s.Offset)
+			e.color(s.Color)
+		}
+	case b.Pattern != nil:
+		e.uvarint(brushPattern)
+	default:
+		e.uvarint(brushSolid)
+	}
+}
+
+func (e *encoder) shadow(s gxui.Shadow) {
+	e.color(s.Color)
+	e.point(s.Offset)
+	e.float(s.Spread)
+	e.float(s.Blur)
+	e.float(s.CornerRadius)
+	e.float(s.BackdropBlur)
+}
+
+func (e *encoder) polygon(p gxui.Polygon) {
+	e.uvarint(uint64(len(p)))
+	for _, v := range p {
+		e.point(v.Position)
+		e.float(v.RoundedRadius)
+	}
+}
+
+func (e *encoder) list(l *List) error {
+	e.size(l.Size)
+	e.uvarint(uint64(len(l.Ops)))
+	for _, op := range l.Ops {
+		if err := e.op(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) op(op Op) error {
+	kind, found := opKinds[op.Name()]
+	if !found {
+		return fmt.Errorf("Unknown display list op '%s'", op.Name())
+	}
+	e.uvarint(uint64(kind))
+	switch op := op.(type) {
+	case Push, Pop, PopLayer:
+	case PushTransform:
+		for _, v := range op.Transform {
+			e.float(v)
+		}
+	case PushLayer:
+		e.float(op.Opacity)
+		e.uvarint(uint64(op.Blend))
+	case AddClip:
+		e.rect(op.Rect)
+	case Clear:
+		e.color(op.Color)
+	case DrawCanvas:
+		e.point(op.Offset)
+		return e.list(op.Canvas)
+	case DrawTexture:
+		e.size(op.Texture.SizePixels)
+		e.bool(op.Texture.FlipY)
+		e.rect(op.Rect)
+	case DrawRunes:
+		e.string(op.Font.Family)
+		e.varint(op.Font.Size)
+		e.uvarint(uint64(len(op.Runes)))
+		for i, r := range op.Runes {
+			e.varint(int(r))
+			e.point(op.Points[i])
+		}
+		e.color(op.Color)
+	case DrawLines:
+		e.polygon(op.Lines)
+		e.pen(op.Pen)
+	case DrawPolygon:
+		e.polygon(op.Polygon)
+		e.pen(op.Pen)
+		e.brush(op.Brush)
+	case DrawRect:
+		e.rect(op.Rect)
+		e.brush(op.Brush)
+	case DrawRoundedRect:
+		e.rect(op.Rect)
+		e.float(op.TL)
+		e.float(op.TR)
+		e.float(op.BL)
+		e.float(op.BR)
+		e.pen(op.Pen)
+		e.brush(op.Brush)
+	case DrawShadow:
+		e.rect(op.Rect)
+		e.shadow(op.Shadow)
+	default:
+		return fmt.Errorf("Unsupported display list op %T", op)
+	}
+	return nil
+}
+
+// decoder reads the binary encoding. The first error encountered is held in
+// err, after which all reads return zero values.
+type decoder struct {
+	r       *bytes.Reader
+	err     error
+	version uint64
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *decoder) varint() int {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return int(v)
+}
+
+// count reads a length prefix, verifying it is no larger than the remaining
+// data so that corrupt input cannot cause huge allocations.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(d.r.Len()) {
+		d.err = fmt.Errorf("Invalid display list length %d", n)
+	}
+	if d.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) float() float32 {
+	if d.err != nil {
+		return 0
+	}
+	var b [4]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		d.err = err
+		return 0
+	}
+	return gomath.Float32frombits(binary.LittleEndian.Uint32(b[:]))
+}
+
+func (d *decoder) bool() bool {
+	if d.err != nil {
+		return false
+	}
+	b, err := d.r.ReadByte()
+	d.err = err
+	return b != 0
+}
+
+func (d *decoder) string() string {
+	b := make([]byte, d.count())
+	if _, err := io.ReadFull(d.r, b); err != nil && d.err == nil {
+		d.err = err
+	}
+	return string(b)
+}
+
+func (d *decoder) point() math.Point {
+	return math.Point{X: d.varint(), Y: d.varint()}
+}
+
+func (d *decoder) size() math.Size {
+	return math.Size{W: d.varint(), H: d.varint()}
+}
+
+func (d *decoder) rect() math.Rect {
+	return math.Rect{Min: d.point(), Max: d.point()}
+}
+
+func (d *decoder) color() gxui.Color {
+	return gxui.Color{R: d.float(), G: d.float(), B: d.float(), A: d.float()}
+}
+
+func (d *decoder) pen() gxui.Pen {
+	p := gxui.Pen{Width: d.float(), Color: d.color()}
+	if d.version < 3 {
+		return p
+	}
+	p.Cap = gxui.LineCap(d.uvarint())
+	p.Join = gxui.LineJoin(d.uvarint())
+	if d.bool() {
+		p.DashOffset = d.float()
+		dashes := make(gxui.DashPattern, d.count())
+		for i := range dashes {
+			dashes[i] = d.float()
+		}
+		p.Dashes = &dashes
+	}
+	return p
+}
+
+func (d *decoder) brush() gxui.Brush {
+	b := gxui.Brush{Color: d.color()}
+	if d.version < 2 {
+		return b
+	}
+	switch kind := d.uvarint(); kind {
+	case brushSolid:
+	case brushGradient:
+		g := &gxui.Gradient{
+			Kind:   gxui.GradientKind(d.uvarint()),
+			Start:  math.Vec2{X: d.float(), Y: d.float()},
+			End:    math.Vec2{X: d.float(), Y: d.float()},
+			Radius: d.float(),
+		}
+		g.Stops = make([]gxui.GradientStop, d.count())
+		for i := range g.Stops {
+			g.Stops[i] = gxui.GradientStop{Offset: d.float(), Color: d.color()}
+		}
+		b.Gradient = g
+	case brushPattern:
+		b.Pattern = &gxui.Pattern{}
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("Invalid brush kind %d", kind)
+		}
+	}
+	return b
+}
+
+func (d *decoder) shadow() gxui.Shadow {
+	return gxui.Shadow{
+		Color:        d.color(),
+		Offset:       d.point(),
+		Spread:       d.float(),
+		Blur:         d.float(),
+		CornerRadius: d.float(),
+		BackdropBlur: d.float(),
+	}
+}
+
+func (d *decoder) polygon() gxui.Polygon {
+	p := make(gxui.Polygon, d.count())
+	for i := range p {
+		p[i].Position = d.point()
+		p[i].RoundedRadius = d.float()
+	}
+	return p
+}
+
+func (d *decoder) list(l *List) {
+	l.Size = d.size()
+	l.Ops = make([]Op, d.count())
+	for i := range l.Ops {
+		l.Ops[i] = d.op()
+	}
+}
+
+func (d *decoder) op() Op {
+	kind := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if kind >= uint64(len(opTypes)) {
+		d.err = fmt.Errorf("Unknown display list op kind %d", kind)
+		return nil
+	}
+	switch opTypes[kind].(type) {
+	case Push:
+		return Push{}
+	case Pop:
+		return Pop{}
+	case PushTransform:
+		op := PushTransform{}
+		for i := range op.Transform {
+			op.Transform[i] = d.float()
+		}
+		return op
+	case PushLayer:
+		return PushLayer{Opacity: d.float(), Blend: gxui.BlendMode(d.uvarint())}
+	case PopLayer:
+		return PopLayer{}
+	case AddClip:
+		return AddClip{Rect: d.rect()}
+	case Clear:
+		return Clear{Color: d.color()}
+	case DrawCanvas:
+		op := DrawCanvas{Offset: d.point(), Canvas: &List{}}
+		d.list(op.Canvas)
+		return op
+	case DrawTexture:
+		op := DrawTexture{}
+		op.Texture.SizePixels = d.size()
+		op.Texture.FlipY = d.bool()
+		op.Rect = d.rect()
+		return op
+	case DrawRunes:
+		op := DrawRunes{}
+		op.Font.Family = d.string()
+		op.Font.Size = d.varint()
+		n := d.count()
+		op.Runes = make([]rune, n)
+		op.Points = make([]math.Point, n)
+		for i := 0; i < n; i++ {
+			op.Runes[i] = rune(d.varint())
+			op.Points[i] = d.point()
+		}
+		op.Color = d.color()
+		return op
+	case DrawLines:
+		return DrawLines{Lines: d.polygon(), Pen: d.pen()}
+	case DrawPolygon:
+		return DrawPolygon{Polygon: d.polygon(), Pen: d.pen(), Brush: d.brush()}
+	case DrawRect:
+		return DrawRect{Rect: d.rect(), Brush: d.brush()}
+	case DrawRoundedRect:
+		return DrawRoundedRect{
+			Rect:  d.rect(),
+			TL:    d.float(),
+			TR:    d.float(),
+			BL:    d.float(),
+			BR:    d.float(),
+			Pen:   d.pen(),
+			Brush: d.brush(),
+		}
+	case DrawShadow:
+		return DrawShadow{Rect: d.rect(), Shadow: d.shadow()}
+	}
+	panic(fmt.Errorf("Unhandled display list op %T", opTypes[kind]))
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package displaylist
+
+import (
+	"fmt"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Canvas is a gxui.Canvas that only records a display list. It can be used
+// to capture painting without a Driver, and by Drivers that defer all
+// rasterization.
+type Canvas struct {
+	list      List
+	built     bool
+	pushCount int
+}
+
+// NewCanvas returns a new recording Canvas of the given size.
+func NewCanvas(size math.Size) *Canvas {
+	if size.W <= 0 || size.H < 0 {
+		panic(fmt.Errorf("Canvas width and height must be positive. Size: %d", size))
+	}
+	return &Canvas{list: List{Size: size}}
+}
+
+func (c *Canvas) record(op Op) {
+	if c.built {
+		panic(fmt.Errorf("%s() called after Complete()", op.Name()))
+	}
+	c.list.Ops = append(c.list.Ops, op)
+}
+
+// Recorder compliance
+func (c *Canvas) DisplayList() *List {
+	return &c.list
+}
+
+// gxui.Canvas compliance
+func (c *Canvas) Size() math.Size {
+	return c.list.Size
+}
+
+func (c *Canvas) IsComplete() bool {
+	return c.built
+}
+
+func (c *Canvas) Complete() {
+	if c.built {
+		panic("Complete() called twice")
+	}
+	if c.pushCount != 0 {
+		panic(fmt.Errorf("Push() count was %d when calling Complete", c.pushCount))
+	}
+	c.built = true
+}
+
+func (c *Canvas) Push() {
+	c.pushCount++
+	c.record(Push{})
+}
+
+func (c *Canvas) PushTransform(m math.Mat3) {
+	c.pushCount++
+	c.record(PushTransform{Transform: m})
+}
+
+func (c *Canvas) Pop() {
+	c.pushCount--
+	c.record(Pop{})
+}
+
+func (c *Canvas) PushLayer(opacity float32, blend gxui.BlendMode) {
+	c.pushCount++
+	c.record(PushLayer{Opacity: opacity, Blend: blend})
+}
+
+func (c *Canvas) PopLayer() {
+	c.pushCount--
+	c.record(PopLayer{})
+}
+
+func (c *Canvas) AddClip(r math.Rect) {
+	c.record(AddClip{Rect: r})
+}
+
+func (c *Canvas) Clear(color gxui.Color) {
+	c.record(Clear{Color: color})
+}
+
+func (c *Canvas) DrawCanvas(cc gxui.Canvas, offset math.Point) {
+	if cc == nil {
+		panic("Canvas cannot be nil")
+	}
+	c.record(DrawCanvas{Canvas: FromCanvas(cc), Offset: offset})
+}
+
+func (c *Canvas) DrawTexture(t gxui.Texture, r math.Rect) {
+	if t == nil {
+		panic("Texture cannot be nil")
+	}
+	c.record(DrawTexture{Texture: CreateTextureRef(t), Rect: r})
+}
+
+func (c *Canvas) DrawRunes(f gxui.Font, r []rune, p []math.Point, col gxui.Color) {
+	if f == nil {
+		panic("Font cannot be nil")
+	}
+	c.record(DrawRunes{
+		Font:   CreateFontRef(f),
+		Runes:  append([]rune{}, r...),
+		Points: append([]math.Point{}, p...),
+		Color:  col,
+	})
+}
+
+func (c *Canvas) DrawLines(lines gxui.Polygon, pen gxui.Pen) {
+	c.record(DrawLines{Lines: append(gxui.Polygon{}, lines...), Pen: pen})
+}
+
+func (c *Canvas) DrawPolygon(poly gxui.Polygon, pen gxui.Pen, brush gxui.Brush) {
+	c.record(DrawPolygon{Polygon: append(gxui.Polygon{}, poly...), Pen: pen, Brush: brush})
+}
+
+func (c *Canvas) DrawRect(r math.Rect, brush gxui.Brush) {
+	c.record(DrawRect{Rect: r, Brush: brush})
+}
+
+func (c *Canvas) DrawRoundedRect(r math.Rect, tl, tr, bl, br float32, pen gxui.Pen, brush gxui.Brush) {
+	c.record(DrawRoundedRect{Rect: r, TL: tl, TR: tr, BL: bl, BR: br, Pen: pen, Brush: brush})
+}
+
+func (c *Canvas) DrawShadow(r math.Rect, s gxui.Shadow) {
+	c.record(DrawShadow{Rect: r, Shadow: s})
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package displaylist
+
+import "fmt"
+
+// Diff compares the display lists got and expected, returning a description
+// of each difference found. Nested canvases are compared op by op. Fonts and
+// textures are compared only by their serialized fields. Diff returns nil if
+// the lists are equivalent.
+func Diff(got, expected *List) []string {
+	return diff("", got, expected, nil)
+}
+
+func diff(path string, got, expected *List, diffs []string) []string {
+	if got.Size != expected.Size {
+		diffs = append(diffs, fmt.Sprintf("%sSize: got %v, expected %v", path, got.Size, expected.Size))
+	}
+	for i := 0; i < len(got.Ops) || i < len(expected.Ops); i++ {
+		opPath := fmt.Sprintf("%sOps[%d]", path, i)
+		switch {
+		case i >= len(expected.Ops):
+			diffs = append(diffs, fmt.Sprintf("%s: got unexpected %s", opPath, opString(got.Ops[i])))
+			continue
+		case i >= len(got.Ops):
+			diffs = append(diffs, fmt.Sprintf("%s: missing %s", opPath, opString(expected.Ops[i])))
+			continue
+		}
+		g, e := got.Ops[i], expected.Ops[i]
+		gc, gIsCanvas := g.(DrawCanvas)
+		ec, eIsCanvas := e.(DrawCanvas)
+		if gIsCanvas && eIsCanvas {
+			if gc.Offset != ec.Offset {
+				diffs = append(diffs, fmt.Sprintf("%s.Offset: got %v, expected %v", opPath, gc.Offset, ec.Offset))
+			}
+			diffs = diff(opPath+".Canvas.", gc.Canvas, ec.Canvas, diffs)
+			continue
+		}
+		if gs, es := opString(g), opString(e); gs != es {
+			diffs = append(diffs, fmt.Sprintf("%s: got %s, expected %s", opPath, gs, es))
+		}
+	}
+	return diffs
+}
+
+// opString returns the op encoded as it appears in a JSON display list.
+func opString(op Op) string {
+	b, err := marshalOp(op)
+	if err != nil {
+		return fmt.Sprintf("%s(%v)", op.Name(), err)
+	}
+	return string(b)
+}
@@ -0,0 +1,106 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package displaylist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type jsonList struct {
+	Size math.Size
+	Ops  []json.RawMessage
+}
+
+// MarshalJSON implements json.Marshaler. Each op is encoded as an object
+// with an "Op" field holding the op's name, followed by the op's fields.
+func (l List) MarshalJSON() ([]byte, error) {
+	jl := jsonList{Size: l.Size, Ops: make([]json.RawMessage, len(l.Ops))}
+	for i, op := range l.Ops {
+		b, err := marshalOp(op)
+		if err != nil {
+			return nil, err
+		}
+		jl.Ops[i] = b
+	}
+	return json.Marshal(jl)
+}
+
+func marshalOp(op Op) ([]byte, error) {
+	fields, err := json.Marshal(op)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `{"Op":%q`, op.Name())
+	if len(fields) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(fields[1:])
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *List) UnmarshalJSON(data []byte) error {
+	jl := jsonList{}
+	if err := json.Unmarshal(data, &jl); err != nil {
+		return err
+	}
+	l.Size = jl.Size
+	l.Ops = make([]Op, len(jl.Ops))
+	for i, raw := range jl.Ops {
+		header := struct{ Op string }{}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return err
+		}
+		op := newOp(header.Op)
+		if op == nil {
+			return fmt.Errorf("Unknown display list op '%s'", header.Op)
+		}
+		if err := json.Unmarshal(raw, op); err != nil {
+			return err
+		}
+		l.Ops[i] = reflect.ValueOf(op).Elem().Interface().(Op)
+	}
+	return nil
+}
+
+// jsonDrawRunes is the JSON form of DrawRunes, with the runes encoded as a
+// string for readability.
+type jsonDrawRunes struct {
+	Font   FontRef
+	Runes  string
+	Points []math.Point
+	Color  gxui.Color
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o DrawRunes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonDrawRunes{
+		Font:   o.Font,
+		Runes:  string(o.Runes),
+		Points: o.Points,
+		Color:  o.Color,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *DrawRunes) UnmarshalJSON(data []byte) error {
+	j := jsonDrawRunes{}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	o.Font = j.Font
+	o.Runes = []rune(j.Runes)
+	o.Points = j.Points
+	o.Color = j.Color
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package displaylist provides a serializable representation of the stream
+// of calls made to a gxui.Canvas.
+//
+// A display list can be encoded to JSON or to a compact binary form, and two
+// lists can be compared with Diff. This makes display lists suitable for
+// structural golden tests of control painting, which are far more stable than
+// pixel comparisons.
+package displaylist
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// List is the recorded sequence of operations made to a Canvas.
+type List struct {
+	Size math.Size
+	Ops  []Op
+}
+
+// Op is a single recorded Canvas operation.
+type Op interface {
+	// Name returns the name of the gxui.Canvas method that recorded the op.
+	Name() string
+}
+
+// Recorder is the interface implemented by Canvases that record a display
+// list as they are built.
+type Recorder interface {
+	// DisplayList returns the operations recorded by the canvas so far.
+	DisplayList() *List
+}
+
+// FromCanvas returns the display list recorded by the canvas c.
+// FromCanvas panics if c does not implement Recorder.
+func FromCanvas(c gxui.Canvas) *List {
+	r, ok := c.(Recorder)
+	if !ok {
+		panic(fmt.Errorf("Canvas %T does not record a display list", c))
+	}
+	return r.DisplayList()
+}
+
+// FontRef identifies the font used by a DrawRunes op. Only the family name
+// and size are serialized.
+type FontRef struct {
+	Family string
+	Size   int
+	Font   gxui.Font `json:"-"`
+}
+
+// CreateFontRef returns a FontRef for the font f. Family is only known if f
+// implements gxui.NamedFont.
+func CreateFontRef(f gxui.Font) FontRef {
+	ref := FontRef{Size: f.Size(), Font: f}
+	if n, ok := f.(gxui.NamedFont); ok {
+		ref.Family = n.Family()
+	}
+	return ref
+}
+
+// TextureRef identifies the texture used by a DrawTexture op. Only the size
+// and orientation of the texture are serialized.
+type TextureRef struct {
+	SizePixels math.Size
+	FlipY      bool
+	Texture    gxui.Texture `json:"-"`
+}
+
+// CreateTextureRef returns a TextureRef for the texture t.
+func CreateTextureRef(t gxui.Texture) TextureRef {
+	return TextureRef{SizePixels: t.SizePixels(), FlipY: t.FlipY(), Texture: t}
+}
+
+type Push struct{}
+
+// PushTransform is a Push that also transforms the following ops, up to the
+// matching Pop, by the 2D affine Transform.
+type PushTransform struct {
+	Transform math.Mat3
+}
+
+type Pop struct{}
+
+// PushLayer is a Push that also draws the following ops, up to the matching
+// PopLayer, into a layer composited with Opacity and Blend.
+type PushLayer struct {
+	Opacity float32
+	Blend   gxui.BlendMode
+}
+
+type PopLayer struct{}
+
+type DrawShadow struct {
+	Rect   math.Rect
+	Shadow gxui.Shadow
+}
+
+type AddClip struct {
+	Rect math.Rect
+}
+
+type Clear struct {
+	Color gxui.Color
+}
+
+type DrawCanvas struct {
+	Canvas *List
+	Offset math.Point
+}
+
+type DrawTexture struct {
+	Texture TextureRef
+	Rect    math.Rect
+}
+
+type DrawRunes struct {
+	Font   FontRef
+	Runes  []rune
+	Points []math.Point
+	Color  gxui.Color
+}
+
+type DrawLines struct {
+	Lines gxui.Polygon
+	Pen   gxui.Pen
+}
+
+type DrawPolygon struct {
+	Polygon gxui.Polygon
+	Pen     gxui.Pen
+	Brush   gxui.Brush
+}
+
+type DrawRect struct {
+	Rect  math.Rect
+	Brush gxui.Brush
+}
+
+type DrawRoundedRect struct {
+	Rect           math.Rect
+	TL, TR, BL, BR float32
+	Pen            gxui.Pen
+	Brush          gxui.Brush
+}
+
+func (Push) Name() string            { return "Push" }
+func (Pop) Name() string             { return "Pop" }
+func (AddClip) Name() string         { return "AddClip" }
+func (Clear) Name() string           { return "Clear" }
+func (DrawCanvas) Name() string      { return "DrawCanvas" }
+func (DrawTexture) Name() string     { return "DrawTexture" }
+func (DrawRunes) Name() string       { return "DrawRunes" }
+func (DrawLines) Name() string       { return "DrawLines" }
+func (DrawPolygon) Name() string     { return "DrawPolygon" }
+func (DrawRect) Name() string        { return "DrawRect" }
+func (DrawRoundedRect) Name() string { return "DrawRoundedRect" }
+func (PushTransform) Name() string   { return "PushTransform" }
+func (PushLayer) Name() string       { return "PushLayer" }
+func (PopLayer) Name() string        { return "PopLayer" }
+func (DrawShadow) Name() string      { return "DrawShadow" }
+
+// opTypes lists every op type. The index of each type is its binary
+// encoding, so new ops must only ever be appended.
+var opTypes = []Op{
+	Push{},
+	Pop{},
+	AddClip{},
+	Clear{},
+	DrawCanvas{},
+	DrawTexture{},
+	DrawRunes{},
+	DrawLines{},
+	DrawPolygon{},
+	DrawRect{},
+	DrawRoundedRect{},
+	PushTransform{},
+	PushLayer{},
+	PopLayer{},
+	DrawShadow{},
+}
+
+var opKinds = map[string]int{}
+
+func init() {
+	for i, op := range opTypes {
+		opKinds[op.Name()] = i
+	}
+}
+
+// newOp returns a pointer to a new zero op with the given name, or nil if
+// the name is unknown.
+func newOp(name string) interface{} {
+	kind, found := opKinds[name]
+	if !found {
+		return nil
+	}
+	return reflect.New(reflect.TypeOf(opTypes[kind])).Interface()
+}
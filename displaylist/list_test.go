@@ -0,0 +1,138 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package displaylist
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	test "github.com/robertt-smg/gxui/testing"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func testList() *List {
+	child := NewCanvas(math.Size{W: 10, H: 10})
+	child.DrawRect(math.CreateRect(1, 2, 3, 4), gxui.CreateBrush(gxui.Red))
+	child.DrawRect(math.CreateRect(0, 5, 10, 10), gxui.CreateLinearGradientBrush(
+		math.Vec2{X: 0, Y: 0}, math.Vec2{X: 0, Y: 1},
+		gxui.GradientStop{Offset: 0, Color: gxui.Gray20},
+		gxui.GradientStop{Offset: 1, Color: gxui.Gray10},
+	))
+	child.Complete()
+
+	c := NewCanvas(math.Size{W: 100, H: 50})
+	c.Clear(gxui.Gray10)
+	c.Push()
+	c.AddClip(math.CreateRect(0, 0, 50, 50))
+	c.DrawCanvas(child, math.Point{X: 5, Y: 6})
+	c.Pop()
+	c.DrawLines(gxui.Polygon{
+		{Position: math.Point{X: 0, Y: 0}},
+		{Position: math.Point{X: 10, Y: 10}, RoundedRadius: 2},
+	}, gxui.CreatePen(1.5, gxui.Blue))
+	c.DrawPolygon(gxui.Polygon{
+		{Position: math.Point{X: 0, Y: 0}},
+		{Position: math.Point{X: 10, Y: 0}},
+		{Position: math.Point{X: 5, Y: 5}},
+	}, gxui.DefaultPen, gxui.WhiteBrush)
+	dashed := gxui.CreateDashedPen(1, gxui.Gray40, 3, 1)
+	dashed.DashOffset, dashed.Cap, dashed.Join = 2, gxui.RoundCap, gxui.BevelJoin
+	c.PushTransform(math.CreateMat3Rotate(math.Pi / 2))
+	c.DrawRoundedRect(math.CreateRect(0, 0, 20, 10), 1, 2, 3, 4, dashed, gxui.TransparentBrush)
+	c.Pop()
+	c.PushLayer(0.5, gxui.MultiplyBlend)
+	c.DrawRect(math.CreateRect(20, 20, 30, 30), gxui.CreateBrush(gxui.Yellow))
+	c.PopLayer()
+	c.DrawShadow(math.CreateRect(5, 5, 25, 15), gxui.Shadow{
+		Color: gxui.Black, Offset: math.Point{X: 1, Y: 2}, Spread: 1, Blur: 4, CornerRadius: 3, BackdropBlur: 2,
+	})
+	c.list.Ops = append(c.list.Ops,
+		DrawRunes{
+			Font:   FontRef{Family: "Roboto", Size: 12},
+			Runes:  []rune("hi"),
+			Points: []math.Point{{X: 1, Y: 2}, {X: 8, Y: 2}},
+			Color:  gxui.Green,
+		},
+		DrawTexture{
+			Texture: TextureRef{SizePixels: math.Size{W: 16, H: 8}, FlipY: true},
+			Rect:    math.CreateRect(0, 0, 8, 4),
+		},
+	)
+	c.Complete()
+	return c.DisplayList()
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	l := testList()
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &List{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, l) {
+		t.Errorf("JSON round trip mismatch: %v", Diff(got, l))
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	l := &List{
+		Size: math.Size{W: 4, H: 2},
+		Ops: []Op{
+			Push{},
+			DrawRect{Rect: math.CreateRect(0, 0, 4, 2), Brush: gxui.CreateBrush(gxui.Black)},
+			Pop{},
+		},
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertEquals(t,
+		`{"Size":{"W":4,"H":2},"Ops":[{"Op":"Push"},`+
+			`{"Op":"DrawRect","Rect":{"Min":{"X":0,"Y":0},"Max":{"X":4,"Y":2}},"Brush":{"Color":{"R":0,"G":0,"B":0,"A":1}}},`+
+			`{"Op":"Pop"}]}`,
+		string(data))
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	l := testList()
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &List{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, l) {
+		t.Errorf("Binary round trip mismatch: %v", Diff(got, l))
+	}
+
+	// Truncated data must be reported, not panic.
+	if err := got.UnmarshalBinary(data[:len(data)-3]); err == nil {
+		t.Error("Expected an error decoding truncated data")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a, b := testList(), testList()
+	test.AssertEquals(t, 0, len(Diff(a, b)))
+
+	b.Ops[3].(DrawCanvas).Canvas.Ops[0] = DrawRect{
+		Rect:  math.CreateRect(1, 2, 3, 4),
+		Brush: gxui.CreateBrush(gxui.Blue),
+	}
+	b.Ops = b.Ops[:len(b.Ops)-1]
+	diffs := Diff(a, b)
+	test.AssertEquals(t, 2, len(diffs))
+	test.AssertEquals(t, "Ops[3].Canvas.Ops[0]", diffs[0][:len("Ops[3].Canvas.Ops[0]")])
+	test.AssertEquals(t, "Ops[15]: got unexpected", diffs[1][:len("Ops[15]: got unexpected")])
+}
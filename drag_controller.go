@@ -0,0 +1,227 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// DragController runs the drag-and-drop operations of a window. Drags are
+// started from DragSources by the MouseController of the window, and can be
+// cancelled by pressing Escape.
+type DragController struct {
+	window  Window
+	mouse   *MouseController
+	overlay BubbleOverlay
+	pressed bool       // True while the left button is down and a drag may start
+	pressAt math.Point // The window point the left button was pressed at
+	ended   bool       // True if a drag ended while the left button is down
+	drag    *drag      // The current drag, or nil
+}
+
+type drag struct {
+	source   DragSource
+	control  Control
+	payload  DragPayload
+	image    Control
+	over     ControlPointList // The DropTargets under the mouse
+	target   Control          // The DropTarget accepting the payload, or nil
+	point    math.Point       // The last window point of the mouse
+	modifier KeyboardModifier
+}
+
+// CreateDragController returns a DragController for the window w, which
+// receives the mouse input of the MouseController m.
+func CreateDragController(w Window, m *MouseController) *DragController {
+	d := &DragController{window: w, mouse: m}
+	m.drag = d
+	w.OnKeyDown(func(ev KeyboardEvent) {
+		if ev.Key == KeyEscape {
+			d.Cancel()
+		}
+	})
+	return d
+}
+
+// SetOverlay sets the overlay used to display the drag images next to the
+// cursor. The overlay is added to the window for the duration of each drag.
+// If overlay is nil, drag images are not displayed.
+func (d *DragController) SetOverlay(overlay BubbleOverlay) {
+	d.overlay = overlay
+}
+
+// Dragging returns true if a drag is in progress.
+func (d *DragController) Dragging() bool {
+	return d.drag != nil
+}
+
+// Cancel cancels the drag in progress, if any. The payload is not dropped.
+func (d *DragController) Cancel() {
+	if d.drag == nil {
+		return
+	}
+	for _, cp := range d.drag.over {
+		cp.C.(DropTarget).DragLeave(d.event(cp.P, d.drag.point))
+	}
+	d.end(false)
+	d.mouse.updateCursor()
+}
+
+func (d *DragController) mouseDown(ev MouseEvent) {
+	if ev.Button == MouseButtonLeft {
+		d.pressed = true
+		d.pressAt = ev.Point
+	}
+}
+
+// mouseMove starts or updates the drag, where down are the controls the left
+// button was pressed over.
+func (d *DragController) mouseMove(ev MouseEvent, down ControlPointList) {
+	if d.drag == nil {
+		if !d.pressed || ev.Point.Sub(d.pressAt).Len() < DragThreshold {
+			return
+		}
+		d.pressed = false // Only attempt to start one drag per press
+		if !d.start(ev, down) {
+			return
+		}
+	}
+	d.update(ev)
+}
+
+// mouseUp drops the payload of the drag, returning true if the release of the
+// button ends a drag and should not be treated as a click. A drag released
+// over no DropTarget accepting its payload falls through to a click.
+func (d *DragController) mouseUp(ev MouseEvent) bool {
+	if ev.Button != MouseButtonLeft {
+		return false
+	}
+	d.pressed = false
+	if d.drag == nil {
+		ended := d.ended
+		d.ended = false
+		return ended
+	}
+	d.update(ev)
+	for _, cp := range d.drag.over {
+		if cp.C == d.drag.target {
+			cp.C.(DropTarget).Drop(d.event(cp.P, ev.Point))
+		} else {
+			cp.C.(DropTarget).DragLeave(d.event(cp.P, ev.Point))
+		}
+	}
+	dropped := d.drag.target != nil
+	d.end(dropped)
+	d.ended = false
+	return dropped
+}
+
+// cursor returns the cursor displayed during the drag.
+func (d *DragController) cursor() Cursor {
+	if d.drag.target == nil {
+		return CreateCursor(NotAllowedCursor)
+	}
+	return CreateCursor(ArrowCursor)
+}
+
+func (d *DragController) start(ev MouseEvent, down ControlPointList) bool {
+	for i := len(down) - 1; i >= 0; i-- {
+		s, ok := down[i].C.(DragSource)
+		if !ok {
+			continue
+		}
+		e := ev
+		e.Point = down[i].P
+		e.WindowPoint = d.pressAt
+		e.Window = d.window
+		if payload, image, ok := s.DragStart(e); ok {
+			d.drag = &drag{
+				source:  s,
+				control: down[i].C,
+				payload: payload,
+				image:   image,
+			}
+			if d.overlay != nil && image != nil {
+				d.window.AddChild(d.overlay)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DragController) update(ev MouseEvent) {
+	d.drag.point = ev.Point
+	d.drag.modifier = ev.Modifier
+	now := ControlPointList{}
+	for _, cp := range d.controlsUnder(ev.Point) {
+		if _, ok := cp.C.(DropTarget); ok {
+			now = append(now, cp)
+		}
+	}
+	for _, cp := range d.drag.over {
+		if !now.Contains(cp.C) {
+			cp.C.(DropTarget).DragLeave(d.event(cp.P, ev.Point))
+		}
+	}
+	for _, cp := range now {
+		if !d.drag.over.Contains(cp.C) {
+			cp.C.(DropTarget).DragEnter(d.event(cp.P, ev.Point))
+		}
+	}
+	d.drag.over = now
+	d.drag.target = nil
+	for i := len(now) - 1; i >= 0; i-- {
+		if now[i].C.(DropTarget).DragOver(d.event(now[i].P, ev.Point)) {
+			d.drag.target = now[i].C
+			break
+		}
+	}
+	if d.overlay != nil && d.drag.image != nil {
+		d.overlay.Show(d.drag.image, ev.Point)
+	}
+}
+
+func (d *DragController) end(dropped bool) {
+	drag := d.drag
+	d.drag = nil
+	d.ended = true
+	if d.overlay != nil && drag.image != nil {
+		d.overlay.Hide()
+		d.window.RemoveChild(d.overlay)
+	}
+	drag.source.DragEnd(dropped)
+}
+
+func (d *DragController) event(p, windowPoint math.Point) DragEvent {
+	return DragEvent{
+		Payload:     d.drag.payload,
+		Source:      d.drag.control,
+		Point:       p,
+		WindowPoint: windowPoint,
+		Window:      d.window,
+		Modifier:    d.drag.modifier,
+	}
+}
+
+// controlsUnder is TopControlsUnder, ignoring the drag image overlay.
+func (d *DragController) controlsUnder(p math.Point) ControlPointList {
+	children := d.window.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		if d.overlay != nil && child.Control == Control(d.overlay) {
+			continue
+		}
+		cp := child.ToChild(p)
+		if child.Control.ContainsPoint(cp) {
+			l := ControlPointList{ControlPoint{child.Control, cp}}
+			if cc, ok := child.Control.(Parent); ok {
+				l = append(l, TopControlsUnder(cp, cc)...)
+			}
+			return l
+		}
+	}
+	return ControlPointList{}
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// DragThreshold is the distance in DIPs the mouse has to move with the left
+// button held down before a drag is started.
+const DragThreshold = 5
+
+// Payload types of the drags started by the standard controls.
+const (
+	// PanelDragType payloads hold a PanelDrag, and are dragged from the tabs
+	// of a PanelHolder.
+	PanelDragType = "gxui/panel"
+
+	// ItemDragType payloads hold an ItemDrag, and are dragged from the items
+	// of a List or Tree.
+	ItemDragType = "gxui/item"
+)
+
+// DragPayload is the data carried by a drag-and-drop operation.
+type DragPayload struct {
+	// Type identifies the kind of Data, so that drop targets can accept only
+	// the payloads they understand.
+	Type string
+	Data interface{}
+}
+
+// PanelDrag is the Data of a PanelDragType payload.
+type PanelDrag struct {
+	Holder PanelHolder
+	Panel  Control
+	Name   string
+}
+
+// ItemDrag is the Data of an ItemDragType payload.
+type ItemDrag struct {
+	Source Control
+	Item   AdapterItem
+}
+
+// DragEvent is the event passed to DropTargets during a drag.
+type DragEvent struct {
+	Payload     DragPayload
+	Source      Control    // The control that started the drag
+	Point       math.Point // Local to the event receiver
+	WindowPoint math.Point
+	Window      Window
+	Modifier    KeyboardModifier
+}
+
+// DragSource is the optional interface implemented by Controls that start
+// drags. When the mouse is dragged with the left button held down, the
+// innermost DragSource under the point the button was pressed at that
+// returns true from DragStart starts the drag.
+type DragSource interface {
+	// DragStart is called when a drag begins over the control, where
+	// ev.Point is the point the button was pressed at. DragStart returns the
+	// payload of the drag and the image displayed next to the cursor, which
+	// may be nil, or false if no drag can start at ev.Point.
+	DragStart(ev MouseEvent) (payload DragPayload, image Control, ok bool)
+
+	// DragEnd is called when a drag started by the control ends. dropped is
+	// true if the payload was dropped on a DropTarget, or false if the drag
+	// was released over no target or cancelled with Escape.
+	DragEnd(dropped bool)
+}
+
+// DropTarget is the optional interface implemented by Controls that accept
+// dropped payloads. Like MouseEnter and MouseExit, DragEnter and DragLeave
+// are called for every DropTarget under the mouse, whether or not it accepts
+// the payload.
+type DropTarget interface {
+	// DragEnter is called when a drag enters the control.
+	DragEnter(ev DragEvent)
+
+	// DragOver is called when a drag moves over the control, starting with
+	// the innermost DropTarget under the mouse, until one returns true to
+	// accept dropping the payload at ev.Point.
+	DragOver(ev DragEvent) bool
+
+	// DragLeave is called when a drag leaves the control, is dropped on
+	// another target, or is cancelled.
+	DragLeave(ev DragEvent)
+
+	// Drop is called when the drag is released over the control, if the
+	// control accepted the payload with the last call to DragOver.
+	Drop(ev DragEvent)
+}
+
+// ItemDragSource is the interface implemented by Lists and Trees, whose items
+// can be dragged.
+type ItemDragSource interface {
+	DragSource
+
+	// ItemDragEnabled returns true if the items can be dragged.
+	ItemDragEnabled() bool
+
+	// SetItemDragEnabled sets whether the items can be dragged, which is the
+	// default. A drag that is not dropped, including an item released over
+	// itself, is released as a click.
+	SetItemDragEnabled(enabled bool)
+}
+
+// ItemDropTarget is the interface implemented by Lists and Trees, which
+// accept the payloads passed by their drop filter on their items.
+type ItemDropTarget interface {
+	DropTarget
+
+	// SetDropFilter sets the function returning true if payload can be
+	// dropped on item, which is nil between items. A nil filter, the
+	// default, accepts no drops.
+	SetDropFilter(f func(payload DragPayload, item AdapterItem) bool)
+
+	// OnItemDrop subscribes f to be called when a payload is dropped on item,
+	// which is nil between items.
+	OnItemDrop(f func(ev DragEvent, item AdapterItem)) EventSubscription
+}
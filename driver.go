@@ -6,6 +6,7 @@ package gxui
 
 import (
 	"image"
+	"time"
 
 	"github.com/robertt-smg/gxui/math"
 )
@@ -19,13 +20,27 @@ type Driver interface {
 	// case f may not be called.
 	Call(f func()) bool
 
+	// CallWhenIdle queues f to be run on the UI go-routine once no other calls
+	// are queued, including those queued by the calls that run before f.
+	// CallWhenIdle returns false if the driver has been terminated, in which
+	// case f may not be called.
+	CallWhenIdle(f func()) bool
+
 	// CallSync queues and then blocks for f to be run on the UI go-routine.
 	// Call returns false if the driver has been terminated, in which case f may
 	// not be called.
 	CallSync(f func()) bool
 
 	Terminate()
+
+	// Clipboard returns the clipboard shared by the viewports of the driver.
+	Clipboard() Clipboard
+
+	// SetClipboard replaces the content of the clipboard with the text str.
 	SetClipboard(str string)
+
+	// GetClipboard returns the text held by the clipboard, or an empty string
+	// if the clipboard holds no text.
 	GetClipboard() (string, error)
 
 	// CreateFont loads a font from the provided TrueType bytes.
@@ -43,6 +58,16 @@ type Driver interface {
 	CreateCanvas(math.Size) Canvas
 	CreateTexture(img image.Image, pixelsPerDip float32) Texture
 
+	// OnFrame subscribes f to be called on the UI go-routine once per frame,
+	// for as long as the subscription is held. t is the time of the frame,
+	// measured by a monotonic clock from the start of the driver. Frames start
+	// after the viewports present the previous frame, or at a steady rate when
+	// nothing is presented. OnFrame must be called on the UI go-routine.
+	OnFrame(f func(t time.Duration)) EventSubscription
+
+	// Stats returns the rendering statistics of the driver.
+	Stats() DriverStats
+
 	// Debug function used to verify that the caller is executing on the UI
 	// goroutine.  If this method is called outside of the UI goroutine
 	// *and* Debug() == true, then it will panic; otherwise it does nothing.
@@ -0,0 +1,124 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FrameTimeBuckets holds the upper bounds of the buckets of the
+// DriverStats.FrameTimes histogram. The last bucket of the histogram counts
+// the frames slower than the last bound.
+var FrameTimeBuckets = [...]time.Duration{
+	4 * time.Millisecond,
+	8 * time.Millisecond,
+	16 * time.Millisecond,
+	33 * time.Millisecond,
+	66 * time.Millisecond,
+}
+
+// FrameTimeHistogram counts frames by the time taken to draw them, using the
+// buckets of FrameTimeBuckets.
+type FrameTimeHistogram [len(FrameTimeBuckets) + 1]int
+
+// Add counts a frame that took d to draw.
+func (h *FrameTimeHistogram) Add(d time.Duration) {
+	for i, b := range FrameTimeBuckets {
+		if d <= b {
+			h[i]++
+			return
+		}
+	}
+	h[len(FrameTimeBuckets)]++
+}
+
+// DriverStats holds the rendering statistics of a Driver, accumulated over
+// the frames drawn by all of its viewports.
+type DriverStats struct {
+	// Frames is the number of frames drawn.
+	Frames int
+
+	// FrameTimes is the histogram of the times taken to draw each frame.
+	FrameTimes FrameTimeHistogram
+
+	// LastFrameTime is the time taken to draw the most recent frame.
+	LastFrameTime time.Duration
+
+	// MaxFrameTime is the longest time taken to draw a frame.
+	MaxFrameTime time.Duration
+
+	// TotalFrameTime is the time taken to draw all of the frames.
+	TotalFrameTime time.Duration
+
+	// DrawCalls is the number of draw calls made by the most recent frame.
+	// Drivers that do not use a GPU report 0.
+	DrawCalls int
+
+	// GlyphPages is the number of textures holding rasterized font glyphs.
+	GlyphPages int
+
+	// Textures is the number of textures held by the driver, and
+	// TextureBytes is an estimate of the memory they use.
+	Textures     int
+	TextureBytes int64
+
+	// CanvasOps holds the number of canvas operations of each kind, keyed by
+	// the operation name, executed by the most recent frame.
+	CanvasOps map[string]int
+}
+
+// AddFrame accumulates a frame that took d to draw.
+func (s *DriverStats) AddFrame(d time.Duration) {
+	s.Frames++
+	s.FrameTimes.Add(d)
+	s.LastFrameTime = d
+	s.TotalFrameTime += d
+	if d > s.MaxFrameTime {
+		s.MaxFrameTime = d
+	}
+}
+
+// AverageFrameTime returns the mean time taken to draw a frame.
+func (s DriverStats) AverageFrameTime() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.TotalFrameTime / time.Duration(s.Frames)
+}
+
+// String returns a multi-line summary of the statistics.
+func (s DriverStats) String() string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "Frames: %d\n", s.Frames)
+	fmt.Fprintf(buffer, "Frame time: %v avg %v max %v\n",
+		s.LastFrameTime.Round(time.Microsecond),
+		s.AverageFrameTime().Round(time.Microsecond),
+		s.MaxFrameTime.Round(time.Microsecond))
+	fmt.Fprintf(buffer, "Frame times:")
+	for i, n := range s.FrameTimes {
+		if i < len(FrameTimeBuckets) {
+			fmt.Fprintf(buffer, " <=%v:%d", FrameTimeBuckets[i], n)
+		} else {
+			fmt.Fprintf(buffer, " >%v:%d", FrameTimeBuckets[i-1], n)
+		}
+	}
+	fmt.Fprintf(buffer, "\nDraw calls: %d\n", s.DrawCalls)
+	fmt.Fprintf(buffer, "Glyph pages: %d\n", s.GlyphPages)
+	fmt.Fprintf(buffer, "Textures: %d (%.1f KB)\n", s.Textures, float64(s.TextureBytes)/1024)
+	names := make([]string, 0, len(s.CanvasOps))
+	for name := range s.CanvasOps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(buffer, "Canvas ops:")
+	for _, name := range names {
+		fmt.Fprintf(buffer, " %s:%d", name, s.CanvasOps[name])
+	}
+	fmt.Fprintf(buffer, "\n")
+	return buffer.String()
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fake contains a minimal implementation of the gxui.Driver
+// interface for automated UI tests. It requires no GPU or display and
+// rasterizes nothing: canvases record display lists, fonts have fixed glyph
+// metrics independent of the font data, and the clipboard is held in memory.
+// Input is sent to windows with the gxui.InputInjector methods and helpers
+// such as gxui.ClickControl.
+package fake
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Size of the virtual screen used by fullscreen viewports created with a
+// width or height of 0.
+var defaultScreenSize = math.Size{W: 1920, H: 1080}
+
+// An Opt is a type which modifies the Driver, usually during setup.
+type Opt = headless.Opt
+
+// An OptFunc is an Opt that doesn't carry any state.
+type OptFunc = headless.OptFunc
+
+// Debug is an Opt that sets d to debug mode (so that d.Debug() == true).
+func Debug() Opt {
+	return headless.Debug()
+}
+
+type driver struct {
+	headless.Driver
+}
+
+// StartDriver starts the fake driver with the given appRoutine.
+// StartDriver blocks until the driver is terminated.
+func StartDriver(appRoutine func(driver gxui.Driver), opts ...Opt) {
+	d := &driver{}
+	d.Init(nil)
+	for _, opt := range opts {
+		d = opt.Apply(d).(*driver)
+	}
+	d.Run(func() { appRoutine(d) })
+}
+
+// gxui.Driver compliance
+
+// CreateFont returns a font of the given size with fixed glyph metrics. The
+// font data is ignored.
+func (d *driver) CreateFont(data []byte, size int) (gxui.Font, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("Font size must be positive. Got %d", size)
+	}
+	return newFont(size), nil
+}
+
+func (d *driver) CreateWindowedViewport(width, height int, name string) gxui.Viewport {
+	return d.createViewport(width, height, name, false)
+}
+
+func (d *driver) CreateFullscreenViewport(width, height int, name string) gxui.Viewport {
+	if width == 0 || height == 0 {
+		width, height = defaultScreenSize.WH()
+	}
+	return d.createViewport(width, height, name, true)
+}
+
+func (d *driver) createViewport(width, height int, name string, fullscreen bool) *viewport {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Errorf("Viewport width and height must be positive. Got %dx%d", width, height))
+	}
+	v := newViewport(d, width, height, name, fullscreen)
+	v.onDestroy = d.AddViewport(v)
+	return v
+}
+
+func (d *driver) CreateCanvas(s math.Size) gxui.Canvas {
+	return displaylist.NewCanvas(s)
+}
+
+func (d *driver) CreateTexture(img image.Image, pixelsPerDip float32) gxui.Texture {
+	return newTexture(img, pixelsPerDip)
+}
+
+// addFrame accumulates the statistics of a frame presenting the display
+// list l. The fake driver rasterizes nothing, so frames take no time.
+func (d *driver) addFrame(l *displaylist.List) {
+	ops := map[string]int{}
+	headless.CountOps(l, ops)
+	d.AddFrame(0, ops)
+}
@@ -0,0 +1,154 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fake_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// runWindow creates a window holding a vertical layout of the controls
+// returned by create, then calls test once the window has been laid out.
+// The driver is terminated after test returns.
+func runWindow(t *testing.T, create func(theme gxui.Theme) []gxui.Control, test func(driver gxui.Driver, window gxui.Window)) {
+	fake.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window := theme.CreateWindow(200, 200, "test")
+		layout := theme.CreateLinearLayout()
+		for _, c := range create(theme) {
+			layout.AddChild(c)
+		}
+		window.AddChild(layout)
+		driver.Call(func() {
+			defer driver.Terminate()
+			test(driver, window)
+		})
+	})
+}
+
+func TestClickControl(t *testing.T) {
+	var button gxui.Button
+	clicks := 0
+	runWindow(t, func(theme gxui.Theme) []gxui.Control {
+		button = theme.CreateButton()
+		button.SetText("Press me")
+		button.OnClick(func(ev gxui.MouseEvent) {
+			clicks++
+			if ev.Button != gxui.MouseButtonLeft {
+				t.Errorf("Button was %v, expected %v", ev.Button, gxui.MouseButtonLeft)
+			}
+		})
+		return []gxui.Control{button}
+	}, func(driver gxui.Driver, window gxui.Window) {
+		gxui.ClickControl(button)
+		if clicks != 1 {
+			t.Errorf("Got %d clicks, expected 1", clicks)
+		}
+		if !button.IsMouseOver() {
+			t.Error("Expected the mouse to be over the button")
+		}
+		window.InjectMouseMove(gxui.MouseEvent{Point: math.Point{X: 199, Y: 199}})
+		if button.IsMouseOver() {
+			t.Error("Expected the mouse to have left the button")
+		}
+	})
+}
+
+func TestTypeIntoFocusedTextBox(t *testing.T) {
+	var first, second gxui.TextBox
+	runWindow(t, func(theme gxui.Theme) []gxui.Control {
+		first, second = theme.CreateTextBox(), theme.CreateTextBox()
+		first.SetDesiredWidth(100)
+		second.SetDesiredWidth(100)
+		return []gxui.Control{first, second}
+	}, func(driver gxui.Driver, window gxui.Window) {
+		gxui.ClickControl(first)
+		if window.Focus() != first {
+			t.Fatalf("Focus was %v, expected the first text box", window.Focus())
+		}
+		gxui.TypeText(window, "hello")
+		gxui.PressKey(window, gxui.KeyBackspace, 0)
+		if got, expected := first.Text(), "hell"; got != expected {
+			t.Errorf("Text was %q, expected %q", got, expected)
+		}
+
+		// Tab moves the focus to the next text box.
+		gxui.PressKey(window, gxui.KeyTab, 0)
+		if window.Focus() != second {
+			t.Fatalf("Focus was %v, expected the second text box", window.Focus())
+		}
+		gxui.TypeText(window, "world")
+		if got, expected := second.Text(), "world"; got != expected {
+			t.Errorf("Text was %q, expected %q", got, expected)
+		}
+		if got, expected := first.Text(), "hell"; got != expected {
+			t.Errorf("Text was %q, expected %q", got, expected)
+		}
+	})
+}
+
+func TestViewportRecordsDisplayList(t *testing.T) {
+	var label gxui.Label
+	runWindow(t, func(theme gxui.Theme) []gxui.Control {
+		label = theme.CreateLabel()
+		label.SetText("Hello")
+		return []gxui.Control{label}
+	}, func(driver gxui.Driver, window gxui.Window) {
+		l := window.Viewport().(fake.Viewport).DisplayList()
+		if l == nil {
+			t.Fatal("Expected the viewport to hold a display list")
+		}
+		if got := driver.Stats().CanvasOps["DrawRunes"]; got != 1 {
+			t.Errorf("CanvasOps[DrawRunes] was %d, expected 1", got)
+		}
+		// Every glyph of the fake font is half the font size wide.
+		size := label.Font().Size()
+		if got, expected := label.Size().W, 5*(size/2); got != expected {
+			t.Errorf("Label width was %d, expected %d", got, expected)
+		}
+	})
+}
+
+func TestClipboard(t *testing.T) {
+	runWindow(t, func(theme gxui.Theme) []gxui.Control {
+		return nil
+	}, func(driver gxui.Driver, window gxui.Window) {
+		c := driver.Clipboard()
+		if formats := c.Formats(); len(formats) != 0 {
+			t.Errorf("New clipboard held %v, expected nothing", formats)
+		}
+
+		png := []byte{0x89, 'P', 'N', 'G'}
+		c.Set(
+			gxui.ClipboardEntry{MimeType: gxui.TextMimeType, Data: []byte("old")},
+			gxui.ClipboardEntry{MimeType: gxui.PNGMimeType, Data: png},
+			gxui.ClipboardEntry{MimeType: gxui.TextMimeType, Data: []byte("chart")},
+		)
+		png[0] = 0
+		if formats := c.Formats(); !reflect.DeepEqual(formats, []string{gxui.PNGMimeType, gxui.TextMimeType}) {
+			t.Errorf("Clipboard formats were %v, expected [%s %s]", formats, gxui.PNGMimeType, gxui.TextMimeType)
+		}
+		if data, ok := c.Get(gxui.PNGMimeType); !ok || data[0] != 0x89 {
+			t.Errorf("Clipboard image was %v, %v, expected the data as set", data, ok)
+		}
+		if str, _ := driver.GetClipboard(); str != "chart" {
+			t.Errorf("Clipboard text was %q, expected %q", str, "chart")
+		}
+		if _, ok := c.Get(gxui.HTMLMimeType); ok {
+			t.Errorf("Clipboard held %s, expected no entry", gxui.HTMLMimeType)
+		}
+
+		driver.SetClipboard("plain")
+		if formats := c.Formats(); !reflect.DeepEqual(formats, []string{gxui.TextMimeType}) {
+			t.Errorf("Clipboard formats were %v after SetClipboard, expected only %s", formats, gxui.TextMimeType)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+
+	"github.com/golang/freetype/truetype"
+)
+
+// Font is a gxui.Font with fixed metrics, independent of any font data, so
+// that text layout is deterministic in tests. Every glyph is GlyphWidth DIPs
+// wide, lines are GlyphHeight DIPs high, and the baseline is Ascent DIPs below
+// the top of each line.
+type Font struct {
+	GlyphWidth  int
+	GlyphHeight int
+	Ascent      int
+}
+
+// CreateFont returns a Font with the given glyph size and ascent, in DIPs.
+func CreateFont(glyphWidth, glyphHeight, ascent int) *Font {
+	if glyphWidth <= 0 || glyphHeight <= 0 {
+		panic(fmt.Errorf("Glyph size must be positive. Got %dx%d", glyphWidth, glyphHeight))
+	}
+	return &Font{GlyphWidth: glyphWidth, GlyphHeight: glyphHeight, Ascent: ascent}
+}
+
+// newFont returns the Font created by Driver.CreateFont for the given size:
+// every glyph is half the font size wide, and lines are the font size high,
+// with the baseline at four fifths of the line.
+func newFont(size int) *Font {
+	advance := size / 2
+	if advance <= 0 {
+		advance = 1
+	}
+	return CreateFont(advance, size, size*4/5)
+}
+
+// gxui.NamedFont compliance
+func (f *Font) Family() string {
+	return "fake"
+}
+
+// gxui.Font compliance
+func (f *Font) LoadGlyphs(first, last rune) {}
+
+// Size returns the height of a line.
+func (f *Font) Size() int {
+	return f.GlyphHeight
+}
+
+func (f *Font) GlyphMaxSize() math.Size {
+	return math.Size{W: f.GlyphWidth, H: f.GlyphHeight}
+}
+
+func (f *Font) Index(r rune) truetype.Index {
+	return truetype.Index(r)
+}
+
+func (f *Font) Measure(fl *gxui.TextBlock) math.Size {
+	size := math.Size{W: 0, H: f.GlyphHeight}
+	var offset math.Point
+	for _, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.GlyphHeight
+			continue
+		}
+		offset.X += f.GlyphWidth
+		size = size.Max(math.Size{W: offset.X, H: offset.Y + f.GlyphHeight})
+	}
+	return size
+}
+
+func (f *Font) Layout(fl *gxui.TextBlock) (offsets []math.Point) {
+	size := math.Size{}
+	offsets = make([]math.Point, len(fl.Runes))
+	var offset math.Point
+	for i, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.GlyphHeight
+			continue
+		}
+		offsets[i] = offset
+		offset.X += f.GlyphWidth
+		size = size.Max(math.Size{W: offset.X, H: offset.Y + f.GlyphHeight})
+	}
+
+	rect := fl.AlignRect
+	var origin math.Point
+	switch fl.H {
+	case gxui.AlignLeft:
+		origin.X = rect.Min.X
+	case gxui.AlignCenter:
+		origin.X = rect.Mid().X - (size.W / 2)
+	case gxui.AlignRight:
+		origin.X = rect.Max.X - size.W
+	}
+	switch fl.V {
+	case gxui.AlignTop:
+		origin.Y = rect.Min.Y + f.Ascent
+	case gxui.AlignMiddle:
+		origin.Y = rect.Mid().Y - (size.H / 2) + f.Ascent
+	case gxui.AlignBottom:
+		origin.Y = rect.Max.Y - size.H + f.Ascent
+	}
+	for i, p := range offsets {
+		offsets[i] = p.Add(origin)
+	}
+	return offsets
+}
@@ -0,0 +1,43 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestFontMetrics(t *testing.T) {
+	font := fake.CreateFont(6, 10, 7)
+	block := &gxui.TextBlock{
+		Runes:     []rune("ab\ncde"),
+		AlignRect: math.CreateRect(0, 0, 100, 40),
+		H:         gxui.AlignRight,
+		V:         gxui.AlignMiddle,
+	}
+	if got, expected := font.Measure(block), (math.Size{W: 18, H: 20}); got != expected {
+		t.Errorf("Measure was %v, expected %v", got, expected)
+	}
+
+	// The block is 18x20, right-aligned and vertically centered in the 100x40
+	// rectangle, with the baseline 7 below the top of each line.
+	expected := []math.Point{
+		{X: 82, Y: 17}, {X: 88, Y: 17}, {X: 0, Y: 0},
+		{X: 82, Y: 27}, {X: 88, Y: 27}, {X: 94, Y: 27},
+	}
+	got := font.Layout(block)
+	for i := range expected {
+		if i == 2 {
+			continue // Newlines have no glyph
+		}
+		if got[i] != expected[i] {
+			t.Errorf("Offset of rune %d was %v, expected %v", i, got[i], expected[i])
+		}
+	}
+}
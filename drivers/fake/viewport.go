@@ -0,0 +1,279 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fake
+
+import (
+	"image"
+	"sync"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Viewport is the interface implemented by all viewports created by the
+// fake driver.
+type Viewport interface {
+	gxui.Viewport
+
+	// DisplayList returns the display list of the canvas most recently
+	// presented by the viewport, or nil if there is none.
+	// The returned list must not be modified.
+	DisplayList() *displaylist.List
+
+	// Visible returns true if the viewport is shown.
+	Visible() bool
+
+	// Cursor returns the cursor most recently set with SetCursor.
+	Cursor() gxui.Cursor
+
+	// CompositionRect returns the rectangle most recently set with
+	// SetCompositionRect.
+	CompositionRect() math.Rect
+
+	// Compose simulates an input method raising the composition event ev.
+	Compose(ev gxui.CompositionEvent)
+
+	// DropFiles simulates the operating system dropping the files paths
+	// onto the viewport at the point at.
+	DropFiles(paths []string, at math.Point)
+}
+
+type viewport struct {
+	sync.Mutex
+	headless.Events
+
+	driver           *driver
+	fullscreen       bool
+	scaling          float32
+	sizeDipsUnscaled math.Size
+	sizeDips         math.Size
+	position         math.Point
+	title            string
+	icon             image.Image
+	visible          bool
+	cursor           gxui.Cursor
+	compositionRect  math.Rect
+	canvas           *displaylist.Canvas
+	destroyed        bool
+	stats            gxui.RedrawStats
+
+	// Called once when the viewport is destroyed
+	onDestroy func()
+}
+
+func newViewport(driver *driver, width, height int, title string, fullscreen bool) *viewport {
+	v := &viewport{
+		driver:     driver,
+		fullscreen: fullscreen,
+		scaling:    1,
+		title:      title,
+		visible:    true,
+	}
+	v.Events.Init(&driver.Driver)
+	v.sizeDipsUnscaled = math.Size{W: width, H: height}
+	v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / v.scaling)
+	return v
+}
+
+// Viewport compliance
+func (v *viewport) DisplayList() *displaylist.List {
+	v.Lock()
+	defer v.Unlock()
+	if v.canvas == nil {
+		return nil
+	}
+	return v.canvas.DisplayList()
+}
+
+func (v *viewport) Visible() bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.visible
+}
+
+// gxui.Viewport compliance
+// These methods are all called on the application routine
+func (v *viewport) SetCanvas(cc gxui.Canvas) {
+	v.setCanvas(cc, v.sizePixels().Area(), true)
+}
+
+func (v *viewport) SetCanvasDamaged(cc gxui.Canvas, damage []math.Rect) {
+	drawn := 0
+	for _, r := range gxui.DamagePixels(damage, v.Scale(), v.sizePixels()) {
+		drawn += r.Size().Area()
+	}
+	v.setCanvas(cc, drawn, false)
+}
+
+func (v *viewport) setCanvas(cc gxui.Canvas, drawn int, full bool) {
+	var c *displaylist.Canvas
+	if cc != nil {
+		c = cc.(*displaylist.Canvas)
+	}
+	v.Lock()
+	if v.destroyed {
+		v.Unlock()
+		return
+	}
+	v.canvas = c
+	v.stats.Add(v.sizeDipsUnscaled, drawn, full)
+	v.Unlock()
+	if c != nil {
+		v.driver.addFrame(c.DisplayList())
+	}
+}
+
+func (v *viewport) sizePixels() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizeDipsUnscaled
+}
+
+func (v *viewport) RedrawStats() gxui.RedrawStats {
+	v.Lock()
+	defer v.Unlock()
+	return v.stats
+}
+
+func (v *viewport) Scale() float32 {
+	v.Lock()
+	defer v.Unlock()
+	return v.scaling
+}
+
+func (v *viewport) SetScale(s float32) {
+	v.Lock()
+	defer v.Unlock()
+	if s != v.scaling {
+		v.scaling = s
+		v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / s)
+		v.ResizeEvent.Fire()
+	}
+}
+
+func (v *viewport) SizeDips() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizeDips
+}
+
+func (v *viewport) SetSizeDips(size math.Size) {
+	v.Lock()
+	defer v.Unlock()
+	v.sizeDips = size
+	v.sizeDipsUnscaled = size.ScaleS(v.scaling)
+	v.ResizeEvent.Fire()
+}
+
+func (v *viewport) SizePixels() math.Size {
+	return v.sizePixels()
+}
+
+func (v *viewport) Title() string {
+	v.Lock()
+	defer v.Unlock()
+	return v.title
+}
+
+func (v *viewport) SetTitle(title string) {
+	v.Lock()
+	defer v.Unlock()
+	v.title = title
+}
+
+func (v *viewport) Icon() image.Image {
+	v.Lock()
+	defer v.Unlock()
+	return v.icon
+}
+
+func (v *viewport) SetIcon(i image.Image) {
+	v.Lock()
+	defer v.Unlock()
+	v.icon = i
+}
+
+func (v *viewport) Position() math.Point {
+	v.Lock()
+	defer v.Unlock()
+	return v.position
+}
+
+func (v *viewport) SetPosition(pos math.Point) {
+	v.Lock()
+	defer v.Unlock()
+	v.position = pos
+}
+
+func (v *viewport) SetCursor(c gxui.Cursor) {
+	v.Lock()
+	defer v.Unlock()
+	v.cursor = c
+}
+
+func (v *viewport) Cursor() gxui.Cursor {
+	v.Lock()
+	defer v.Unlock()
+	return v.cursor
+}
+
+func (v *viewport) SetCompositionRect(r math.Rect) {
+	v.Lock()
+	defer v.Unlock()
+	v.compositionRect = r
+}
+
+func (v *viewport) CompositionRect() math.Rect {
+	v.Lock()
+	defer v.Unlock()
+	return v.compositionRect
+}
+
+func (v *viewport) Fullscreen() bool {
+	return v.fullscreen
+}
+
+func (v *viewport) Show() {
+	v.Lock()
+	defer v.Unlock()
+	v.visible = true
+}
+
+func (v *viewport) Hide() {
+	v.Lock()
+	defer v.Unlock()
+	v.visible = false
+}
+
+func (v *viewport) Close() {
+	v.CloseEvent.Fire()
+	v.Destroy()
+}
+
+func (v *viewport) Compose(ev gxui.CompositionEvent) {
+	v.CompositionEvent.Fire(ev)
+}
+
+func (v *viewport) DropFiles(paths []string, at math.Point) {
+	v.FileDropEvent.Fire(paths, at)
+}
+
+func (v *viewport) Destroy() {
+	v.Lock()
+	if v.destroyed {
+		v.Unlock()
+		return
+	}
+	v.destroyed = true
+	v.canvas = nil
+	onDestroy := v.onDestroy
+	v.Unlock()
+	if onDestroy != nil {
+		onDestroy()
+	}
+}
@@ -37,6 +37,42 @@ const (
     gl_FragColor = texture2D(source, vTexcoords);
   }`
 
+	fsLayerSrc = `
+  #ifdef GL_ES
+    precision mediump float;
+  #endif
+
+  uniform sampler2D source;
+  uniform float opacity;
+  varying vec2 vTexcoords;
+  void main() {
+    gl_FragColor = texture2D(source, vTexcoords) * opacity;
+  }`
+
+	fsBlurSrc = `
+  #ifdef GL_ES
+    precision mediump float;
+  #endif
+
+  uniform sampler2D source;
+  uniform vec2 texelStep;
+  uniform float sigma;
+  varying vec2 vTexcoords;
+  void main() {
+    float radius = ceil(sigma * 3.0);
+    vec4 sum = vec4(0.0);
+    float total = 0.0;
+    for (int i = -32; i <= 32; i++) {
+      float x = float(i);
+      if (abs(x) <= radius) {
+        float w = exp(-x * x / (2.0 * sigma * sigma));
+        sum += texture2D(source, vTexcoords + texelStep * x) * w;
+        total += w;
+      }
+    }
+    gl_FragColor = sum / total;
+  }`
+
 	vsColorSrc = `
   attribute vec2 aPosition;
   uniform mat3 mPos;
@@ -56,6 +92,81 @@ const (
     gl_FragColor *= gl_FragColor.a; // PMA
   }`
 
+	vsPaintSrc = `
+  attribute vec2 aPosition;
+  uniform mat3 mPos;
+  uniform mat3 mPaint;
+  varying vec2 vPaint;
+  void main() {
+    vec3 pos3 = vec3(aPosition, 1.0);
+    gl_Position = vec4((mPos * pos3).xy, 0.0, 1.0);
+    vPaint = (mPaint * pos3).xy;
+  }`
+
+	fsGradientSrc = `
+  #ifdef GL_ES
+    precision mediump float;
+  #endif
+
+  uniform sampler2D ramp;
+  uniform float radial;
+  varying vec2 vPaint;
+  void main() {
+    float t = clamp(mix(vPaint.x, length(vPaint), radial), 0.0, 1.0);
+    gl_FragColor = texture2D(ramp, vec2((t * 255.0 + 0.5) / 256.0, 0.5));
+  }`
+
+	fsShadowSrc = `
+  #ifdef GL_ES
+    precision mediump float;
+  #endif
+
+  uniform vec4 Color;
+  uniform vec4 rect;
+  uniform vec2 offset;
+  uniform float spread;
+  uniform float sigma;
+  uniform float radius;
+  varying vec2 vPaint;
+
+  float roundedRectDistance(vec2 p, vec4 r, float cornerRadius) {
+    vec2 center = (r.xy + r.zw) * 0.5;
+    vec2 halfSize = (r.zw - r.xy) * 0.5;
+    cornerRadius = clamp(cornerRadius, 0.0, min(halfSize.x, halfSize.y));
+    vec2 q = abs(p - center) - halfSize + cornerRadius;
+    return length(max(q, 0.0)) + min(max(q.x, q.y), 0.0) - cornerRadius;
+  }
+
+  float erfc(float x) {
+    float x2 = x * x;
+    float erf = sqrt(1.0 - exp(-x2 * (1.2732395 + 0.147 * x2) / (1.0 + 0.147 * x2)));
+    return 1.0 - sign(x) * erf;
+  }
+
+  void main() {
+    if (roundedRectDistance(vPaint, rect, radius) < 0.0) {
+      discard;
+    }
+    float r = radius > 0.0 ? radius + spread : 0.0;
+    float d = roundedRectDistance(vPaint - offset, rect, r) - spread;
+    float a = sigma > 0.0 ? 0.5 * erfc(d / (sigma * 1.4142136)) : step(d, 0.0);
+    gl_FragColor = vec4(Color.rgb * Color.a, Color.a) * a; // PMA
+  }`
+
+	fsPatternSrc = `
+  #ifdef GL_ES
+    precision mediump float;
+  #endif
+
+  uniform sampler2D source;
+  uniform float flipY;
+  varying vec2 vPaint;
+  void main() {
+    vec2 uv = fract(vPaint);
+    uv.y = mix(uv.y, 1.0 - uv.y, flipY);
+    gl_FragColor = texture2D(source, uv);
+  }`
+
 	vsFontSrc = `
   attribute vec2 aSrc;
   attribute vec2 aDst;
@@ -101,36 +212,87 @@ type glyphBatch struct {
 }
 
 type blitter struct {
-	stats       *contextStats
-	quad        *shape
-	copyShader  *shaderProgram
-	colorShader *shaderProgram
-	fontShader  *shaderProgram
-	glyphBatch  glyphBatch
+	stats          *contextStats
+	quad           *shape
+	copyShader     *shaderProgram
+	layerShader    *shaderProgram
+	blurShader     *shaderProgram
+	shadowShader   *shaderProgram
+	colorShader    *shaderProgram
+	gradientShader *shaderProgram
+	patternShader  *shaderProgram
+	fontShader     *shaderProgram
+	glyphBatch     glyphBatch
 }
 
 func newBlitter(ctx *context, stats *contextStats) *blitter {
 	return &blitter{
-		stats:       stats,
-		quad:        newQuadShape(),
-		copyShader:  newShaderProgram(ctx, vsCopySrc, fsCopySrc),
-		colorShader: newShaderProgram(ctx, vsColorSrc, fsColorSrc),
-		fontShader:  newShaderProgram(ctx, vsFontSrc, fsFontSrc),
+		stats:          stats,
+		quad:           newQuadShape(),
+		copyShader:     newShaderProgram(ctx, vsCopySrc, fsCopySrc),
+		layerShader:    newShaderProgram(ctx, vsCopySrc, fsLayerSrc),
+		blurShader:     newShaderProgram(ctx, vsCopySrc, fsBlurSrc),
+		shadowShader:   newShaderProgram(ctx, vsPaintSrc, fsShadowSrc),
+		colorShader:    newShaderProgram(ctx, vsColorSrc, fsColorSrc),
+		gradientShader: newShaderProgram(ctx, vsPaintSrc, fsGradientSrc),
+		patternShader:  newShaderProgram(ctx, vsPaintSrc, fsPatternSrc),
+		fontShader:     newShaderProgram(ctx, vsFontSrc, fsFontSrc),
 	}
 }
 
 func (b *blitter) destroy(ctx *context) {
 	b.copyShader.destroy(ctx)
+	b.layerShader.destroy(ctx)
+	b.blurShader.destroy(ctx)
+	b.shadowShader.destroy(ctx)
 	b.colorShader.destroy(ctx)
+	b.gradientShader.destroy(ctx)
+	b.patternShader.destroy(ctx)
 	b.fontShader.destroy(ctx)
 }
 
+// localToWindow returns the transform from local pixels, at the context's
+// resolution, to window pixels for the draw state ds.
+func localToWindow(ctx *context, ds *drawState) math.Mat3 {
+	if ds.Transform == nil {
+		return math.CreateMat3Translate(ds.OriginPixels.Vec2())
+	}
+	s := 1 / ctx.resolution.dipsToPixels()
+	return math.CreateMat3Scale(math.Vec2{X: s, Y: s}).Mul(*ds.Transform)
+}
+
+// windowToNDC returns the transform from window pixels to normalized device
+// coordinates.
+func windowToNDC(ctx *context) math.Mat3 {
+	dw, dh := ctx.sizePixels.WH()
+	return math.CreateMat3(
+		+2.0/float32(dw), 0, 0,
+		0, -2.0/float32(dh), 0,
+		-1.0, +1.0, 1,
+	)
+}
+
+// rectToNDC returns the transform from the unit quad to the rectangle r, in
+// local pixels, in normalized device coordinates.
+func rectToNDC(ctx *context, r math.Rect, ds *drawState) math.Mat3 {
+	return math.CreateMat3(
+		float32(r.W()), 0, 0,
+		0, float32(r.H()), 0,
+		float32(r.Min.X), float32(r.Min.Y), 1,
+	).Mul(localToWindow(ctx, ds)).Mul(windowToNDC(ctx))
+}
+
+// dipsToNDC returns the transform from local DIPs to normalized device
+// coordinates.
+func dipsToNDC(ctx *context, ds *drawState) math.Mat3 {
+	s := ctx.resolution.dipsToPixels()
+	return math.CreateMat3Scale(math.Vec2{X: s, Y: s}).Mul(localToWindow(ctx, ds)).Mul(windowToNDC(ctx))
+}
+
 func (b *blitter) blit(ctx *context, tc *textureContext, srcRect, dstRect math.Rect, ds *drawState) {
 	b.commitGlyphs(ctx)
 
-	dstRect = dstRect.Offset(ds.OriginPixels)
 	sw, sh := tc.sizePixels.WH()
-	dw, dh := ctx.sizePixels.WH()
 
 	var mUV math.Mat3
 	if tc.flipY {
@@ -148,12 +310,7 @@ func (b *blitter) blit(ctx *context, tc *textureContext, srcRect, dstRect math.R
 			float32(srcRect.Min.Y)/float32(sh), 1,
 		)
 	}
-	mPos := math.CreateMat3(
-		+2.0*float32(dstRect.W())/float32(dw), 0, 0,
-		0, -2.0*float32(dstRect.H())/float32(dh), 0,
-		-1.0+2.0*float32(dstRect.Min.X)/float32(dw),
-		+1.0-2.0*float32(dstRect.Min.Y)/float32(dh), 1,
-	)
+	mPos := rectToNDC(ctx, dstRect, ds)
 	if !tc.pma {
 		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 	}
@@ -168,9 +325,132 @@ func (b *blitter) blit(ctx *context, tc *textureContext, srcRect, dstRect math.R
 	b.stats.drawCallCount++
 }
 
-func (b *blitter) blitGlyph(ctx *context, tc *textureContext, c gxui.Color, srcRect, dstRect math.Rect, ds *drawState) {
-	dstRect = dstRect.Offset(ds.OriginPixels)
+// blitLayer composites the area r, in window pixels, of the layer texture tc
+// onto the current framebuffer with opacity and the blend mode. Blend modes
+// other than gxui.NormalBlend are exact for opaque destinations, but leave
+// the alpha of translucent destinations unchanged.
+func (b *blitter) blitLayer(ctx *context, tc *textureContext, r math.Rect, opacity float32, blend gxui.BlendMode) {
+	b.commitGlyphs(ctx)
+
+	sw, sh := tc.sizePixels.WH()
+	mUV := math.CreateMat3(
+		float32(r.W())/float32(sw), 0, 0,
+		0, -float32(r.H())/float32(sh), 0,
+		float32(r.Min.X)/float32(sw),
+		1.0-float32(r.Min.Y)/float32(sh), 1,
+	)
+	mPos := rectToNDC(ctx, r, &drawState{})
+	switch blend {
+	case gxui.MultiplyBlend:
+		gl.BlendFunc(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA)
+	case gxui.ScreenBlend:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_COLOR)
+	case gxui.AdditiveBlend:
+		gl.BlendFunc(gl.ONE, gl.ONE)
+	}
+	b.quad.draw(ctx, b.layerShader, uniformBindings{
+		"source":  tc,
+		"mUV":     mUV,
+		"mPos":    mPos,
+		"opacity": math.Saturate(opacity),
+	})
+	gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	b.stats.drawCallCount++
+}
 
+// blitShadow draws the shadow s cast by the rectangle r, in local DIPs.
+func (b *blitter) blitShadow(ctx *context, r math.Rect, s gxui.Shadow, ds *drawState) {
+	b.commitGlyphs(ctx)
+	bounds := s.Bounds(r)
+	// Map the unit quad to the shadow's bounds in DIPs.
+	mPaint := math.CreateMat3(
+		float32(bounds.W()), 0, 0,
+		0, float32(bounds.H()), 0,
+		float32(bounds.Min.X), float32(bounds.Min.Y), 1,
+	)
+	b.quad.draw(ctx, b.shadowShader, uniformBindings{
+		"mPos":   mPaint.Mul(dipsToNDC(ctx, ds)),
+		"mPaint": mPaint,
+		"Color":  s.Color,
+		"rect":   math.Vec4{X: float32(r.Min.X), Y: float32(r.Min.Y), Z: float32(r.Max.X), W: float32(r.Max.Y)},
+		"offset": s.Offset.Vec2(),
+		"spread": s.Spread,
+		"sigma":  s.Blur / 2,
+		"radius": s.CornerRadius,
+	})
+	b.stats.drawCallCount++
+}
+
+// maxBlurSigma is the largest standard deviation, in pixels, that the blur
+// shader samples out to three standard deviations.
+const maxBlurSigma = 32.0 / 3
+
+// blurRect blurs the area r, in window pixels, of the current framebuffer
+// with a Gaussian of standard deviation sigma pixels. The blur is separated
+// into a horizontal pass into a temporary texture, and a vertical pass back
+// into the framebuffer.
+func (b *blitter) blurRect(ctx *context, r math.Rect, sigma float32) {
+	w, h := r.W(), r.H()
+	if w <= 0 || h <= 0 || sigma <= 0 {
+		return
+	}
+	b.commitGlyphs(ctx)
+	sigma = math.Minf(sigma, maxBlurSigma)
+
+	newTexture := func() gl.Texture {
+		t := gl.CreateTexture()
+		gl.BindTexture(gl.TEXTURE_2D, t)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		return t
+	}
+	size := math.Size{W: w, H: h}
+
+	// Copy the area to blur. GL framebuffers are stored bottom row first.
+	src := newTexture()
+	gl.CopyTexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, r.Min.X, ctx.sizePixels.H-r.Max.Y, w, h, 0)
+	tmp := newTexture()
+	gl.TexImage2D(gl.TEXTURE_2D, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.BindTexture(gl.TEXTURE_2D, gl.Texture{})
+	fb := gl.CreateFramebuffer()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, tmp, 0)
+
+	// The blurred pixels replace those in the framebuffer.
+	gl.Disable(gl.BLEND)
+
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(0, 0, w, h)
+	b.quad.draw(ctx, b.blurShader, uniformBindings{
+		"source":    &textureContext{texture: src, sizePixels: size},
+		"mPos":      math.CreateMat3(2, 0, 0, 0, 2, 0, -1, -1, 1),
+		"mUV":       math.Mat3Ident,
+		"texelStep": math.Vec2{X: 1 / float32(w)},
+		"sigma":     sigma,
+	})
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, ctx.currentFramebuffer())
+	gl.Viewport(0, 0, ctx.sizePixels.W, ctx.sizePixels.H)
+	gl.Enable(gl.SCISSOR_TEST)
+	b.quad.draw(ctx, b.blurShader, uniformBindings{
+		"source":    &textureContext{texture: tmp, sizePixels: size},
+		"mPos":      rectToNDC(ctx, r, &drawState{}),
+		"mUV":       math.CreateMat3(1, 0, 0, 0, -1, 0, 0, 1, 1),
+		"texelStep": math.Vec2{Y: 1 / float32(h)},
+		"sigma":     sigma,
+	})
+
+	gl.Enable(gl.BLEND)
+	gl.DeleteFramebuffer(fb)
+	gl.DeleteTexture(src)
+	gl.DeleteTexture(tmp)
+	checkError()
+	b.stats.drawCallCount += 2
+}
+
+func (b *blitter) blitGlyph(ctx *context, tc *textureContext, c gxui.Color, srcRect, dstRect math.Rect, ds *drawState) {
 	if b.glyphBatch.GlyphPage != tc {
 		b.commitGlyphs(ctx)
 		b.glyphBatch.GlyphPage = tc
@@ -182,12 +462,23 @@ func (b *blitter) blitGlyph(ctx *context, tc *textureContext, c gxui.Color, srcR
 		float32(ds.ClipPixels.Max.X),
 		float32(ds.ClipPixels.Max.Y),
 	}
-	b.glyphBatch.DstRects = append(b.glyphBatch.DstRects,
-		float32(dstRect.Min.X), float32(dstRect.Min.Y),
-		float32(dstRect.Max.X), float32(dstRect.Min.Y),
-		float32(dstRect.Min.X), float32(dstRect.Max.Y),
-		float32(dstRect.Max.X), float32(dstRect.Max.Y),
-	)
+	if ds.Transform == nil {
+		dstRect = dstRect.Offset(ds.OriginPixels)
+		b.glyphBatch.DstRects = append(b.glyphBatch.DstRects,
+			float32(dstRect.Min.X), float32(dstRect.Min.Y),
+			float32(dstRect.Max.X), float32(dstRect.Min.Y),
+			float32(dstRect.Min.X), float32(dstRect.Max.Y),
+			float32(dstRect.Max.X), float32(dstRect.Max.Y),
+		)
+	} else {
+		m := localToWindow(ctx, ds)
+		b.glyphBatch.DstRects = appendVec2(b.glyphBatch.DstRects,
+			m.Transform(dstRect.TL().Vec2()),
+			m.Transform(dstRect.TR().Vec2()),
+			m.Transform(dstRect.BL().Vec2()),
+			m.Transform(dstRect.BR().Vec2()),
+		)
+	}
 	b.glyphBatch.SrcRects = append(b.glyphBatch.SrcRects,
 		float32(srcRect.Min.X), float32(srcRect.Min.Y),
 		float32(srcRect.Max.X), float32(srcRect.Min.Y),
@@ -216,14 +507,7 @@ func (b *blitter) blitGlyph(ctx *context, tc *textureContext, c gxui.Color, srcR
 
 func (b *blitter) blitShape(ctx *context, shape shape, color gxui.Color, ds *drawState) {
 	b.commitGlyphs(ctx)
-	dipsToPixels := ctx.resolution.dipsToPixels()
-	dw, dh := ctx.sizePixels.WH()
-	mPos := math.CreateMat3(
-		+2.0*dipsToPixels/float32(dw), 0, 0,
-		0, -2.0*dipsToPixels/float32(dh), 0,
-		-1.0+2.0*float32(ds.OriginPixels.X)/float32(dw),
-		+1.0-2.0*float32(ds.OriginPixels.Y)/float32(dh), 1,
-	)
+	mPos := dipsToNDC(ctx, ds)
 
 	shape.draw(ctx, b.colorShader, uniformBindings{
 		"mPos":  mPos,
@@ -244,14 +528,7 @@ func (b *blitter) blitShape(ctx *context, shape shape, color gxui.Color, ds *dra
 
 func (b *blitter) blitRect(ctx *context, dstRect math.Rect, color gxui.Color, ds *drawState) {
 	b.commitGlyphs(ctx)
-	dstRect = dstRect.Offset(ds.OriginPixels)
-	dw, dh := ctx.sizePixels.WH()
-	mPos := math.CreateMat3(
-		+2.0*float32(dstRect.W())/float32(dw), 0, 0,
-		0, -2.0*float32(dstRect.H())/float32(dh), 0,
-		-1.0+2.0*float32(dstRect.Min.X)/float32(dw),
-		+1.0-2.0*float32(dstRect.Min.Y)/float32(dh), 1,
-	)
+	mPos := rectToNDC(ctx, dstRect, ds)
 
 	b.quad.draw(ctx, b.colorShader, uniformBindings{
 		"mPos":  mPos,
@@ -260,6 +537,68 @@ func (b *blitter) blitRect(ctx *context, dstRect math.Rect, color gxui.Color, ds
 	b.stats.drawCallCount++
 }
 
+// blitShapePaint fills the shape with the gradient or pattern of p. bounds is
+// the bounding rectangle of the shape in DIPs, relative to the draw state's
+// origin.
+func (b *blitter) blitShapePaint(ctx *context, shape shape, p *brushPaint, bounds math.Rect, ds *drawState) {
+	if bounds.W() <= 0 || bounds.H() <= 0 {
+		return
+	}
+	b.commitGlyphs(ctx)
+	mPos := dipsToNDC(ctx, ds)
+	// Shape vertices are in DIPs.
+	size := bounds.Size()
+	scale := math.Vec2{X: 1 / float32(size.W), Y: 1 / float32(size.H)}
+	offset := bounds.Min.Vec2().Neg().Mul(scale)
+	b.paint(ctx, &shape, p, mPos, p.matrix(scale, offset, size))
+}
+
+// blitRectPaint fills dstRect, in pixels, with the gradient or pattern of p.
+// bounds is the same rectangle in DIPs.
+func (b *blitter) blitRectPaint(ctx *context, dstRect math.Rect, p *brushPaint, bounds math.Rect, ds *drawState) {
+	if bounds.W() <= 0 || bounds.H() <= 0 {
+		return
+	}
+	b.commitGlyphs(ctx)
+	mPos := rectToNDC(ctx, dstRect, ds)
+	// Quad vertices already span the unit square of the bounds.
+	b.paint(ctx, b.quad, p, mPos, p.matrix(math.Vec2{X: 1, Y: 1}, math.Vec2{}, bounds.Size()))
+}
+
+func (b *blitter) paint(ctx *context, s *shape, p *brushPaint, mPos, mPaint math.Mat3) {
+	if p.pattern != nil {
+		tc := ctx.getOrCreateTextureContext(p.pattern)
+		flipY := float32(0)
+		if tc.flipY {
+			flipY = 1
+		}
+		if !tc.pma {
+			gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		}
+		s.draw(ctx, b.patternShader, uniformBindings{
+			"source": tc,
+			"flipY":  flipY,
+			"mPos":   mPos,
+			"mPaint": mPaint,
+		})
+		if !tc.pma {
+			gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+		}
+	} else {
+		radial := float32(0)
+		if p.isRadial() {
+			radial = 1
+		}
+		s.draw(ctx, b.gradientShader, uniformBindings{
+			"ramp":   ctx.getOrCreateTextureContext(ctx.getOrCreateRampTexture(p)),
+			"radial": radial,
+			"mPos":   mPos,
+			"mPaint": mPaint,
+		})
+	}
+	b.stats.drawCallCount++
+}
+
 func (b *blitter) commit(ctx *context) {
 	b.commitGlyphs(ctx)
 }
@@ -1,72 +1,13 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
 package gl
 
-import "sync"
+import "github.com/robertt-smg/gxui/drivers/internal/callqueue"
 
-type CallQueue struct {
-	mu     sync.Mutex
-	onDeck chan *callNode
-	head   *callNode
-	tail   *callNode
-}
+type CallQueue = callqueue.CallQueue
 
 func NewCallQueue() *CallQueue {
-	return &CallQueue{
-		onDeck: make(chan *callNode, 1),
-	}
-}
-
-func (c *CallQueue) Inject(call func()) {
-	node := &callNode{v: call}
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if c.head == nil {
-		c.head = node
-		c.tail = node
-		c.onDeck <- c.head
-		return
-	}
-	c.tail.next = node
-	c.tail = node
-}
-
-func (c *CallQueue) Pop() (func(), bool) {
-	select {
-	case node, ok := <-c.onDeck:
-		if !ok {
-			return nil, false
-		}
-		c.shift()
-		return node.v, true
-	default:
-		return nil, true
-	}
-}
-
-func (c *CallQueue) PopWhenReady() (func(), bool) {
-	node, ok := <-c.onDeck
-	if !ok {
-		return nil, false
-	}
-	c.shift()
-	return node.v, true
-}
-
-func (c *CallQueue) Close() {
-	close(c.onDeck)
-}
-
-func (c *CallQueue) shift() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.head = c.head.next
-	if c.head == nil {
-		c.tail = nil
-		return
-	}
-	c.onDeck <- c.head
-}
-
-type callNode struct {
-	v    func()
-	next *callNode
+	return callqueue.New()
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
 
 	"github.com/robertt-smg/gxui/math"
 
@@ -32,11 +33,16 @@ type drawState struct {
 	// The below are all in window coordinates
 	ClipPixels   math.Rect
 	OriginPixels math.Point
+
+	// Transform, if not nil, maps local DIPs to window pixels and is used in
+	// place of OriginPixels.
+	Transform *math.Mat3
 }
 
 type canvas struct {
 	sizeDips          math.Size
 	ops               []canvasOp
+	recorded          []displaylist.Op
 	built             bool
 	buildingPushCount int
 }
@@ -55,16 +61,23 @@ func (c *canvas) draw(ctx *context, dss *drawStateStack) {
 	ds := dss.head()
 	ctx.apply(ds)
 
-	for _, op := range c.ops {
+	for i, op := range c.ops {
+		ctx.stats.canvasOps[c.recorded[i].Name()]++
 		op(ctx, dss)
 	}
 }
 
-func (c *canvas) appendOp(name string, op canvasOp) {
+func (c *canvas) appendOp(record displaylist.Op, op canvasOp) {
 	if c.built {
-		panic(fmt.Errorf("%s() called after Complete()", name))
+		panic(fmt.Errorf("%s() called after Complete()", record.Name()))
 	}
 	c.ops = append(c.ops, op)
+	c.recorded = append(c.recorded, record)
+}
+
+// displaylist.Recorder compliance
+func (c *canvas) DisplayList() *displaylist.List {
+	return &displaylist.List{Size: c.sizeDips, Ops: c.recorded}
 }
 
 // gxui.Canvas compliance
@@ -88,31 +101,69 @@ func (c *canvas) Complete() {
 
 func (c *canvas) Push() {
 	c.buildingPushCount++
-	c.appendOp("Push", func(ctx *context, dss *drawStateStack) {
+	c.appendOp(displaylist.Push{}, func(ctx *context, dss *drawStateStack) {
+		dss.push(*dss.head())
+	})
+}
+
+func (c *canvas) PushTransform(m math.Mat3) {
+	c.buildingPushCount++
+	c.appendOp(displaylist.PushTransform{Transform: m}, func(ctx *context, dss *drawStateStack) {
 		dss.push(*dss.head())
+		ds := dss.head()
+		t := m
+		if ds.Transform != nil {
+			t = t.Mul(*ds.Transform)
+		} else {
+			s := ctx.resolution.dipsToPixels()
+			t = t.Mul(math.CreateMat3Scale(math.Vec2{X: s, Y: s})).
+				Mul(math.CreateMat3Translate(ds.OriginPixels.Vec2()))
+		}
+		ds.Transform = &t
 	})
 }
 
 func (c *canvas) Pop() {
 	c.buildingPushCount--
-	c.appendOp("Pop", func(ctx *context, dss *drawStateStack) {
+	c.appendOp(displaylist.Pop{}, func(ctx *context, dss *drawStateStack) {
+		dss.pop()
+		ctx.apply(dss.head())
+	})
+}
+
+func (c *canvas) PushLayer(opacity float32, blend gxui.BlendMode) {
+	c.buildingPushCount++
+	c.appendOp(displaylist.PushLayer{Opacity: opacity, Blend: blend}, func(ctx *context, dss *drawStateStack) {
+		dss.push(*dss.head())
+		ctx.pushLayer(dss.head().ClipPixels, opacity, blend)
+	})
+}
+
+func (c *canvas) PopLayer() {
+	c.buildingPushCount--
+	c.appendOp(displaylist.PopLayer{}, func(ctx *context, dss *drawStateStack) {
+		ctx.popLayer()
 		dss.pop()
 		ctx.apply(dss.head())
 	})
 }
 
 func (c *canvas) AddClip(r math.Rect) {
-	c.appendOp("AddClip", func(ctx *context, dss *drawStateStack) {
+	c.appendOp(displaylist.AddClip{Rect: r}, func(ctx *context, dss *drawStateStack) {
 		ds := dss.head()
-		rectLocalPixels := ctx.resolution.rectDipsToPixels(r)
-		rectWindowPixels := rectLocalPixels.Offset(ds.OriginPixels)
-		ds.ClipPixels = ds.ClipPixels.Intersect(rectWindowPixels)
+		if ds.Transform != nil {
+			ds.ClipPixels = ds.ClipPixels.Intersect(ds.Transform.TransformRect(r))
+		} else {
+			rectLocalPixels := ctx.resolution.rectDipsToPixels(r)
+			rectWindowPixels := rectLocalPixels.Offset(ds.OriginPixels)
+			ds.ClipPixels = ds.ClipPixels.Intersect(rectWindowPixels)
+		}
 		ctx.apply(ds)
 	})
 }
 
 func (c *canvas) Clear(color gxui.Color) {
-	c.appendOp("Clear", func(ctx *context, dss *drawStateStack) {
+	c.appendOp(displaylist.Clear{Color: color}, func(ctx *context, dss *drawStateStack) {
 		gl.ClearColor(
 			color.R,
 			color.G,
@@ -128,11 +179,17 @@ func (c *canvas) DrawCanvas(cc gxui.Canvas, offsetDips math.Point) {
 		panic("Canvas cannot be nil")
 	}
 	childCanvas := cc.(*canvas)
-	c.appendOp("DrawCanvas", func(ctx *context, dss *drawStateStack) {
+	record := displaylist.DrawCanvas{Canvas: childCanvas.DisplayList(), Offset: offsetDips}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
 		offsetPixels := ctx.resolution.pointDipsToPixels(offsetDips)
 		dss.push(*dss.head())
 		ds := dss.head()
-		ds.OriginPixels = ds.OriginPixels.Add(offsetPixels)
+		if ds.Transform != nil {
+			t := math.CreateMat3Translate(offsetDips.Vec2()).Mul(*ds.Transform)
+			ds.Transform = &t
+		} else {
+			ds.OriginPixels = ds.OriginPixels.Add(offsetPixels)
+		}
 		childCanvas.draw(ctx, dss)
 		dss.pop()
 		ctx.apply(dss.head())
@@ -145,14 +202,21 @@ func (c *canvas) DrawRunes(f gxui.Font, r []rune, p []math.Point, col gxui.Color
 	}
 	runes := append([]rune{}, r...)
 	points := append([]math.Point{}, p...)
-	c.appendOp("DrawRunes", func(ctx *context, dss *drawStateStack) {
+	record := displaylist.DrawRunes{
+		Font:   displaylist.CreateFontRef(f),
+		Runes:  runes,
+		Points: points,
+		Color:  col,
+	}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
 		f.(*font).DrawRunes(ctx, runes, points, col, dss.head())
 	})
 }
 
 func (c *canvas) DrawLines(lines gxui.Polygon, pen gxui.Pen) {
-	edge := openPolyToShape(lines, pen.Width)
-	c.appendOp("DrawLines", func(ctx *context, dss *drawStateStack) {
+	record := displaylist.DrawLines{Lines: append(gxui.Polygon{}, lines...), Pen: pen}
+	edge := openPolyToShape(lines, pen)
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
 		ds := dss.head()
 		if edge != nil && pen.Color.A > 0 {
 			ctx.blitter.blitShape(ctx, *edge, pen.Color, ds)
@@ -161,27 +225,48 @@ func (c *canvas) DrawLines(lines gxui.Polygon, pen gxui.Pen) {
 }
 
 func (c *canvas) DrawPolygon(poly gxui.Polygon, pen gxui.Pen, brush gxui.Brush) {
-	fill, edge := closedPolyToShape(poly, pen.Width)
-	c.appendOp("DrawPolygon", func(ctx *context, dss *drawStateStack) {
+	record := displaylist.DrawPolygon{Polygon: append(gxui.Polygon{}, poly...), Pen: pen, Brush: brush}
+	c.appendOp(record, drawPolygon(poly, pen, brush))
+}
+
+func drawPolygon(poly gxui.Polygon, pen gxui.Pen, brush gxui.Brush) canvasOp {
+	fill, edge := closedPolyToShape(poly, pen)
+	paint := newBrushPaint(brush)
+	bounds := poly.Bounds()
+	return func(ctx *context, dss *drawStateStack) {
 		ds := dss.head()
 		if fill != nil && brush.Color.A > 0 {
-			ctx.blitter.blitShape(ctx, *fill, brush.Color, ds)
+			if paint != nil {
+				ctx.blitter.blitShapePaint(ctx, *fill, paint, bounds, ds)
+			} else {
+				ctx.blitter.blitShape(ctx, *fill, brush.Color, ds)
+			}
 		}
 		if edge != nil && pen.Color.A > 0 {
 			ctx.blitter.blitShape(ctx, *edge, pen.Color, ds)
 		}
-	})
+	}
 }
 
 func (c *canvas) DrawRect(r math.Rect, brush gxui.Brush) {
-	c.appendOp("DrawRect", func(ctx *context, dss *drawStateStack) {
+	c.appendOp(displaylist.DrawRect{Rect: r, Brush: brush}, drawRect(r, brush))
+}
+
+func drawRect(r math.Rect, brush gxui.Brush) canvasOp {
+	if paint := newBrushPaint(brush); paint != nil {
+		return func(ctx *context, dss *drawStateStack) {
+			ctx.blitter.blitRectPaint(ctx, ctx.resolution.rectDipsToPixels(r), paint, r, dss.head())
+		}
+	}
+	return func(ctx *context, dss *drawStateStack) {
 		ctx.blitter.blitRect(ctx, ctx.resolution.rectDipsToPixels(r), brush.Color, dss.head())
-	})
+	}
 }
 
 func (c *canvas) DrawRoundedRect(r math.Rect, tl, tr, bl, br float32, pen gxui.Pen, brush gxui.Brush) {
+	record := displaylist.DrawRoundedRect{Rect: r, TL: tl, TR: tr, BL: bl, BR: br, Pen: pen, Brush: brush}
 	if tl == 0 && tr == 0 && bl == 0 && br == 0 && pen.Color.A == 0 {
-		c.DrawRect(r, brush)
+		c.appendOp(record, drawRect(r, brush))
 		return
 	}
 	p := gxui.Polygon{
@@ -190,7 +275,26 @@ func (c *canvas) DrawRoundedRect(r math.Rect, tl, tr, bl, br float32, pen gxui.P
 		gxui.PolygonVertex{Position: r.BR(), RoundedRadius: br},
 		gxui.PolygonVertex{Position: r.BL(), RoundedRadius: bl},
 	}
-	c.DrawPolygon(p, pen, brush)
+	c.appendOp(record, drawPolygon(p, pen, brush))
+}
+
+func (c *canvas) DrawShadow(r math.Rect, s gxui.Shadow) {
+	c.appendOp(displaylist.DrawShadow{Rect: r, Shadow: s}, func(ctx *context, dss *drawStateStack) {
+		ds := dss.head()
+		if s.BackdropBlur > 0 {
+			// Transform from local DIPs to window pixels.
+			p := ctx.resolution.dipsToPixels()
+			m := math.CreateMat3Scale(math.Vec2{X: p, Y: p}).Mul(localToWindow(ctx, ds))
+			area, clip := m.TransformRect(r), ds.ClipPixels
+			if area.Min.X < clip.Max.X && clip.Min.X < area.Max.X && area.Min.Y < clip.Max.Y && clip.Min.Y < area.Max.Y {
+				scale := math.Sqrtf(math.Absf(m[0]*m[4] - m[1]*m[3]))
+				ctx.blitter.blurRect(ctx, area.Intersect(clip), s.BackdropBlur*scale)
+			}
+		}
+		if s.Color.A > 0 {
+			ctx.blitter.blitShadow(ctx, r, s, ds)
+		}
+	})
 }
 
 func (c *canvas) DrawTexture(t gxui.Texture, r math.Rect) {
@@ -198,7 +302,8 @@ func (c *canvas) DrawTexture(t gxui.Texture, r math.Rect) {
 		panic("Texture cannot be nil")
 	}
 
-	c.appendOp("DrawTexture", func(ctx *context, dss *drawStateStack) {
+	record := displaylist.DrawTexture{Texture: displaylist.CreateTextureRef(t), Rect: r}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
 		tc := ctx.getOrCreateTextureContext(t.(*texture))
 		ctx.blitter.blit(ctx, tc, tc.sizePixels.Rect(), ctx.resolution.rectDipsToPixels(r), dss.head())
 	})
@@ -0,0 +1,78 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gl
+
+import (
+	"sync"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// clipboard is the gxui.Clipboard of the driver. The TextMimeType entry is
+// exchanged with other applications through the clipboard of the operating
+// system, which only holds text. The entries of other types are held in
+// memory, and are only available to this application until another
+// application replaces the text of the system clipboard.
+type clipboard struct {
+	sync.Mutex
+	driver  *driver
+	entries *gxui.MemoryClipboard
+	text    string // The text last written to, or read from, the system clipboard
+}
+
+func newClipboard(d *driver) *clipboard {
+	return &clipboard{
+		driver:  d,
+		entries: gxui.CreateMemoryClipboard(),
+	}
+}
+
+// sync discards the entries held in memory if the system clipboard has been
+// changed by another application.
+func (c *clipboard) sync() {
+	var text string
+	c.driver.syncDriver(func() {
+		if v := c.driver.viewports.Front(); v != nil {
+			text = v.Value.(*viewport).window.GetClipboardString()
+		}
+	})
+	if text == c.text {
+		return
+	}
+	c.text = text
+	if text == "" {
+		c.entries.Set()
+	} else {
+		gxui.SetClipboardText(c.entries, text)
+	}
+}
+
+// gxui.Clipboard compliance
+func (c *clipboard) Formats() []string {
+	c.Lock()
+	defer c.Unlock()
+	c.sync()
+	return c.entries.Formats()
+}
+
+func (c *clipboard) Get(mimeType string) ([]byte, bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.sync()
+	return c.entries.Get(mimeType)
+}
+
+func (c *clipboard) Set(entries ...gxui.ClipboardEntry) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries.Set(entries...)
+	text, _ := gxui.ClipboardText(c.entries)
+	c.text = text
+	c.driver.asyncDriver(func() {
+		if v := c.driver.viewports.Front(); v != nil {
+			v.Value.(*viewport).window.SetClipboardString(text)
+		}
+	})
+}
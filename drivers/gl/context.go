@@ -16,6 +16,12 @@ type contextResource struct {
 	lastContextUse int // used for mark-and-sweeping the resource.
 }
 
+// rampTexture is a gradient ramp texture cached by the context.
+type rampTexture struct {
+	contextResource
+	texture *texture
+}
+
 type context struct {
 	blitter              *blitter
 	resolution           resolution
@@ -23,9 +29,12 @@ type context struct {
 	textureContexts      map[*texture]*textureContext
 	vertexStreamContexts map[*vertexStream]*vertexStreamContext
 	indexBufferContexts  map[*indexBuffer]*indexBufferContext
+	rampTextures         map[rampKey]*rampTexture
 	sizeDips, sizePixels math.Size
 	clip                 math.Rect
 	frame                int
+	framebuffer          gl.Framebuffer // The framebuffer drawn to outside of layers
+	layers               []*layer
 }
 
 func newContext() *context {
@@ -33,6 +42,7 @@ func newContext() *context {
 		textureContexts:      make(map[*texture]*textureContext),
 		vertexStreamContexts: make(map[*vertexStream]*vertexStreamContext),
 		indexBufferContexts:  make(map[*indexBuffer]*indexBufferContext),
+		rampTextures:         make(map[rampKey]*rampTexture),
 	}
 	ctx.blitter = newBlitter(ctx, &ctx.stats)
 	return ctx
@@ -54,6 +64,9 @@ func (c *context) destroy() {
 		ic.destroy()
 		c.stats.indexBufferCount--
 	}
+	for key := range c.rampTextures {
+		delete(c.rampTextures, key)
+	}
 	c.blitter.destroy(c)
 	c.blitter = nil
 }
@@ -66,6 +79,7 @@ func (c *context) beginDraw(sizeDips, sizePixels math.Size) {
 	c.resolution = resolution(dipsToPixels*65536 + 0.5)
 
 	c.stats.drawCallCount = 0
+	c.stats.canvasOps = make(map[string]int)
 	c.stats.timer("Frame").start()
 }
 
@@ -92,6 +106,11 @@ func (c *context) endDraw() {
 			c.stats.indexBufferCount--
 		}
 	}
+	for key, rt := range c.rampTextures {
+		if rt.lastContextUse != c.frame {
+			delete(c.rampTextures, key)
+		}
+	}
 
 	c.stats.timer("Frame").stop()
 	c.stats.frameCount++
@@ -109,6 +128,19 @@ func (c *context) getOrCreateTextureContext(t *texture) *textureContext {
 	return tc
 }
 
+// getOrCreateRampTexture returns the gradient ramp texture of the paint p,
+// creating it if no gradient with the same stops has been drawn by the
+// context in the last frame.
+func (c *context) getOrCreateRampTexture(p *brushPaint) *texture {
+	rt, found := c.rampTextures[p.ramp]
+	if !found {
+		rt = &rampTexture{texture: newRampTexture(p.brush.Gradient)}
+		c.rampTextures[p.ramp] = rt
+	}
+	rt.lastContextUse = c.frame
+	return rt.texture
+}
+
 func (c *context) getOrCreateVertexStreamContext(vs *vertexStream) *vertexStreamContext {
 	vc, found := c.vertexStreamContexts[vs]
 	if !found {
@@ -9,10 +9,12 @@ import (
 	"container/list"
 	"image"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/internal/frameclock"
 
 	"github.com/robertt-smg/gxui/math"
 
@@ -53,11 +55,17 @@ type driver struct {
 	pendingApp    *CallQueue
 	terminated    int32 // non-zero represents driver terminations
 	viewports     *list.List
+	clipboard     *clipboard
 
 	pcs  []uintptr // reusable scratch-buffer for use by runtime.Callers.
 	uiPC uintptr   // the program-counter of the applicationLoop function.
 
 	debug bool
+
+	statsMutex sync.Mutex
+	stats      gxui.DriverStats
+
+	frameClock *frameclock.Clock
 }
 
 // StartDriver starts the gl driver with the given appRoutine.
@@ -77,6 +85,8 @@ func StartDriver(appRoutine func(driver gxui.Driver), opts ...Opt) {
 		viewports:     list.New(),
 		pcs:           make([]uintptr, 256),
 	}
+	d.clipboard = newClipboard(d)
+	d.frameClock = frameclock.New(d.Call)
 	for _, opt := range opts {
 		d = opt.Apply(d).(*driver)
 	}
@@ -156,6 +166,17 @@ func (d *driver) Call(f func()) bool {
 	return true
 }
 
+func (d *driver) CallWhenIdle(f func()) bool {
+	if f == nil {
+		panic("Function must not be nil")
+	}
+	if atomic.LoadInt32(&d.terminated) != 0 {
+		return false // Driver.Terminate has been called
+	}
+	d.pendingApp.InjectIdle(f)
+	return true
+}
+
 func (d *driver) CallSync(f func()) bool {
 	if d.isUIGoroutine() {
 		f()
@@ -215,18 +236,16 @@ func (d *driver) Terminate() {
 	})
 }
 
+func (d *driver) Clipboard() gxui.Clipboard {
+	return d.clipboard
+}
+
 func (d *driver) SetClipboard(str string) {
-	d.asyncDriver(func() {
-		v := d.viewports.Front().Value.(*viewport)
-		v.window.SetClipboardString(str)
-	})
+	gxui.SetClipboardText(d.clipboard, str)
 }
 
-func (d *driver) GetClipboard() (str string, err error) {
-	d.syncDriver(func() {
-		c := d.viewports.Front().Value.(*viewport)
-		str = c.window.GetClipboardString()
-	})
+func (d *driver) GetClipboard() (string, error) {
+	str, _ := gxui.ClipboardText(d.clipboard)
 	return str, nil
 }
 
@@ -265,3 +284,34 @@ func (d *driver) CreateCanvas(s math.Size) gxui.Canvas {
 func (d *driver) CreateTexture(img image.Image, pixelsPerDip float32) gxui.Texture {
 	return newTexture(img, pixelsPerDip)
 }
+
+// gxui.Driver compliance
+func (d *driver) OnFrame(f func(t time.Duration)) gxui.EventSubscription {
+	d.AssertUIGoroutine()
+	return d.frameClock.Listen(f)
+}
+
+func (d *driver) Stats() gxui.DriverStats {
+	d.statsMutex.Lock()
+	defer d.statsMutex.Unlock()
+	s := d.stats
+	s.CanvasOps = make(map[string]int, len(d.stats.CanvasOps))
+	for name, n := range d.stats.CanvasOps {
+		s.CanvasOps[name] = n
+	}
+	s.GlyphPages = int(atomic.LoadInt32(&globalStats.glyphPageCount.value))
+	s.Textures = int(atomic.LoadInt32(&globalStats.textureContextCount.value))
+	s.TextureBytes = atomic.LoadInt64(&globalStats.textureBytes)
+	return s
+}
+
+// addFrameStats accumulates the statistics of the frame just drawn by a
+// viewport's context.
+// Called on the driver routine.
+func (d *driver) addFrameStats(s *contextStats) {
+	d.statsMutex.Lock()
+	defer d.statsMutex.Unlock()
+	d.stats.AddFrame(s.timer("Frame").last)
+	d.stats.DrawCalls = s.drawCallCount
+	d.stats.CanvasOps = s.canvasOps
+}
@@ -23,6 +23,8 @@ type font struct {
 	glyphMaxSizeDips math.Size
 	ascentDips       int
 	ttf              *truetype.Font
+	family           string
+	data             []byte
 	resolutions      map[resolution]*glyphTable
 	glyphAdvanceDips map[rune]int
 }
@@ -43,6 +45,8 @@ func newFont(data []byte, size int) (*font, error) {
 		glyphMaxSizeDips: bounds.Size(),
 		ascentDips:       ascentDips,
 		ttf:              ttf,
+		family:           ttf.Name(truetype.NameIDFontFamily),
+		data:             data,
 		resolutions:      make(map[resolution]*glyphTable),
 		glyphAdvanceDips: make(map[rune]int),
 	}, nil
@@ -124,6 +128,15 @@ func (f *font) DrawRunes(ctx *context, runes []rune, offsets []math.Point, col g
 	}
 }
 
+func (f *font) Family() string {
+	return f.family
+}
+
+// gxui.TrueTypeFont compliance
+func (f *font) TrueTypeData() []byte {
+	return f.data
+}
+
 func (f *font) Index(r rune) truetype.Index {
 	return f.ttf.Index(r)
 }
@@ -65,6 +65,7 @@ func newGlyphPage(face fnt.Face, r rune) *glyphPage {
 		rowHeight: 0,
 	}
 	page.add(face, r)
+	globalStats.glyphPageCount.inc()
 	return page
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gl
+
+import (
+	"fmt"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+
+	"github.com/goxjs/gl"
+)
+
+// layer is an off-screen framebuffer drawn to between a PushLayer and the
+// matching PopLayer. The layer's texture is the size of the window, so that
+// drawing into it uses the same coordinates as drawing to the window.
+type layer struct {
+	framebuffer gl.Framebuffer
+	texture     *textureContext
+	clip        math.Rect // In window pixels
+	opacity     float32
+	blend       gxui.BlendMode
+}
+
+// pushLayer redirects drawing to a new transparent layer, which is
+// composited onto the current framebuffer within clip by popLayer.
+func (c *context) pushLayer(clip math.Rect, opacity float32, blend gxui.BlendMode) {
+	c.blitter.commit(c)
+
+	w, h := c.sizePixels.WH()
+	texture := gl.CreateTexture()
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, gl.Texture{})
+
+	fb := gl.CreateFramebuffer()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fb)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, texture, 0)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		panic(fmt.Errorf("Layer framebuffer incomplete. Status: 0x%x", status))
+	}
+
+	// Only the clipped area of the layer is composited, so only that needs
+	// clearing.
+	c.apply(&drawState{ClipPixels: clip})
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	checkError()
+
+	c.layers = append(c.layers, &layer{
+		framebuffer: fb,
+		texture: &textureContext{
+			texture:    texture,
+			sizePixels: c.sizePixels,
+			flipY:      true,
+			pma:        true,
+		},
+		clip:    clip,
+		opacity: opacity,
+		blend:   blend,
+	})
+}
+
+// popLayer composites the layer pushed by the last call to pushLayer onto
+// the framebuffer beneath it, and releases the layer.
+func (c *context) popLayer() {
+	c.blitter.commit(c)
+
+	l := c.layers[len(c.layers)-1]
+	c.layers = c.layers[:len(c.layers)-1]
+	gl.BindFramebuffer(gl.FRAMEBUFFER, c.currentFramebuffer())
+
+	c.apply(&drawState{ClipPixels: l.clip})
+	c.blitter.blitLayer(c, l.texture, l.clip, l.opacity, l.blend)
+
+	gl.DeleteFramebuffer(l.framebuffer)
+	gl.DeleteTexture(l.texture.texture)
+	checkError()
+}
+
+// currentFramebuffer returns the framebuffer of the innermost layer, or the
+// context's framebuffer outside of layers.
+func (c *context) currentFramebuffer() gl.Framebuffer {
+	if len(c.layers) > 0 {
+		return c.layers[len(c.layers)-1].framebuffer
+	}
+	return c.framebuffer
+}
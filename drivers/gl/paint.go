@@ -0,0 +1,117 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gl
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	gomath "math"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// gradientRampSize is the number of texels used to hold the colors of a
+// gradient.
+const gradientRampSize = 256
+
+// brushPaint holds the resources used to fill with a gradient or pattern
+// gxui.Brush.
+type brushPaint struct {
+	brush   gxui.Brush
+	ramp    rampKey  // Identifies the ramp texture of a gradient brush
+	pattern *texture // Texture of a pattern brush
+}
+
+// rampKey identifies the colors of a gradient ramp texture, which only depend
+// on the gradient stops. Gradients with equal stops share a ramp texture.
+type rampKey string
+
+// gradientRampKey returns the rampKey of the stops of g.
+func gradientRampKey(g *gxui.Gradient) rampKey {
+	b := make([]byte, 0, len(g.Stops)*5*4)
+	for _, s := range g.Stops {
+		for _, f := range []float32{s.Offset, s.Color.R, s.Color.G, s.Color.B, s.Color.A} {
+			b = binary.LittleEndian.AppendUint32(b, gomath.Float32bits(f))
+		}
+	}
+	return rampKey(b)
+}
+
+// newRampTexture returns the texture holding the colors of the gradient g,
+// sampled by gradient offset.
+func newRampTexture(g *gxui.Gradient) *texture {
+	img := image.NewRGBA(image.Rect(0, 0, gradientRampSize, 1))
+	for x := 0; x < gradientRampSize; x++ {
+		c := g.ColorAt(float32(x) / (gradientRampSize - 1)).Saturate()
+		img.SetRGBA(x, 0, color.RGBA{
+			R: uint8(c.R*c.A*255 + 0.5),
+			G: uint8(c.G*c.A*255 + 0.5),
+			B: uint8(c.B*c.A*255 + 0.5),
+			A: uint8(c.A*255 + 0.5),
+		})
+	}
+	return newTexture(img, 1)
+}
+
+// newBrushPaint returns the brushPaint for b, or nil if b is a solid brush.
+func newBrushPaint(b gxui.Brush) *brushPaint {
+	switch {
+	case b.Gradient != nil:
+		return &brushPaint{brush: b, ramp: gradientRampKey(b.Gradient)}
+	case b.Pattern != nil && b.Pattern.Texture != nil:
+		return &brushPaint{brush: b, pattern: b.Pattern.Texture.(*texture)}
+	default:
+		return nil
+	}
+}
+
+// matrix returns the transform from vertex positions to paint space: the
+// gradient offset in x for linear gradients, the vector from the center in
+// units of the radius for radial gradients, or the texture coordinates for
+// patterns. Vertex positions map to the unit square of the filled shape's
+// bounds as unit = scale * position + offset, and size is the size of the
+// bounds in DIPs.
+func (p *brushPaint) matrix(scale, offset math.Vec2, size math.Size) math.Mat3 {
+	// The result is x' = a·x + c·y + e, y' = b·x + d·y + f
+	var a, b, c, d, e, f float32
+	switch {
+	case p.pattern != nil:
+		t := p.pattern.Size().Vec2()
+		s := size.Vec2().Div(t)
+		a, d = scale.X*s.X, scale.Y*s.Y
+		e, f = offset.X*s.X, offset.Y*s.Y
+	case p.brush.Gradient.Kind == gxui.RadialGradient:
+		g := p.brush.Gradient
+		r := g.Radius
+		if r <= 0 {
+			r = 1e-6
+		}
+		a, d = scale.X/r, scale.Y/r
+		e, f = (offset.X-g.Start.X)/r, (offset.Y-g.Start.Y)/r
+	default:
+		g := p.brush.Gradient
+		dir := g.End.Sub(g.Start)
+		l := dir.SqrLen()
+		if l == 0 {
+			// Degenerate gradient, use the last stop everywhere.
+			return math.CreateMat3(0, 0, 0, 0, 0, 0, 1, 0, 1)
+		}
+		n := dir.DivS(l)
+		a, c = n.X*scale.X, n.Y*scale.Y
+		e = n.X*offset.X + n.Y*offset.Y - n.Dot(g.Start)
+	}
+	return math.CreateMat3(
+		a, b, 0,
+		c, d, 0,
+		e, f, 1,
+	)
+}
+
+func (p *brushPaint) isRadial() bool {
+	return p.brush.Gradient != nil && p.brush.Gradient.Kind == gxui.RadialGradient
+}
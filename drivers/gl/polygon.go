@@ -6,6 +6,7 @@ package gl
 
 import (
 	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/internal/stroke"
 
 	"github.com/robertt-smg/gxui/math"
 )
@@ -29,7 +30,40 @@ func pruneDuplicates(p gxui.Polygon) gxui.Polygon {
 	return pruned
 }
 
-func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []float32, fillEdge []math.Vec2) ([]float32, []math.Vec2) {
+// edgePairs returns the triangle strip vertices as edge pairs.
+func edgePairs(vsEdgePos []float32) []stroke.EdgePair {
+	pairs := make([]stroke.EdgePair, 0, len(vsEdgePos)/4)
+	for i := 0; i+3 < len(vsEdgePos); i += 4 {
+		pairs = append(pairs, stroke.EdgePair{
+			Outer: math.Vec2{X: vsEdgePos[i+0], Y: vsEdgePos[i+1]},
+			Inner: math.Vec2{X: vsEdgePos[i+2], Y: vsEdgePos[i+3]},
+		})
+	}
+	return pairs
+}
+
+// stripVertices returns the vertices of a single triangle strip holding all
+// the strips of edge pairs. Consecutive strips are joined by degenerate
+// triangles.
+func stripVertices(strips [][]stroke.EdgePair) []float32 {
+	vsEdgePos := []float32{}
+	for _, strip := range strips {
+		if len(strip) < 2 {
+			continue
+		}
+		if len(vsEdgePos) > 0 {
+			last := vsEdgePos[len(vsEdgePos)-2:]
+			vsEdgePos = append(vsEdgePos, last[0], last[1])
+			vsEdgePos = appendVec2(vsEdgePos, strip[0].Outer)
+		}
+		for _, e := range strip {
+			vsEdgePos = appendVec2(vsEdgePos, e.Outer, e.Inner)
+		}
+	}
+	return vsEdgePos
+}
+
+func segment(penWidth, r float32, join gxui.LineJoin, a, b, c math.Vec2, aIsLast bool, vsEdgePos []float32, fillEdge []math.Vec2) ([]float32, []math.Vec2) {
 	ba, ca := a.Sub(b), a.Sub(c)
 	baLen, caLen := ba.Len(), ca.Len()
 	baDir, caDir := ba.DivS(baLen), ca.DivS(caLen)
@@ -67,6 +101,12 @@ func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []f
 	// ╚═══════════════════════════╩════════════════╝
 	v := baDir.Add(caDir).Normalize()
 	u := v.Tangent()
+	convex := baDir.Tangent().Dot(caDir) <= 0
+	if convex && join == gxui.RoundJoin {
+		// Rounding the outside of the corner by the pen width leaves the
+		// inner edge of the stroke sharp.
+		r = math.Maxf(r, penWidth)
+	}
 	//
 	// cos(2 • α) = dp
 	//
@@ -94,8 +134,6 @@ func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []f
 
 	x := a.Sub(v.MulS(d))
 
-	convex := baDir.Tangent().Dot(caDir) <= 0
-
 	w := penWidth
 	β := math.Pi/2 - α
 
@@ -106,6 +144,19 @@ func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []f
 	useFixedInnerPoint := convex && w > r
 	fixedInnerPoint := a.Sub(v.MulS(math.Minf(w/math.Sinf(α), dMax)))
 
+	if useFixedInnerPoint && r == 0 && join == gxui.BevelJoin {
+		// Cut the corner between the feet of the perpendiculars from the
+		// inner point to each edge.
+		t := math.Minf(w/math.Tanf(α), math.Minf(baLen, caLen)/2)
+		vsEdgePos = appendVec2(vsEdgePos,
+			a.Sub(baDir.MulS(t)), fixedInnerPoint,
+			a.Sub(caDir.MulS(t)), fixedInnerPoint)
+		if fillEdge != nil {
+			fillEdge = append(fillEdge, fixedInnerPoint)
+		}
+		return vsEdgePos, fillEdge
+	}
+
 	// Concave vertices behave much the same as convex, but we have to flip
 	// β as the sweep is reversed and w as we're extruding.
 	if !convex {
@@ -134,7 +185,9 @@ func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []f
 		}
 
 		vsEdgePos = appendVec2(vsEdgePos, va, vb)
-		if fillEdge != nil {
+		if fillEdge != nil && (j == 0 || vb != fillEdge[len(fillEdge)-1]) {
+			// Inner points coincide where the stroke is as wide as the
+			// rounding, which cannot be triangulated.
 			fillEdge = append(fillEdge, vb)
 		}
 	}
@@ -142,7 +195,7 @@ func segment(penWidth, r float32, a, b, c math.Vec2, aIsLast bool, vsEdgePos []f
 	return vsEdgePos, fillEdge
 }
 
-func closedPolyToShape(p gxui.Polygon, penWidth float32) (fillShape, edgeShape *shape) {
+func closedPolyToShape(p gxui.Polygon, pen gxui.Pen) (fillShape, edgeShape *shape) {
 	p = pruneDuplicates(p)
 
 	fillEdge := []math.Vec2{}
@@ -153,13 +206,16 @@ func closedPolyToShape(p gxui.Polygon, penWidth float32) (fillShape, edgeShape *
 		a := p[i].Position.Vec2()
 		b := p[(i+cnt-1)%cnt].Position.Vec2()
 		c := p[(i+1)%cnt].Position.Vec2()
-		vsEdgePos, fillEdge = segment(penWidth, r, a, b, c, i == len(p), vsEdgePos, fillEdge)
+		vsEdgePos, fillEdge = segment(pen.Width, r, pen.Join, a, b, c, i == len(p), vsEdgePos, fillEdge)
 	}
 
 	// Close the edge
 	if len(vsEdgePos) >= 4 {
 		vsEdgePos = append(vsEdgePos, vsEdgePos[:4]...)
 	}
+	if pen.IsDashed() {
+		vsEdgePos = stripVertices(stroke.Strips(edgePairs(vsEdgePos), pen, true))
+	}
 
 	fillTris := triangulate(fillEdge)
 	if len(fillTris) > 0 {
@@ -182,8 +238,9 @@ func closedPolyToShape(p gxui.Polygon, penWidth float32) (fillShape, edgeShape *
 	return fillShape, edgeShape
 }
 
-func openPolyToShape(p gxui.Polygon, penWidth float32) *shape {
+func openPolyToShape(p gxui.Polygon, pen gxui.Pen) *shape {
 	p = pruneDuplicates(p)
+	penWidth := pen.Width
 	if len(p) < 2 {
 		return nil
 	}
@@ -201,7 +258,7 @@ func openPolyToShape(p gxui.Polygon, penWidth float32) *shape {
 		a := p[i].Position.Vec2()
 		b := p[i-1].Position.Vec2()
 		c := p[i+1].Position.Vec2()
-		vsEdgePos, _ = segment(penWidth, r, a, b, c, false, vsEdgePos, nil)
+		vsEdgePos, _ = segment(penWidth, r, pen.Join, a, b, c, false, vsEdgePos, nil)
 	}
 	{ // p[N-2] -> p[N-1]
 		a, c := p[len(p)-2].Position.Vec2(), p[len(p)-1].Position.Vec2()
@@ -209,6 +266,9 @@ func openPolyToShape(p gxui.Polygon, penWidth float32) *shape {
 		inner := c.Sub(caDir.Tangent().MulS(penWidth))
 		vsEdgePos = appendVec2(vsEdgePos, c, inner)
 	}
+	if pen.IsDashed() || pen.Cap != gxui.ButtCap {
+		vsEdgePos = stripVertices(stroke.Strips(edgePairs(vsEdgePos), pen, false))
+	}
 	if len(vsEdgePos) > 0 {
 		return newShape(newVertexBuffer(
 			newVertexStream("aPosition", stFloatVec2, vsEdgePos),
@@ -0,0 +1,100 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gl
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/robertt-smg/gxui"
+
+	glfw32 "github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/goxjs/gl"
+	"github.com/goxjs/glfw"
+)
+
+// gxui.CanvasRasterizer compliance
+func (d *driver) RasterizeCanvas(cc gxui.Canvas, pixelsPerDip float32) *image.RGBA {
+	c := cc.(*canvas)
+	sizeDips := c.Size()
+	sizePixels := sizeDips.ScaleS(pixelsPerDip)
+	w, h := sizePixels.WH()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	d.syncDriver(func() {
+		// Rendering requires a GL context, so borrow one from a hidden window.
+		prev := glfw32.GetCurrentContext()
+		glfw.DefaultWindowHints()
+		glfw32.WindowHint(glfw32.Visible, glfw32.False)
+		wnd, err := glfw.CreateWindow(1, 1, "", nil, nil)
+		if err != nil {
+			panic(err)
+		}
+		wnd.MakeContextCurrent()
+		defer func() {
+			wnd.Destroy()
+			if prev != nil {
+				(&glfw.Window{Window: prev}).MakeContextCurrent()
+			}
+		}()
+
+		target := gl.CreateTexture()
+		gl.BindTexture(gl.TEXTURE_2D, target)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		gl.BindTexture(gl.TEXTURE_2D, gl.Texture{})
+		defer gl.DeleteTexture(target)
+
+		fb := gl.CreateFramebuffer()
+		gl.BindFramebuffer(gl.FRAMEBUFFER, fb)
+		defer gl.DeleteFramebuffer(fb)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, target, 0)
+		if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+			panic(fmt.Errorf("Framebuffer incomplete. Status: 0x%x", status))
+		}
+
+		// Pre-multiplied alpha blending
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+		gl.Enable(gl.BLEND)
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Viewport(0, 0, w, h)
+		gl.Scissor(0, 0, int32(w), int32(h))
+		gl.ClearColor(0, 0, 0, 0)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+
+		ctx := newContext()
+		ctx.framebuffer = fb
+		defer ctx.destroy()
+		ctx.beginDraw(sizeDips, sizePixels)
+
+		dss := drawStateStack{drawState{
+			ClipPixels: sizePixels.Rect(),
+		}}
+		c.draw(ctx, &dss)
+		if len(dss) != 1 {
+			panic("DrawStateStack count was not 1 after calling Canvas.Draw")
+		}
+		ctx.apply(dss.head())
+		ctx.blitter.commit(ctx)
+		ctx.endDraw()
+
+		gl.ReadPixels(img.Pix, 0, 0, w, h, gl.RGBA, gl.UNSIGNED_BYTE)
+		checkError()
+	})
+
+	// GL framebuffers are stored bottom row first.
+	stride := img.Stride
+	row := make([]byte, stride)
+	for y := 0; y < h/2; y++ {
+		a := img.Pix[y*stride : (y+1)*stride]
+		b := img.Pix[(h-1-y)*stride : (h-y)*stride]
+		copy(row, a)
+		copy(a, b)
+		copy(b, row)
+	}
+	return img
+}
@@ -58,6 +58,8 @@ type globalDriverStats struct {
 	vertexStreamContextCount count
 	indexBufferContextCount  count
 	textureContextCount      count
+	textureBytes             int64 // Memory used by texture contexts
+	glyphPageCount           count
 }
 
 func (s *globalDriverStats) get() string {
@@ -65,6 +67,8 @@ func (s *globalDriverStats) get() string {
 	fmt.Fprintf(buffer, "Vertex stream context count: %v\n", s.vertexStreamContextCount)
 	fmt.Fprintf(buffer, "Index buffer context count: %v\n", s.indexBufferContextCount)
 	fmt.Fprintf(buffer, "Texture context count: %v\n", s.textureContextCount)
+	fmt.Fprintf(buffer, "Texture bytes: %d\n", atomic.LoadInt64(&s.textureBytes))
+	fmt.Fprintf(buffer, "Glyph page count: %v\n", s.glyphPageCount)
 	s.vertexStreamContextCount.resetDeltas()
 	s.indexBufferContextCount.resetDeltas()
 	s.textureContextCount.resetDeltas()
@@ -80,6 +84,7 @@ type timer struct {
 	history  [historySize]time.Duration
 	timer    time.Time
 	current  int
+	last     time.Duration // Duration of the most recent start-stop
 }
 
 func (t *timer) start() {
@@ -92,6 +97,7 @@ func (t *timer) stop() {
 	t.history[t.current] = duration
 	t.total += t.history[t.current]
 	t.duration = t.total / historySize
+	t.last = duration
 	t.current++
 	if t.current >= historySize {
 		t.current = 0
@@ -120,6 +126,7 @@ type contextStats struct {
 	shaderProgramCount int
 	frameCount         int
 	drawCallCount      int
+	canvasOps          map[string]int // Canvas ops executed by the current frame, by name
 	timers             []timer
 }
 
@@ -6,6 +6,7 @@ package gl
 
 import (
 	"image"
+	"sync/atomic"
 
 	"github.com/robertt-smg/gxui/math"
 
@@ -71,6 +72,10 @@ func (t *texture) newContext() *textureContext {
 	texture := gl.CreateTexture()
 	gl.BindTexture(gl.TEXTURE_2D, texture)
 	w, h := t.SizePixels().WH()
+	bytes := w * h * 4
+	if fmt == gl.ALPHA {
+		bytes = w * h
+	}
 	gl.TexImage2D(gl.TEXTURE_2D, 0, w, h, fmt, gl.UNSIGNED_BYTE, data)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
@@ -78,11 +83,13 @@ func (t *texture) newContext() *textureContext {
 	checkError()
 
 	globalStats.textureContextCount.inc()
+	atomic.AddInt64(&globalStats.textureBytes, int64(bytes))
 	return &textureContext{
 		texture:    texture,
 		sizePixels: t.Size(),
 		flipY:      t.flipY,
 		pma:        pma,
+		bytes:      bytes,
 	}
 }
 
@@ -92,10 +99,12 @@ type textureContext struct {
 	sizePixels math.Size
 	flipY      bool
 	pma        bool
+	bytes      int // Memory used by the texture, counted by globalStats
 }
 
 func (c *textureContext) destroy() {
 	globalStats.textureContextCount.dec()
+	atomic.AddInt64(&globalStats.textureBytes, -int64(c.bytes))
 	gl.DeleteTexture(c.texture)
 	c.texture = gl.Texture{}
 }
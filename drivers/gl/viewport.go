@@ -47,6 +47,13 @@ type viewport struct {
 	scrollAccumY            float64
 	destroyed               bool
 	redrawCount             uint32
+	pendingDamage           []math.Rect // Damage of the canvases not yet rendered
+	pendingFull             bool        // True if a canvas not yet rendered needs a full redraw
+	frame                   gl.Texture  // Copy of the last rendered frame
+	frameSize               math.Size   // Size of frame in pixels
+	stats                   gxui.RedrawStats
+	cursors                 map[glfw32.StandardCursor]*glfw32.Cursor // Only accessed on the driver thread
+	customCursor            *glfw32.Cursor                           // Only accessed on the driver thread
 
 	// Broadcasts to application thread
 	onClose       gxui.Event // ()
@@ -61,6 +68,8 @@ type viewport struct {
 	onKeyUp       gxui.Event // (gxui.KeyboardEvent)
 	onKeyRepeat   gxui.Event // (gxui.KeyboardEvent)
 	onKeyStroke   gxui.Event // (gxui.KeyStrokeEvent)
+	onComposition gxui.Event // (gxui.CompositionEvent)
+	onFileDrop    gxui.Event // ([]string, math.Point)
 	// Broadcasts to driver thread
 	onDestroy gxui.Event
 }
@@ -223,6 +232,9 @@ func newViewport(driver *driver, width, height int, title string, fullscreen boo
 		}
 		v.onKeyStroke.Fire(ev)
 	})
+	wnd.SetDropCallback(func(w *glfw.Window, names []string) {
+		v.onFileDrop.Fire(names, cursorPoint(w.GetCursorPos()))
+	})
 	wnd.SetRefreshCallback(func(w *glfw.Window) {
 		if v.canvas != nil {
 			v.render()
@@ -256,6 +268,8 @@ func newViewport(driver *driver, width, height int, title string, fullscreen boo
 	v.onKeyUp = driver.createAppEvent(func(gxui.KeyboardEvent) {})
 	v.onKeyRepeat = driver.createAppEvent(func(gxui.KeyboardEvent) {})
 	v.onKeyStroke = driver.createAppEvent(func(gxui.KeyStrokeEvent) {})
+	v.onComposition = driver.createAppEvent(func(gxui.CompositionEvent) {})
+	v.onFileDrop = driver.createAppEvent(func([]string, math.Point) {})
 	v.onDestroy = driver.createDriverEvent(func() {})
 	v.sizeDipsUnscaled = math.Size{W: width, H: height}
 	v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / v.scaling)
@@ -287,14 +301,107 @@ func (v *viewport) render() {
 
 	ctx.apply(dss.head())
 	ctx.blitter.commit(ctx)
+	v.storeFrame(v.sizePixels.Rect())
+
+	if viewportDebugEnabled {
+		v.drawFrameUpdate(ctx)
+	}
+
+	ctx.endDraw()
+	v.driver.addFrameStats(&ctx.stats)
+
+	v.Lock()
+	v.stats.Add(v.sizePixels, v.sizePixels.Area(), true)
+	v.Unlock()
+
+	v.window.SwapBuffers()
+	v.driver.frameClock.Presented()
+}
+
+// renderDamaged draws the frame stored by the last render, then redraws the
+// damaged rectangles of the canvas, in DIPs, over it.
+func (v *viewport) renderDamaged(damage []math.Rect) {
+	if v.destroyed {
+		return
+	}
+	if v.frameSize != v.sizePixels || !v.frame.Valid() {
+		v.render()
+		return
+	}
+
+	v.window.MakeContextCurrent()
+
+	ctx := v.context
+	sizeDips, sizePixels := v.SizeDips(), v.SizePixels()
+	ctx.beginDraw(sizeDips, sizePixels)
+
+	all := sizePixels.Rect()
+	ctx.apply(&drawState{ClipPixels: all})
+	ctx.blitter.blitLayer(ctx, &textureContext{
+		texture:    v.frame,
+		sizePixels: sizePixels,
+		flipY:      true,
+		pma:        true,
+	}, all, 1, gxui.NormalBlend)
+
+	drawn := 0
+	pixelsPerDip := float32(sizePixels.W) / float32(sizeDips.W)
+	for _, r := range gxui.DamagePixels(damage, pixelsPerDip, sizePixels) {
+		ctx.blitter.commit(ctx)
+		ctx.apply(&drawState{ClipPixels: r})
+		gl.ClearColor(clearColorR, clearColorG, clearColorB, 1.0)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+
+		dss := drawStateStack{drawState{ClipPixels: r}}
+		v.canvas.draw(ctx, &dss)
+		if len(dss) != 1 {
+			panic("DrawStateStack count was not 1 after calling Canvas.Draw")
+		}
+		ctx.apply(dss.head())
+		ctx.blitter.commit(ctx)
+		v.storeFrame(r)
+		drawn += r.Size().Area()
+	}
 
 	if viewportDebugEnabled {
 		v.drawFrameUpdate(ctx)
 	}
 
 	ctx.endDraw()
+	v.driver.addFrameStats(&ctx.stats)
+
+	v.Lock()
+	v.stats.Add(sizePixels, drawn, false)
+	v.Unlock()
 
 	v.window.SwapBuffers()
+	v.driver.frameClock.Presented()
+}
+
+// storeFrame copies the rectangle r, in pixels, of the rendered frame into
+// the frame texture, which is drawn beneath the damaged rectangles of the
+// following frames.
+func (v *viewport) storeFrame(r math.Rect) {
+	w, h := v.sizePixels.WH()
+	if v.frameSize != v.sizePixels || !v.frame.Valid() {
+		if v.frame.Valid() {
+			gl.DeleteTexture(v.frame)
+		}
+		v.frame = gl.CreateTexture()
+		gl.BindTexture(gl.TEXTURE_2D, v.frame)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		gl.CopyTexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 0, 0, w, h, 0)
+		v.frameSize = v.sizePixels
+	} else {
+		// GL framebuffers are stored bottom row first.
+		gl.BindTexture(gl.TEXTURE_2D, v.frame)
+		gl.CopyTexSubImage2D(gl.TEXTURE_2D, 0, r.Min.X, h-r.Max.Y, r.Min.X, h-r.Max.Y, r.W(), r.H())
+	}
+	gl.BindTexture(gl.TEXTURE_2D, gl.Texture{})
+	checkError()
 }
 
 func (v *viewport) drawFrameUpdate(ctx *context) {
@@ -307,15 +414,43 @@ func (v *viewport) drawFrameUpdate(ctx *context) {
 // gxui.viewport compliance
 // These methods are all called on the application routine
 func (v *viewport) SetCanvas(cc gxui.Canvas) {
+	v.setCanvas(cc, nil, true)
+}
+
+func (v *viewport) SetCanvasDamaged(cc gxui.Canvas, damage []math.Rect) {
+	v.setCanvas(cc, damage, false)
+}
+
+func (v *viewport) RedrawStats() gxui.RedrawStats {
+	v.Lock()
+	defer v.Unlock()
+	return v.stats
+}
+
+func (v *viewport) setCanvas(cc gxui.Canvas, damage []math.Rect, full bool) {
 	cnt := atomic.AddUint32(&v.redrawCount, 1)
 	c := cc.(*canvas)
+	// The damage of skipped canvases is redrawn with the next canvas.
+	v.Lock()
+	v.pendingDamage = append(v.pendingDamage, damage...)
+	v.pendingFull = v.pendingFull || full
+	v.Unlock()
 	v.driver.asyncDriver(func() {
 		// Only use the canvas of the most recent SetCanvas call.
 		v.window.MakeContextCurrent()
 		if atomic.LoadUint32(&v.redrawCount) == cnt {
+			v.Lock()
+			damage, full := v.pendingDamage, v.pendingFull
+			v.pendingDamage, v.pendingFull = nil, false
+			v.Unlock()
 			v.canvas = c
-			if v.canvas != nil {
+			if v.canvas == nil {
+				return
+			}
+			if full {
 				v.render()
+			} else {
+				v.renderDamaged(damage)
 			}
 		}
 	})
@@ -402,10 +537,53 @@ func (v *viewport) SetPosition(pos math.Point) {
 	})
 }
 
-func (v *viewport) SetCursor(c *glfw32.Cursor) {
-	v.window.SetCursor(c)
+// standardCursors maps the gxui cursor shapes to the closest GLFW standard
+// cursor. Shapes without an equivalent use the arrow.
+var standardCursors = map[gxui.CursorShape]glfw32.StandardCursor{
+	gxui.IBeamCursor:      glfw32.IBeamCursor,
+	gxui.HandCursor:       glfw32.HandCursor,
+	gxui.ResizeNSCursor:   glfw32.VResizeCursor,
+	gxui.ResizeEWCursor:   glfw32.HResizeCursor,
+	gxui.ResizeNWSECursor: glfw32.CrosshairCursor,
+	gxui.MoveCursor:       glfw32.CrosshairCursor,
+}
+
+func (v *viewport) SetCursor(c gxui.Cursor) {
+	v.driver.asyncDriver(func() {
+		if v.destroyed {
+			return
+		}
+		if v.customCursor != nil {
+			v.window.SetCursor(nil)
+			v.customCursor.Destroy()
+			v.customCursor = nil
+		}
+		if c.Shape == gxui.CustomCursor {
+			v.customCursor = glfw32.CreateCursor(c.Image, c.Hotspot.X, c.Hotspot.Y)
+			v.window.SetCursor(v.customCursor)
+			return
+		}
+		shape, found := standardCursors[c.Shape]
+		if !found {
+			shape = glfw32.ArrowCursor
+		}
+		cursor, found := v.cursors[shape]
+		if !found {
+			if v.cursors == nil {
+				v.cursors = make(map[glfw32.StandardCursor]*glfw32.Cursor)
+			}
+			cursor = glfw32.CreateStandardCursor(shape)
+			v.cursors[shape] = cursor
+		}
+		v.window.SetCursor(cursor)
+	})
 }
 
+// GLFW does not expose the pre-edit text of input methods, which commit their
+// text with key-strokes. Composition events are never raised, and composition
+// rectangles are ignored.
+func (v *viewport) SetCompositionRect(r math.Rect) {}
+
 func (v *viewport) Fullscreen() bool {
 	return v.fullscreen
 }
@@ -471,13 +649,30 @@ func (v *viewport) OnKeyStroke(f func(gxui.KeyStrokeEvent)) gxui.EventSubscripti
 	return v.onKeyStroke.Listen(f)
 }
 
+func (v *viewport) OnComposition(f func(gxui.CompositionEvent)) gxui.EventSubscription {
+	return v.onComposition.Listen(f)
+}
+
+func (v *viewport) OnFileDrop(f func(paths []string, at math.Point)) gxui.EventSubscription {
+	return v.onFileDrop.Listen(f)
+}
+
 func (v *viewport) Destroy() {
 	v.driver.asyncDriver(func() {
 		if !v.destroyed {
 			v.window.MakeContextCurrent()
 			v.canvas = nil
+			if v.frame.Valid() {
+				gl.DeleteTexture(v.frame)
+			}
 			v.context.destroy()
 			v.window.Destroy()
+			for _, cursor := range v.cursors {
+				cursor.Destroy()
+			}
+			if v.customCursor != nil {
+				v.customCursor.Destroy()
+			}
 			v.onDestroy.Fire()
 			v.destroyed = true
 		}
@@ -0,0 +1,112 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package callqueue provides the unbounded FIFO of funcs used by the drivers
+// to marshal calls onto the driver and UI go-routines.
+package callqueue
+
+import "sync"
+
+type CallQueue struct {
+	mu     sync.Mutex
+	onDeck chan *callNode
+	head   *callNode
+	tail   *callNode
+	idle   []func() // Calls returned by PopWhenReady once the queue is empty
+}
+
+func New() *CallQueue {
+	return &CallQueue{
+		onDeck: make(chan *callNode, 1),
+	}
+}
+
+func (c *CallQueue) Inject(call func()) {
+	node := &callNode{v: call}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.head == nil {
+		c.head = node
+		c.tail = node
+		c.onDeck <- c.head
+		return
+	}
+	c.tail.next = node
+	c.tail = node
+}
+
+// InjectIdle queues call to be returned by PopWhenReady once no other calls
+// are queued. Idle calls are returned in the order they were injected.
+func (c *CallQueue) InjectIdle(call func()) {
+	c.mu.Lock()
+	c.idle = append(c.idle, call)
+	c.mu.Unlock()
+	// Wake a PopWhenReady blocked on an empty queue.
+	c.Inject(func() {})
+}
+
+func (c *CallQueue) Pop() (func(), bool) {
+	select {
+	case node, ok := <-c.onDeck:
+		if !ok {
+			return nil, false
+		}
+		c.shift()
+		return node.v, true
+	default:
+		return nil, true
+	}
+}
+
+func (c *CallQueue) PopWhenReady() (func(), bool) {
+	select {
+	case node, ok := <-c.onDeck:
+		if !ok {
+			return nil, false
+		}
+		c.shift()
+		return node.v, true
+	default:
+		if idle := c.popIdle(); idle != nil {
+			return idle, true
+		}
+	}
+	node, ok := <-c.onDeck
+	if !ok {
+		return nil, false
+	}
+	c.shift()
+	return node.v, true
+}
+
+func (c *CallQueue) popIdle() func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle) == 0 {
+		return nil
+	}
+	call := c.idle[0]
+	c.idle = c.idle[1:]
+	return call
+}
+
+func (c *CallQueue) Close() {
+	close(c.onDeck)
+}
+
+func (c *CallQueue) shift() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head = c.head.next
+	if c.head == nil {
+		c.tail = nil
+		return
+	}
+	c.onDeck <- c.head
+}
+
+type callNode struct {
+	v    func()
+	next *callNode
+}
@@ -0,0 +1,37 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package callqueue_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui/drivers/internal/callqueue"
+)
+
+func TestCallQueue_Idle(t *testing.T) {
+	c := callqueue.New()
+	defer c.Close()
+
+	order := []string{}
+	c.Inject(func() { order = append(order, "a") })
+	c.InjectIdle(func() {
+		order = append(order, "idle")
+		c.Inject(func() { order = append(order, "c") })
+	})
+	c.Inject(func() { order = append(order, "b") })
+	for len(order) < 4 {
+		call, ok := c.PopWhenReady()
+		if !ok || call == nil {
+			t.Fatalf("Expected (non-nil, true) from c.PopWhenReady; got (%T, %v)", call, ok)
+		}
+		call()
+	}
+	expected := []string{"a", "b", "idle", "c"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Calls were made in the order %v, expected %v", order, expected)
+		}
+	}
+}
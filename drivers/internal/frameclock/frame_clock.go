@@ -0,0 +1,143 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package frameclock provides the frame callbacks used by the drivers to
+// implement gxui.Driver.OnFrame.
+package frameclock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// Interval is the time between frames when no viewport presents a frame.
+const Interval = time.Second / 60
+
+// MinInterval is the shortest time between frames, limiting the frame rate
+// of drivers that present faster than the display refreshes.
+const MinInterval = time.Millisecond * 4
+
+type listener struct {
+	f       func(time.Duration)
+	removed bool
+}
+
+type subscription struct {
+	clock    *Clock
+	listener *listener
+}
+
+func (s *subscription) Unlisten() {
+	if s.clock != nil {
+		s.clock.unlisten(s.listener)
+		s.clock = nil
+	}
+}
+
+// Clock calls its listeners on the UI go-routine once per frame, while it
+// has listeners. A frame starts when a viewport presents the previous frame,
+// or after Interval if nothing is presented.
+type Clock struct {
+	call  func(func()) bool // Queues a function on the UI go-routine
+	start time.Time
+
+	listeners []*listener // Only accessed on the UI go-routine
+
+	mutex   sync.Mutex
+	waiting bool // True when the next frame is yet to be queued
+	timer   *time.Timer
+	last    time.Time // Time of the last frame
+}
+
+// New returns a Clock that uses call to queue frames on the UI go-routine.
+func New(call func(func()) bool) *Clock {
+	return &Clock{call: call, start: time.Now()}
+}
+
+// Now returns the time since the clock was created.
+func (c *Clock) Now() time.Duration {
+	return time.Since(c.start)
+}
+
+// Listen subscribes f to be called every frame with the time of the frame,
+// as returned by Now. Listen must be called on the UI go-routine.
+func (c *Clock) Listen(f func(time.Duration)) gxui.EventSubscription {
+	l := &listener{f: f}
+	c.listeners = append(c.listeners, l)
+	if len(c.listeners) == 1 {
+		c.wait(Interval)
+	}
+	return &subscription{c, l}
+}
+
+func (c *Clock) unlisten(l *listener) {
+	l.removed = true
+	for i, o := range c.listeners {
+		if o == l {
+			c.listeners = append(c.listeners[:i], c.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// Presented is called by the driver after a viewport presents a frame.
+// Presented may be called from any go-routine.
+func (c *Clock) Presented() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.waiting {
+		return
+	}
+	c.timer.Stop()
+	if d := MinInterval - time.Since(c.last); d > 0 {
+		c.timer = time.AfterFunc(d, c.next)
+	} else {
+		c.waiting = false
+		c.call(c.fire)
+	}
+}
+
+// wait schedules the next frame in d, unless one is already scheduled.
+func (c *Clock) wait(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.waiting {
+		c.waiting = true
+		c.timer = time.AfterFunc(d, c.next)
+	}
+}
+
+// next queues the next frame on the UI go-routine.
+func (c *Clock) next() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.waiting {
+		c.waiting = false
+		c.call(c.fire)
+	}
+}
+
+// fire calls the listeners, and schedules the next frame if there are any
+// listeners remaining.
+// Called on the UI go-routine.
+func (c *Clock) fire() {
+	if len(c.listeners) == 0 {
+		return
+	}
+	c.mutex.Lock()
+	c.last = time.Now()
+	c.mutex.Unlock()
+
+	t := c.Now()
+	for _, l := range append([]*listener{}, c.listeners...) {
+		if !l.removed {
+			l.f(t)
+		}
+	}
+	if len(c.listeners) > 0 {
+		c.wait(Interval)
+	}
+}
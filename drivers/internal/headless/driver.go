@@ -0,0 +1,271 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package headless contains the driver and viewport code shared by the
+// drivers that need no GPU or display. Each of those drivers embeds a Driver
+// in its driver type and an Events in its viewport type, adding fonts,
+// canvases, textures and the presentation of frames.
+package headless
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/callqueue"
+	"github.com/robertt-smg/gxui/drivers/internal/frameclock"
+)
+
+// Maximum time allowed for application to process events on termination.
+const maxFlushTime = time.Second * 3
+
+// An Opt is a type which modifies the Driver, usually during setup.
+type Opt interface {
+	Apply(gxui.Driver) gxui.Driver
+}
+
+// An OptFunc is an Opt that doesn't carry any state.
+type OptFunc func(gxui.Driver) gxui.Driver
+
+// Apply implements Opt.
+func (f OptFunc) Apply(d gxui.Driver) gxui.Driver {
+	return f(d)
+}
+
+// Debug is an Opt that sets d to debug mode (so that d.Debug() == true).
+// d must embed a Driver.
+func Debug() Opt {
+	return OptFunc(func(d gxui.Driver) gxui.Driver {
+		d.(embedder).base().debug = true
+		return d
+	})
+}
+
+// embedder is implemented by the drivers that embed a Driver.
+type embedder interface {
+	base() *Driver
+}
+
+// Viewport is the interface of the viewports tracked by a Driver.
+type Viewport interface {
+	Destroy()
+}
+
+// Driver implements the parts of gxui.Driver that are common to the headless
+// drivers: the UI go-routine and its call queue, termination, the clipboard,
+// frame events and statistics. Init must be called before any other method.
+type Driver struct {
+	pendingApp *callqueue.CallQueue
+	terminated int32 // non-zero represents driver terminations
+
+	viewportsMutex sync.Mutex
+	viewports      *list.List
+
+	clipboard *gxui.MemoryClipboard
+
+	pcs  []uintptr // reusable scratch-buffer for use by runtime.Callers.
+	uiPC uintptr   // the program-counter of the applicationLoop function.
+
+	debug bool
+
+	statsMutex sync.Mutex
+	stats      gxui.DriverStats
+
+	frameClock  *frameclock.Clock
+	onTerminate func()
+}
+
+// Init initializes d. onTerminate, if not nil, is called on the UI
+// go-routine by Terminate, once every viewport has been destroyed and the
+// remaining events of the application have been flushed.
+func (d *Driver) Init(onTerminate func()) {
+	d.pendingApp = callqueue.New()
+	d.viewports = list.New()
+	d.clipboard = gxui.CreateMemoryClipboard()
+	d.pcs = make([]uintptr, 256)
+	d.frameClock = frameclock.New(d.Call)
+	d.onTerminate = onTerminate
+}
+
+func (d *Driver) base() *Driver {
+	return d
+}
+
+// Run calls appRoutine on the UI go-routine, then executes the calls made to
+// d until it is terminated. Run blocks until the driver is terminated.
+func (d *Driver) Run(appRoutine func()) {
+	d.pendingApp.Inject(d.discoverUIGoRoutine)
+	d.pendingApp.Inject(appRoutine)
+	d.applicationLoop()
+}
+
+// CreateAppEvent returns an event with the given signature whose handlers
+// are called on the UI go-routine.
+func (d *Driver) CreateAppEvent(signature interface{}) gxui.Event {
+	return gxui.CreateChanneledEvent(signature, d.pendingApp)
+}
+
+// applicationLoop pulls and executes funcs from the pendingApp chan until
+// the chan is closed.
+func (d *Driver) applicationLoop() {
+	for {
+		ev, ok := d.pendingApp.PopWhenReady()
+		if !ok {
+			return
+		}
+		ev()
+	}
+}
+
+// AddViewport adds v to the viewports destroyed by Terminate, returning the
+// function that removes it, to be called when v is destroyed.
+func (d *Driver) AddViewport(v Viewport) (remove func()) {
+	d.viewportsMutex.Lock()
+	e := d.viewports.PushBack(v)
+	d.viewportsMutex.Unlock()
+	return func() {
+		d.viewportsMutex.Lock()
+		d.viewports.Remove(e)
+		d.viewportsMutex.Unlock()
+	}
+}
+
+// Viewports returns the viewports that have been added and not removed, in
+// the order they were added.
+func (d *Driver) Viewports() []Viewport {
+	d.viewportsMutex.Lock()
+	defer d.viewportsMutex.Unlock()
+	viewports := []Viewport{}
+	for e := d.viewports.Front(); e != nil; e = e.Next() {
+		viewports = append(viewports, e.Value.(Viewport))
+	}
+	return viewports
+}
+
+// gxui.Driver compliance
+func (d *Driver) Debug() bool {
+	return d.debug
+}
+
+func (d *Driver) Call(f func()) bool {
+	if f == nil {
+		panic("Function must not be nil")
+	}
+	if atomic.LoadInt32(&d.terminated) != 0 {
+		return false // Driver.Terminate has been called
+	}
+	d.pendingApp.Inject(f)
+	return true
+}
+
+func (d *Driver) CallWhenIdle(f func()) bool {
+	if f == nil {
+		panic("Function must not be nil")
+	}
+	if atomic.LoadInt32(&d.terminated) != 0 {
+		return false // Driver.Terminate has been called
+	}
+	d.pendingApp.InjectIdle(f)
+	return true
+}
+
+func (d *Driver) CallSync(f func()) bool {
+	if d.isUIGoroutine() {
+		f()
+		return true
+	}
+	c := make(chan struct{})
+	if d.Call(func() { f(); close(c) }) {
+		<-c
+		return true
+	}
+	return false
+}
+
+func (d *Driver) Terminate() {
+	d.pendingApp.Inject(func() {
+		// Close all viewports. This will notify the application.
+		for _, v := range d.Viewports() {
+			v.Destroy()
+		}
+
+		// Flush all remaining events from the application.
+		// This gives the application an opportunity to handle shutdown.
+		flushStart := time.Now()
+		for time.Since(flushStart) < maxFlushTime {
+			ev, _ := d.pendingApp.Pop()
+			if ev == nil {
+				break
+			}
+			ev()
+		}
+
+		if d.onTerminate != nil {
+			d.onTerminate()
+		}
+
+		// All done.
+		atomic.StoreInt32(&d.terminated, 1)
+		d.pendingApp.Close()
+	})
+}
+
+func (d *Driver) Clipboard() gxui.Clipboard {
+	return d.clipboard
+}
+
+func (d *Driver) SetClipboard(str string) {
+	gxui.SetClipboardText(d.clipboard, str)
+}
+
+func (d *Driver) GetClipboard() (string, error) {
+	str, _ := gxui.ClipboardText(d.clipboard)
+	return str, nil
+}
+
+func (d *Driver) OnFrame(f func(t time.Duration)) gxui.EventSubscription {
+	d.AssertUIGoroutine()
+	return d.frameClock.Listen(f)
+}
+
+// The headless drivers make no draw calls and hold no GPU textures, which
+// are reported as 0.
+func (d *Driver) Stats() gxui.DriverStats {
+	d.statsMutex.Lock()
+	defer d.statsMutex.Unlock()
+	s := d.stats
+	s.CanvasOps = make(map[string]int, len(d.stats.CanvasOps))
+	for name, n := range d.stats.CanvasOps {
+		s.CanvasOps[name] = n
+	}
+	return s
+}
+
+// AddFrame accumulates the statistics of a frame that took duration to
+// present and executed the canvas ops counted by ops, and notifies the
+// OnFrame listeners that the frame was presented.
+func (d *Driver) AddFrame(duration time.Duration, ops map[string]int) {
+	d.statsMutex.Lock()
+	d.stats.AddFrame(duration)
+	d.stats.CanvasOps = ops
+	d.statsMutex.Unlock()
+	d.frameClock.Presented()
+}
+
+// CountOps adds the number of ops of each kind in l, including those of the
+// canvases it draws, to ops.
+func CountOps(l *displaylist.List, ops map[string]int) {
+	if l == nil {
+		return
+	}
+	for _, op := range l.Ops {
+		ops[op.Name()]++
+		if dc, ok := op.(displaylist.DrawCanvas); ok {
+			CountOps(dc.Canvas, ops)
+		}
+	}
+}
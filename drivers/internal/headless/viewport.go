@@ -0,0 +1,107 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package headless
+
+import (
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Events holds the events of a viewport, which are broadcast to the
+// application on the UI go-routine, and implements the subscription methods
+// of gxui.Viewport. Drivers embed Events in their viewport type and fire the
+// events directly.
+type Events struct {
+	CloseEvent       gxui.Event // ()
+	ResizeEvent      gxui.Event // ()
+	MouseMoveEvent   gxui.Event // (gxui.MouseEvent)
+	MouseEnterEvent  gxui.Event // (gxui.MouseEvent)
+	MouseExitEvent   gxui.Event // (gxui.MouseEvent)
+	MouseDownEvent   gxui.Event // (gxui.MouseEvent)
+	MouseUpEvent     gxui.Event // (gxui.MouseEvent)
+	MouseScrollEvent gxui.Event // (gxui.MouseEvent)
+	KeyDownEvent     gxui.Event // (gxui.KeyboardEvent)
+	KeyUpEvent       gxui.Event // (gxui.KeyboardEvent)
+	KeyRepeatEvent   gxui.Event // (gxui.KeyboardEvent)
+	KeyStrokeEvent   gxui.Event // (gxui.KeyStrokeEvent)
+	CompositionEvent gxui.Event // (gxui.CompositionEvent)
+	FileDropEvent    gxui.Event // ([]string, math.Point)
+}
+
+// Init creates the events of e, broadcasting to the UI go-routine of d.
+func (e *Events) Init(d *Driver) {
+	e.CloseEvent = d.CreateAppEvent(func() {})
+	e.ResizeEvent = d.CreateAppEvent(func() {})
+	e.MouseMoveEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.MouseEnterEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.MouseExitEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.MouseDownEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.MouseUpEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.MouseScrollEvent = d.CreateAppEvent(func(gxui.MouseEvent) {})
+	e.KeyDownEvent = d.CreateAppEvent(func(gxui.KeyboardEvent) {})
+	e.KeyUpEvent = d.CreateAppEvent(func(gxui.KeyboardEvent) {})
+	e.KeyRepeatEvent = d.CreateAppEvent(func(gxui.KeyboardEvent) {})
+	e.KeyStrokeEvent = d.CreateAppEvent(func(gxui.KeyStrokeEvent) {})
+	e.CompositionEvent = d.CreateAppEvent(func(gxui.CompositionEvent) {})
+	e.FileDropEvent = d.CreateAppEvent(func([]string, math.Point) {})
+}
+
+// gxui.Viewport compliance
+func (e *Events) OnResize(f func()) gxui.EventSubscription {
+	return e.ResizeEvent.Listen(f)
+}
+
+func (e *Events) OnClose(f func()) gxui.EventSubscription {
+	return e.CloseEvent.Listen(f)
+}
+
+func (e *Events) OnMouseMove(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseMoveEvent.Listen(f)
+}
+
+func (e *Events) OnMouseEnter(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseEnterEvent.Listen(f)
+}
+
+func (e *Events) OnMouseExit(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseExitEvent.Listen(f)
+}
+
+func (e *Events) OnMouseDown(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseDownEvent.Listen(f)
+}
+
+func (e *Events) OnMouseUp(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseUpEvent.Listen(f)
+}
+
+func (e *Events) OnMouseScroll(f func(gxui.MouseEvent)) gxui.EventSubscription {
+	return e.MouseScrollEvent.Listen(f)
+}
+
+func (e *Events) OnKeyDown(f func(gxui.KeyboardEvent)) gxui.EventSubscription {
+	return e.KeyDownEvent.Listen(f)
+}
+
+func (e *Events) OnKeyUp(f func(gxui.KeyboardEvent)) gxui.EventSubscription {
+	return e.KeyUpEvent.Listen(f)
+}
+
+func (e *Events) OnKeyRepeat(f func(gxui.KeyboardEvent)) gxui.EventSubscription {
+	return e.KeyRepeatEvent.Listen(f)
+}
+
+func (e *Events) OnKeyStroke(f func(gxui.KeyStrokeEvent)) gxui.EventSubscription {
+	return e.KeyStrokeEvent.Listen(f)
+}
+
+func (e *Events) OnComposition(f func(gxui.CompositionEvent)) gxui.EventSubscription {
+	return e.CompositionEvent.Listen(f)
+}
+
+func (e *Events) OnFileDrop(f func(paths []string, at math.Point)) gxui.EventSubscription {
+	return e.FileDropEvent.Listen(f)
+}
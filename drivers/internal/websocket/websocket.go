@@ -0,0 +1,346 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package websocket is a minimal implementation of the WebSocket protocol
+// (RFC 6455), sufficient for drivers that stream to a browser. It supports
+// unfragmented and fragmented text and binary messages, ping, pong and close
+// frames, but no extensions or subprotocols.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Message types, as the frame opcodes of the protocol.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+const continuationFrame = 0
+
+// acceptGUID is appended to the client's key to compute the accept key of the
+// handshake.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MaxMessageSize is the largest message that ReadMessage will accept.
+const MaxMessageSize = 16 << 20
+
+// ErrClosed is returned by ReadMessage when the peer has closed the
+// connection, and by WriteMessage once the connection has been closed.
+var ErrClosed = errors.New("websocket: connection closed")
+
+// Conn is a WebSocket connection. ReadMessage must only be called by a single
+// go-routine at a time, but WriteMessage and Close may be called from any
+// go-routine.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	client bool // Client connections mask the frames they send
+
+	writeMutex sync.Mutex
+	closed     bool
+}
+
+// Upgrade performs the server side of the WebSocket handshake for the HTTP
+// request r, returning the connection. Browser requests whose Origin is not
+// the host being connected to are refused, so that other web pages cannot
+// connect. On failure, Upgrade replies to the request with an HTTP error and
+// returns the error.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != "GET" ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected a WebSocket upgrade request", http.StatusBadRequest)
+		return nil, fmt.Errorf("Request is not a WebSocket upgrade")
+	}
+	if v := r.Header.Get("Sec-Websocket-Version"); v != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusBadRequest)
+		return nil, fmt.Errorf("Unsupported WebSocket version '%s'", v)
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "Cross-origin WebSocket requests are not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("Request origin '%s' does not match host '%s'", r.Header.Get("Origin"), r.Host)
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("Missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, fmt.Errorf("ResponseWriter %T does not implement http.Hijacker", w)
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Conn{conn: conn, reader: rw.Reader}, nil
+}
+
+// Dial opens a client connection to the WebSocket server at rawurl, which
+// must have the scheme "ws".
+func Dial(rawurl string) (*Conn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("Unsupported WebSocket URL scheme '%s'", u.Scheme)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed with status '%s'", resp.Status)
+	}
+	if resp.Header.Get("Sec-Websocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake returned an invalid accept key")
+	}
+	return &Conn{conn: conn, reader: reader, client: true}, nil
+}
+
+// LocalAddr returns the local network address of the connection.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote network address of the connection.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage blocks until a complete text or binary message has been
+// received, returning its type and payload. Ping frames are answered while
+// waiting. ReadMessage returns ErrClosed once the peer has closed the
+// connection.
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	messageType = -1
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return -1, nil, err
+		}
+		switch opcode {
+		case CloseMessage:
+			c.Close()
+			return -1, nil, ErrClosed
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil {
+				return -1, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case TextMessage, BinaryMessage:
+			if messageType != -1 {
+				return -1, nil, fmt.Errorf("WebSocket message started before the previous message finished")
+			}
+			messageType = opcode
+		case continuationFrame:
+			if messageType == -1 {
+				return -1, nil, fmt.Errorf("WebSocket continuation frame without a message")
+			}
+		default:
+			return -1, nil, fmt.Errorf("Unknown WebSocket opcode %d", opcode)
+		}
+		if len(data)+len(payload) > MaxMessageSize {
+			return -1, nil, fmt.Errorf("WebSocket message exceeds %d bytes", MaxMessageSize)
+		}
+		data = append(data, payload...)
+		if fin {
+			return messageType, data, nil
+		}
+	}
+}
+
+// WriteMessage sends data as a single message of the given type.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	return c.writeFrame(messageType, data)
+}
+
+// Close closes the connection, sending a close frame if the connection was
+// still open.
+func (c *Conn) Close() error {
+	c.writeFrame(CloseMessage, nil)
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, fmt.Errorf("WebSocket frame exceeds %d bytes", MaxMessageSize)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+14)
+	frame = append(frame, 0x80|byte(opcode))
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch l := len(payload); {
+	case l < 126:
+		frame = append(frame, maskBit|byte(l))
+	case l <= 0xffff:
+		frame = append(frame, maskBit|126, byte(l>>8), byte(l))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(l))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for the client's key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// sameOrigin returns true if the request r has no Origin header, as sent by
+// non-browser clients, or if its Origin has the host of the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// headerContains returns true if the comma-separated header name holds the
+// token value, ignoring case.
+func headerContains(header http.Header, name, value string) bool {
+	for _, v := range header[http.CanonicalHeaderKey(name)] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
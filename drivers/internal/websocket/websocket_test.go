@@ -0,0 +1,160 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echoServer returns a server that echoes every message back to the client.
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return // Upgrade has replied with an error
+		}
+		defer conn.Close()
+		for {
+			ty, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(ty, data); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestAcceptKey(t *testing.T) {
+	// The example from RFC 6455 section 1.3.
+	if got, expected := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != expected {
+		t.Errorf("acceptKey was %q, expected %q", got, expected)
+	}
+}
+
+func TestEcho(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	conn, err := Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/")
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	for _, test := range []struct {
+		ty   int
+		data []byte
+	}{
+		{TextMessage, []byte("hello")},
+		{TextMessage, []byte{}},
+		{BinaryMessage, bytes.Repeat([]byte{1, 2, 3}, 100)},   // 16-bit length
+		{BinaryMessage, bytes.Repeat([]byte{4, 5, 6}, 30000)}, // 64-bit length
+	} {
+		if err := conn.WriteMessage(test.ty, test.data); err != nil {
+			t.Fatalf("WriteMessage failed: %v", err)
+		}
+		ty, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		if ty != test.ty || !bytes.Equal(data, test.data) {
+			t.Errorf("Echo of %d byte message of type %d was %d bytes of type %d",
+				len(test.data), test.ty, len(data), ty)
+		}
+	}
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	conn, err := Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/")
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// Send "hello world" as a text frame, a ping and a final continuation.
+	for _, f := range []struct {
+		fin    bool
+		opcode int
+		data   string
+	}{
+		{false, TextMessage, "hello "},
+		{true, PingMessage, "ping"},
+		{true, continuationFrame, "world"},
+	} {
+		frame := []byte{byte(f.opcode), 0x80 | byte(len(f.data)), 0, 0, 0, 0}
+		if f.fin {
+			frame[0] |= 0x80
+		}
+		frame = append(frame, f.data...) // A zero mask leaves the data unchanged
+		if _, err := conn.conn.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The pong is consumed by ReadMessage.
+	ty, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage failed: %v", err)
+	}
+	if ty != TextMessage || string(data) != "hello world" {
+		t.Errorf("Got message %q of type %d, expected %q of type %d", data, ty, "hello world", TextMessage)
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a plain GET to fail with %d, got %v %v", http.StatusBadRequest, resp, err)
+	}
+}
+
+func TestUpgradeRejectsForeignOrigins(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	for _, test := range []struct {
+		origin string
+		status int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"http://" + host, http.StatusSwitchingProtocols},
+		{"http://example.com", http.StatusForbidden},
+		{"http://" + host + ".example.com", http.StatusForbidden},
+	} {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("Request with origin %q failed: %v", test.origin, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("Request with origin %q returned %d, expected %d", test.origin, resp.StatusCode, test.status)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/websocket"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Number of messages queued for a client before it is considered too slow
+// and disconnected.
+const clientQueueSize = 1024
+
+// serverMessage is a message sent from the driver to the browser.
+//
+//	"viewport": creates or updates the viewport Viewport, where Cursor is a
+//	            CSS cursor property value.
+//	"close":    removes the viewport Viewport.
+//	"texture":  uploads the texture ID, of Size pixels, as a PNG image.
+//	"glyph":    uploads the glyph ID as a PNG image, where Rect is the bounds
+//	            of the glyph in pixels relative to the dot.
+//	"frame":    replaces the content of the viewport Viewport with List.
+type serverMessage struct {
+	Type     string
+	Viewport int        `json:",omitempty"`
+	Title    string     `json:",omitempty"`
+	Size     *math.Size `json:",omitempty"`
+	Scale    float32    `json:",omitempty"`
+	Hidden   bool       `json:",omitempty"`
+	Cursor   string     `json:",omitempty"`
+	ID       int        `json:",omitempty"`
+	Rect     *math.Rect `json:",omitempty"`
+	FlipY    bool       `json:",omitempty"`
+	PNG      []byte     `json:",omitempty"`
+	List     *frameList `json:",omitempty"`
+}
+
+// clientMessage is a message sent from the browser to the driver, holding a
+// mouse or keyboard event of the viewport Viewport. Mouse positions are in
+// pixels of the viewport, keys are KeyboardEvent.code values.
+type clientMessage struct {
+	Type             string
+	Viewport         int
+	X, Y             int
+	Button           gxui.MouseButton
+	State            gxui.MouseState
+	ScrollX, ScrollY int
+	Modifier         gxui.KeyboardModifier
+	Code             string
+	Character        string
+}
+
+// frameList is the form of a displaylist.List sent to the browser. Ops are
+// encoded as by displaylist.List.MarshalJSON, except for the ops that
+// reference textures and fonts, which are replaced by the ops below.
+type frameList struct {
+	Size math.Size
+	Ops  []json.RawMessage
+}
+
+// drawGlyphs replaces DrawRunes. Glyphs holds the identifier of the uploaded
+// glyph of each rune, or 0 for runes without pixels. Resolution is the number
+// of glyph pixels per DIP.
+type drawGlyphs struct {
+	Op         string
+	Glyphs     []int
+	Points     []math.Point
+	Color      gxui.Color
+	Resolution float32
+}
+
+// drawTexture replaces DrawTexture, with Texture the uploaded texture's
+// identifier.
+type drawTexture struct {
+	Op      string
+	Texture int
+	Rect    math.Rect
+}
+
+// drawCanvas replaces DrawCanvas, so that the ops of the drawn canvas are
+// also replaced.
+type drawCanvas struct {
+	Op     string
+	Canvas frameList
+	Offset math.Point
+}
+
+// client is a browser connected to the driver. Other than writeLoop and
+// readLoop, the methods of client must be called on the UI go-routine.
+type client struct {
+	driver     *driver
+	conn       *websocket.Conn
+	out        chan []byte
+	closed     bool
+	pixelRatio float32      // Device pixels per CSS pixel of the browser
+	textures   map[int]bool // Textures uploaded to the browser
+	glyphs     map[int]bool // Glyphs uploaded to the browser
+}
+
+func (d *driver) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		if d.Debug() {
+			fmt.Printf("Failed to accept client: %v\n", err)
+		}
+		return
+	}
+	c := &client{
+		driver:     d,
+		conn:       conn,
+		out:        make(chan []byte, clientQueueSize),
+		pixelRatio: 1,
+		textures:   make(map[int]bool),
+		glyphs:     make(map[int]bool),
+	}
+	if ratio, err := strconv.ParseFloat(r.URL.Query().Get("pixelRatio"), 32); err == nil && ratio > 0 {
+		c.pixelRatio = float32(ratio)
+	}
+	if !d.Call(func() { d.addClient(c) }) {
+		conn.Close()
+		return
+	}
+	c.readLoop()
+	d.Call(c.close)
+}
+
+// addClient registers the client c, sending it every open viewport.
+func (d *driver) addClient(c *client) {
+	d.clients[c] = true
+	go c.writeLoop()
+	for _, v := range d.openViewports() {
+		c.sendViewport(v)
+		if l := v.displayList(); l != nil {
+			c.sendFrame(v, l)
+		}
+	}
+}
+
+func (c *client) sendViewport(v *viewport) {
+	c.send(v.state())
+}
+
+func (c *client) sendClose(v *viewport) {
+	c.send(serverMessage{Type: "close", Viewport: v.id})
+}
+
+func (c *client) sendFrame(v *viewport, l *displaylist.List) {
+	list := c.encodeList(l, v.Scale()*c.pixelRatio)
+	c.send(serverMessage{Type: "frame", Viewport: v.id, List: &list})
+}
+
+// send queues the message m to be written to the browser. If the browser is
+// not keeping up with the messages, the client is disconnected.
+func (c *client) send(m serverMessage) {
+	if c.closed {
+		return
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	select {
+	case c.out <- data:
+	default:
+		if c.driver.Debug() {
+			fmt.Printf("Disconnecting client %v: too many queued messages\n", c.conn.RemoteAddr())
+		}
+		c.close()
+	}
+}
+
+// close unregisters the client and closes the connection once all queued
+// messages have been written.
+func (c *client) close() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	delete(c.driver.clients, c)
+	close(c.out)
+}
+
+// encodeList returns the display list l as sent to the browser, first
+// uploading any textures and glyphs it uses. Glyphs are rasterized at
+// resolution pixels per DIP.
+func (c *client) encodeList(l *displaylist.List, resolution float32) frameList {
+	list := frameList{Size: l.Size, Ops: make([]json.RawMessage, 0, len(l.Ops))}
+	for _, op := range l.Ops {
+		var data []byte
+		var err error
+		switch op := op.(type) {
+		case displaylist.DrawRunes:
+			f, ok := op.Font.Font.(*font)
+			if !ok {
+				continue // Font was not created by this driver
+			}
+			glyphs := make([]int, len(op.Runes))
+			for i, r := range op.Runes {
+				if g := c.uploadGlyph(f, r, resolution); g != nil {
+					glyphs[i] = g.id
+				}
+			}
+			data, err = json.Marshal(drawGlyphs{"DrawGlyphs", glyphs, op.Points, op.Color, resolution})
+		case displaylist.DrawTexture:
+			t, ok := op.Texture.Texture.(*texture)
+			if !ok {
+				continue // Texture was not created by this driver
+			}
+			c.uploadTexture(t)
+			data, err = json.Marshal(drawTexture{"DrawTexture", t.id, op.Rect})
+		case displaylist.DrawCanvas:
+			if op.Canvas == nil {
+				continue
+			}
+			data, err = json.Marshal(drawCanvas{"DrawCanvas", c.encodeList(op.Canvas, resolution), op.Offset})
+		default:
+			data, err = marshalOp(op)
+		}
+		if err != nil {
+			panic(err)
+		}
+		list.Ops = append(list.Ops, data)
+	}
+	return list
+}
+
+// marshalOp encodes op as displaylist.List.MarshalJSON does.
+func marshalOp(op displaylist.Op) ([]byte, error) {
+	fields, err := json.Marshal(op)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `{"Op":%q`, op.Name())
+	if len(fields) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(fields[1:])
+	return buf.Bytes(), nil
+}
+
+func (c *client) uploadTexture(t *texture) {
+	if c.textures[t.id] {
+		return
+	}
+	c.textures[t.id] = true
+	size := t.SizePixels()
+	c.send(serverMessage{Type: "texture", ID: t.id, Size: &size, FlipY: t.FlipY(), PNG: t.encoded()})
+}
+
+func (c *client) uploadGlyph(f *font, r rune, resolution float32) *glyph {
+	g := f.glyph(r, resolution)
+	if g == nil || c.glyphs[g.id] {
+		return g
+	}
+	c.glyphs[g.id] = true
+	rect := math.CreateRect(g.rect.Min.X, g.rect.Min.Y, g.rect.Max.X, g.rect.Max.Y)
+	c.send(serverMessage{Type: "glyph", ID: g.id, Rect: &rect, PNG: g.png})
+	return g
+}
+
+// writeLoop writes the queued messages to the browser until the client is
+// closed, then closes the connection.
+func (c *client) writeLoop() {
+	for data := range c.out {
+		if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			break
+		}
+	}
+	c.conn.Close()
+}
+
+// readLoop dispatches the events sent by the browser to the viewports until
+// the connection is closed.
+func (c *client) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		m := clientMessage{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			if c.driver.Debug() {
+				fmt.Printf("Invalid message from client %v: %v\n", c.conn.RemoteAddr(), err)
+			}
+			continue
+		}
+		if v := c.driver.viewport(m.Viewport); v != nil {
+			v.dispatch(m)
+		}
+	}
+}
+
+// dispatch fires the viewport event described by m.
+func (v *viewport) dispatch(m clientMessage) {
+	mouse := gxui.MouseEvent{
+		Point:    math.Point{X: m.X, Y: m.Y}.ScaleS(1 / v.Scale()),
+		Button:   m.Button,
+		State:    m.State,
+		ScrollX:  m.ScrollX,
+		ScrollY:  m.ScrollY,
+		Modifier: m.Modifier,
+	}
+	key := gxui.KeyboardEvent{
+		Key:      translateKeyboardKey(m.Code),
+		Modifier: m.Modifier,
+	}
+	switch m.Type {
+	case "mousemove":
+		v.MouseMoveEvent.Fire(mouse)
+	case "mouseenter":
+		v.MouseEnterEvent.Fire(mouse)
+	case "mouseexit":
+		v.MouseExitEvent.Fire(mouse)
+	case "mousedown":
+		v.MouseDownEvent.Fire(mouse)
+	case "mouseup":
+		v.MouseUpEvent.Fire(mouse)
+	case "scroll":
+		v.MouseScrollEvent.Fire(mouse)
+	case "keydown":
+		v.KeyDownEvent.Fire(key)
+	case "keyup":
+		v.KeyUpEvent.Fire(key)
+	case "keyrepeat":
+		v.KeyRepeatEvent.Fire(key)
+	case "keystroke":
+		for _, r := range m.Character {
+			v.KeyStrokeEvent.Fire(gxui.KeyStrokeEvent{Character: r, Modifier: m.Modifier})
+		}
+	}
+}
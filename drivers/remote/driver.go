@@ -0,0 +1,212 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package remote contains an implementation of the gxui.Driver interface that
+// displays viewports in a web browser. The driver needs no GPU or display: it
+// serves a small HTML5 canvas client over HTTP and streams each frame to it
+// over a WebSocket as a display list, uploading textures and rasterized
+// glyphs as they are first used. Mouse and keyboard input in the browser is
+// sent back to the viewports.
+//
+// The driver has no dependencies outside of the standard library and gxui,
+// so it can be used to run gxui applications on a headless machine and view
+// them from another:
+//
+//	remote.StartDriver(appMain, remote.Address(":8080"))
+package remote
+
+import (
+	"fmt"
+	"image"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// DefaultAddress is the address the driver listens on if no Address Opt is
+// given.
+const DefaultAddress = "localhost:8080"
+
+// Size of the virtual screen used by fullscreen viewports created with a
+// width or height of 0.
+var defaultScreenSize = math.Size{W: 1920, H: 1080}
+
+// Driver is the interface implemented by the remote driver.
+type Driver interface {
+	gxui.Driver
+
+	// URL returns the address of the HTML client served by the driver, such
+	// as "http://localhost:8080/".
+	URL() string
+}
+
+// An Opt is a type which modifies the Driver, usually during setup.
+type Opt = headless.Opt
+
+// An OptFunc is an Opt that doesn't carry any state.
+type OptFunc = headless.OptFunc
+
+// Debug is an Opt that sets d to debug mode (so that d.Debug() == true).
+func Debug() Opt {
+	return headless.Debug()
+}
+
+// Address is an Opt that sets the TCP address the driver's HTTP server
+// listens on, in the form "host:port". A port of 0 picks a free port, which
+// can be found with Driver.URL.
+func Address(addr string) Opt {
+	return OptFunc(func(d gxui.Driver) gxui.Driver {
+		d.(*driver).address = addr
+		return d
+	})
+}
+
+// driver is the remote driver. The clipboard is held by the driver. It is not
+// shared with the browser.
+type driver struct {
+	headless.Driver
+
+	address  string
+	listener net.Listener
+	server   *http.Server
+	clients  map[*client]bool // Only accessed on the UI go-routine
+	nextID   int32            // Last identifier given to a viewport, texture or glyph
+}
+
+// StartDriver starts the remote driver with the given appRoutine, serving the
+// browser client on the address given by the Address Opt, or DefaultAddress.
+// StartDriver blocks until the driver is terminated, and panics if the
+// address cannot be listened on.
+func StartDriver(appRoutine func(driver gxui.Driver), opts ...Opt) {
+	d := &driver{
+		address: DefaultAddress,
+		clients: make(map[*client]bool),
+	}
+	d.Init(d.closeClients)
+	for _, opt := range opts {
+		d = opt.Apply(d).(*driver)
+	}
+
+	listener, err := net.Listen("tcp", d.address)
+	if err != nil {
+		panic(fmt.Errorf("Failed to listen on %s: %v", d.address, err))
+	}
+	d.listener = listener
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", serveClientPage)
+	mux.HandleFunc("/ws", d.serveWebSocket)
+	d.server = &http.Server{Handler: mux}
+	go d.server.Serve(listener)
+	if d.Debug() {
+		fmt.Printf("gxui remote driver serving at %s\n", d.URL())
+	}
+
+	d.Run(func() { appRoutine(d) })
+	d.server.Close()
+}
+
+// newID returns a new identifier for a viewport, texture or glyph.
+func (d *driver) newID() int {
+	return int(atomic.AddInt32(&d.nextID, 1))
+}
+
+// viewport returns the open viewport with the given identifier, or nil.
+func (d *driver) viewport(id int) *viewport {
+	for _, v := range d.openViewports() {
+		if v.id == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// openViewports returns the open viewports, in order of creation.
+func (d *driver) openViewports() []*viewport {
+	viewports := []*viewport{}
+	for _, v := range d.Viewports() {
+		viewports = append(viewports, v.(*viewport))
+	}
+	return viewports
+}
+
+// closeClients disconnects every connected client. It is called by Terminate
+// on the UI go-routine.
+func (d *driver) closeClients() {
+	for c := range d.clients {
+		c.close()
+	}
+}
+
+// Driver compliance
+func (d *driver) URL() string {
+	return fmt.Sprintf("http://%s/", d.listener.Addr())
+}
+
+// gxui.Driver compliance
+func (d *driver) CreateFont(data []byte, size int) (gxui.Font, error) {
+	f, err := newFont(d, data, size)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (d *driver) CreateWindowedViewport(width, height int, name string) gxui.Viewport {
+	return d.createViewport(width, height, name, false)
+}
+
+func (d *driver) CreateFullscreenViewport(width, height int, name string) gxui.Viewport {
+	if width == 0 || height == 0 {
+		width, height = defaultScreenSize.WH()
+	}
+	return d.createViewport(width, height, name, true)
+}
+
+func (d *driver) createViewport(width, height int, name string, fullscreen bool) *viewport {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Errorf("Viewport width and height must be positive. Got %dx%d", width, height))
+	}
+	v := newViewport(d, width, height, name, fullscreen)
+	remove := d.AddViewport(v)
+	v.onDestroy = func() {
+		remove()
+		d.broadcast(func(c *client) { c.sendClose(v) })
+	}
+	d.broadcast(func(c *client) { c.sendViewport(v) })
+	return v
+}
+
+func (d *driver) CreateCanvas(s math.Size) gxui.Canvas {
+	return displaylist.NewCanvas(s)
+}
+
+func (d *driver) CreateTexture(img image.Image, pixelsPerDip float32) gxui.Texture {
+	return newTexture(d.newID(), img, pixelsPerDip)
+}
+
+// broadcast calls f for every connected client. broadcast must be called on
+// the UI go-routine.
+func (d *driver) broadcast(f func(c *client)) {
+	for c := range d.clients {
+		f(c)
+	}
+}
+
+// present sends the display list l of the viewport v to every connected
+// client, and accumulates the statistics of the frame. The frame time is the
+// time taken to encode and queue the frame for every connected browser.
+func (d *driver) present(v *viewport, l *displaylist.List) {
+	start := time.Now()
+	d.broadcast(func(c *client) { c.sendFrame(v, l) })
+	ops := map[string]int{}
+	headless.CountOps(l, ops)
+	d.AddFrame(time.Since(start), ops)
+}
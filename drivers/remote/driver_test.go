@@ -0,0 +1,266 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote_test
+
+import (
+	"encoding/json"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/internal/websocket"
+	"github.com/robertt-smg/gxui/drivers/remote"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+const timeout = 5 * time.Second
+
+// start starts the driver on a free localhost port, calling f on the UI
+// go-routine. The driver is terminated at the end of the test.
+func start(t *testing.T, f func(driver gxui.Driver)) remote.Driver {
+	started := make(chan remote.Driver)
+	done := make(chan struct{})
+	go func() {
+		remote.StartDriver(func(driver gxui.Driver) {
+			f(driver)
+			started <- driver.(remote.Driver)
+		}, remote.Address("localhost:0"))
+		close(done)
+	}()
+	d := <-started
+	t.Cleanup(func() {
+		d.Terminate()
+		<-done
+	})
+	return d
+}
+
+type message struct {
+	Type     string
+	Viewport int
+	Title    string
+	Size     *math.Size
+	ID       int
+	PNG      []byte
+	List     json.RawMessage
+}
+
+// client is a test stand-in for the browser client.
+type client struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	messages chan message
+}
+
+func connect(t *testing.T, d remote.Driver) *client {
+	conn, err := websocket.Dial("ws" + strings.TrimPrefix(d.URL(), "http") + "ws")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	c := &client{t: t, conn: conn, messages: make(chan message, 1024)}
+	go func() {
+		defer close(c.messages)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			m := message{}
+			if err := json.Unmarshal(data, &m); err != nil {
+				t.Errorf("Invalid message %s: %v", data, err)
+				return
+			}
+			c.messages <- m
+		}
+	}()
+	t.Cleanup(func() { conn.Close() })
+	return c
+}
+
+// next returns the next message for which match returns true, skipping all
+// other messages.
+func (c *client) next(match func(m message) bool) message {
+	c.t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case m, ok := <-c.messages:
+			if !ok {
+				c.t.Fatalf("Connection closed while waiting for message")
+			}
+			if match(m) {
+				return m
+			}
+		case <-deadline:
+			c.t.Fatalf("Timed out waiting for message")
+		}
+	}
+}
+
+func (c *client) send(m interface{}) {
+	c.t.Helper()
+	data, err := json.Marshal(m)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		c.t.Fatalf("Failed to send message: %v", err)
+	}
+}
+
+func ofType(ty string) func(m message) bool {
+	return func(m message) bool { return m.Type == ty }
+}
+
+func TestServesClientPage(t *testing.T) {
+	d := start(t, func(driver gxui.Driver) {})
+	resp, err := http.Get(d.URL())
+	if err != nil {
+		t.Fatalf("Failed to get client page: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "WebSocket") {
+		t.Errorf("Client page returned status %s, body:\n%s", resp.Status, body)
+	}
+}
+
+func TestRejectsForeignOrigins(t *testing.T) {
+	d := start(t, func(driver gxui.Driver) {})
+	req, err := http.NewRequest("GET", d.URL()+"ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "http://example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to request an upgrade: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Upgrade from a foreign origin returned status %s, expected %d", resp.Status, http.StatusForbidden)
+	}
+}
+
+func TestStreamsViewportAndFrames(t *testing.T) {
+	d := start(t, func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		label := theme.CreateLabel()
+		label.SetText("Hello")
+		window := theme.CreateWindow(120, 40, "Remote")
+		window.AddChild(label)
+	})
+	c := connect(t, d)
+
+	v := c.next(ofType("viewport"))
+	if v.Title != "Remote" || v.Size == nil || *v.Size != (math.Size{W: 120, H: 40}) {
+		t.Errorf("Viewport message was %+v, expected title 'Remote' and size 120x40", v)
+	}
+
+	glyphs := map[int]bool{}
+	frame := c.next(func(m message) bool {
+		if m.Type == "glyph" {
+			if len(m.PNG) == 0 {
+				t.Errorf("Glyph %d has no image", m.ID)
+			}
+			glyphs[m.ID] = true
+		}
+		return m.Type == "frame" && strings.Contains(string(m.List), "DrawGlyphs")
+	})
+	if frame.Viewport != v.Viewport {
+		t.Errorf("Frame was for viewport %d, expected %d", frame.Viewport, v.Viewport)
+	}
+	if len(glyphs) == 0 {
+		t.Errorf("No glyphs were uploaded before the frame")
+	}
+	list := struct{ Ops []struct{ Op string } }{}
+	if err := json.Unmarshal(frame.List, &list); err != nil {
+		t.Fatalf("Invalid frame list: %v", err)
+	}
+	for _, op := range list.Ops {
+		if op.Op == "DrawRunes" {
+			t.Errorf("DrawRunes was sent, expected it to be replaced by DrawGlyphs")
+		}
+	}
+}
+
+func TestUploadsTextures(t *testing.T) {
+	d := start(t, func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		img := theme.CreateImage()
+		img.SetTexture(driver.CreateTexture(image.NewRGBA(image.Rect(0, 0, 4, 4)), 1))
+		window := theme.CreateWindow(40, 40, "Texture")
+		window.AddChild(img)
+	})
+	c := connect(t, d)
+	tex := c.next(ofType("texture"))
+	if tex.Size == nil || *tex.Size != (math.Size{W: 4, H: 4}) || len(tex.PNG) == 0 {
+		t.Errorf("Texture message was %+v, expected a 4x4 PNG", tex)
+	}
+	c.next(func(m message) bool {
+		return m.Type == "frame" && strings.Contains(string(m.List), `"Texture":`)
+	})
+}
+
+func TestForwardsInput(t *testing.T) {
+	clicked := make(chan bool, 1)
+	var button gxui.Button
+	var textBox gxui.TextBox
+	d := start(t, func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		button = theme.CreateButton()
+		button.SetText("OK")
+		button.OnClick(func(gxui.MouseEvent) { clicked <- true })
+		textBox = theme.CreateTextBox()
+		layout := theme.CreateLinearLayout()
+		layout.AddChild(button)
+		layout.AddChild(textBox)
+		window := theme.CreateWindow(200, 100, "Input")
+		window.AddChild(layout)
+	})
+	c := connect(t, d)
+	v := c.next(ofType("viewport"))
+
+	var p math.Point
+	d.CallSync(func() { _, p = gxui.ControlToWindow(button, button.Size().Rect().Mid()) })
+	left := gxui.MouseState(1 << uint(gxui.MouseButtonLeft))
+	c.send(map[string]interface{}{"Type": "mousemove", "Viewport": v.Viewport, "X": p.X, "Y": p.Y})
+	c.send(map[string]interface{}{"Type": "mousedown", "Viewport": v.Viewport, "X": p.X, "Y": p.Y,
+		"Button": gxui.MouseButtonLeft, "State": left})
+	c.send(map[string]interface{}{"Type": "mouseup", "Viewport": v.Viewport, "X": p.X, "Y": p.Y,
+		"Button": gxui.MouseButtonLeft})
+	select {
+	case <-clicked:
+	case <-time.After(timeout):
+		t.Fatalf("Timed out waiting for the button to be clicked")
+	}
+
+	d.CallSync(func() { gxui.SetFocus(textBox) })
+	c.send(map[string]interface{}{"Type": "keystroke", "Viewport": v.Viewport, "Character": "hi"})
+	c.send(map[string]interface{}{"Type": "keydown", "Viewport": v.Viewport, "Code": "Backspace"})
+	c.send(map[string]interface{}{"Type": "keystroke", "Viewport": v.Viewport, "Character": "!"})
+	deadline := time.Now().Add(timeout)
+	for {
+		var text string
+		d.CallSync(func() { text = textBox.Text() })
+		if text == "h!" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("TextBox text was '%s', expected 'h!'", text)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
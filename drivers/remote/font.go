@@ -0,0 +1,220 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+
+	"github.com/golang/freetype/truetype"
+	fnt "golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+type font struct {
+	driver           *driver
+	size             int
+	scale            fixed.Int26_6
+	glyphMaxSizeDips math.Size
+	ascentDips       int
+	ttf              *truetype.Font
+	family           string
+	data             []byte
+	faces            map[float32]fnt.Face
+	glyphAdvanceDips map[rune]int
+	glyphs           map[glyphKey]*glyph
+}
+
+// glyphKey identifies a glyph rasterized at a resolution, in pixels per DIP.
+type glyphKey struct {
+	r          rune
+	resolution float32
+}
+
+// glyph is a rasterized glyph, sent to the browser as a white PNG image with
+// the coverage of the glyph in the alpha channel.
+type glyph struct {
+	id   int
+	rect image.Rectangle // Bounds in pixels, relative to the dot
+	png  []byte
+}
+
+func newFont(driver *driver, data []byte, size int) (*font, error) {
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Metrics are rounded exactly as the gl and soft drivers do, so that all
+	// drivers lay out text identically.
+	scale := fixed.Int26_6(size << 6)
+	b := ttf.Bounds(scale)
+	bounds := math.CreateRect(int(b.Min.X)>>6, int(b.Min.Y)>>6, int(b.Max.X)>>6, int(b.Max.Y)>>6)
+
+	return &font{
+		driver:           driver,
+		size:             size,
+		scale:            scale,
+		glyphMaxSizeDips: bounds.Size(),
+		ascentDips:       bounds.Max.Y,
+		ttf:              ttf,
+		family:           ttf.Name(truetype.NameIDFontFamily),
+		data:             data,
+		faces:            make(map[float32]fnt.Face),
+		glyphAdvanceDips: make(map[rune]int),
+		glyphs:           make(map[glyphKey]*glyph),
+	}, nil
+}
+
+func (f *font) advanceDips(r rune) int {
+	if g, found := f.glyphAdvanceDips[r]; found {
+		return g
+	}
+	idx := f.ttf.Index(r)
+	gb := &truetype.GlyphBuf{}
+	err := gb.Load(f.ttf, f.scale, idx, fnt.HintingFull)
+	if err != nil {
+		panic(err)
+	}
+
+	advance := int((gb.AdvanceWidth + 0x3f) >> 6)
+	f.glyphAdvanceDips[r] = advance
+	return advance
+}
+
+// glyph returns the rune r rasterized at resolution pixels per DIP, or nil if
+// the glyph has no pixels. glyph must be called on the UI go-routine.
+func (f *font) glyph(r rune, resolution float32) *glyph {
+	key := glyphKey{r, resolution}
+	if g, found := f.glyphs[key]; found {
+		return g
+	}
+
+	face, found := f.faces[resolution]
+	if !found {
+		face = truetype.NewFace(f.ttf, &truetype.Options{
+			Size:    float64(f.size),
+			DPI:     float64(72 * resolution),
+			Hinting: fnt.HintingFull,
+		})
+		f.faces[resolution] = face
+	}
+
+	var g *glyph
+	dr, mask, maskp, _, ok := face.Glyph(fixed.P(0, 0), r)
+	if ok && !dr.Empty() {
+		img := image.NewNRGBA(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+		for y := 0; y < dr.Dy(); y++ {
+			for x := 0; x < dr.Dx(); x++ {
+				_, _, _, a := mask.At(maskp.X+x, maskp.Y+y).RGBA()
+				img.SetNRGBA(x, y, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: uint8(a >> 8)})
+			}
+		}
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, img); err != nil {
+			panic(err)
+		}
+		g = &glyph{id: f.driver.newID(), rect: dr, png: buf.Bytes()}
+	}
+	f.glyphs[key] = g
+	return g
+}
+
+func (f *font) align(rect math.Rect, size math.Size, ascent int, h gxui.HorizontalAlignment, v gxui.VerticalAlignment) math.Point {
+	var origin math.Point
+	switch h {
+	case gxui.AlignLeft:
+		origin.X = rect.Min.X
+	case gxui.AlignCenter:
+		origin.X = rect.Mid().X - (size.W / 2)
+	case gxui.AlignRight:
+		origin.X = rect.Max.X - size.W
+	}
+	switch v {
+	case gxui.AlignTop:
+		origin.Y = rect.Min.Y + ascent
+	case gxui.AlignMiddle:
+		origin.Y = rect.Mid().Y - (size.H / 2) + ascent
+	case gxui.AlignBottom:
+		origin.Y = rect.Max.Y - size.H + ascent
+	}
+	return origin
+}
+
+// gxui.NamedFont compliance
+func (f *font) Family() string {
+	return f.family
+}
+
+// gxui.TrueTypeFont compliance
+func (f *font) TrueTypeData() []byte {
+	return f.data
+}
+
+// gxui.Font compliance
+func (f *font) Index(r rune) truetype.Index {
+	return f.ttf.Index(r)
+}
+
+func (f *font) Size() int {
+	return f.size
+}
+
+func (f *font) Measure(fl *gxui.TextBlock) math.Size {
+	size := math.Size{W: 0, H: f.glyphMaxSizeDips.H}
+	var offset math.Point
+	for _, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.glyphMaxSizeDips.H
+			continue
+		}
+		offset.X += f.advanceDips(r)
+		size = size.Max(math.Size{W: offset.X, H: offset.Y + f.glyphMaxSizeDips.H})
+	}
+	return size
+}
+
+func (f *font) Layout(fl *gxui.TextBlock) (offsets []math.Point) {
+	sizeDips := math.Size{}
+	offsets = make([]math.Point, len(fl.Runes))
+	var offset math.Point
+	for i, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.glyphMaxSizeDips.H
+			continue
+		}
+
+		offsets[i] = offset
+		offset.X += f.advanceDips(r)
+		sizeDips = sizeDips.Max(math.Size{W: offset.X, H: offset.Y + f.glyphMaxSizeDips.H})
+	}
+
+	origin := f.align(fl.AlignRect, sizeDips, f.ascentDips, fl.H, fl.V)
+	for i, p := range offsets {
+		offsets[i] = p.Add(origin)
+	}
+	return offsets
+}
+
+func (f *font) LoadGlyphs(first, last rune) {
+	if first > last {
+		first, last = last, first
+	}
+	for r := first; r < last; r++ {
+		f.advanceDips(r)
+	}
+}
+
+func (f *font) GlyphMaxSize() math.Size {
+	return f.glyphMaxSizeDips
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"github.com/robertt-smg/gxui"
+)
+
+// keys maps the KeyboardEvent.code values of the browser to gxui keys.
+var keys = map[string]gxui.KeyboardKey{
+	"Space":          gxui.KeySpace,
+	"Quote":          gxui.KeyApostrophe,
+	"Comma":          gxui.KeyComma,
+	"Minus":          gxui.KeyMinus,
+	"Period":         gxui.KeyPeriod,
+	"Slash":          gxui.KeySlash,
+	"Digit0":         gxui.Key0,
+	"Digit1":         gxui.Key1,
+	"Digit2":         gxui.Key2,
+	"Digit3":         gxui.Key3,
+	"Digit4":         gxui.Key4,
+	"Digit5":         gxui.Key5,
+	"Digit6":         gxui.Key6,
+	"Digit7":         gxui.Key7,
+	"Digit8":         gxui.Key8,
+	"Digit9":         gxui.Key9,
+	"Semicolon":      gxui.KeySemicolon,
+	"Equal":          gxui.KeyEqual,
+	"KeyA":           gxui.KeyA,
+	"KeyB":           gxui.KeyB,
+	"KeyC":           gxui.KeyC,
+	"KeyD":           gxui.KeyD,
+	"KeyE":           gxui.KeyE,
+	"KeyF":           gxui.KeyF,
+	"KeyG":           gxui.KeyG,
+	"KeyH":           gxui.KeyH,
+	"KeyI":           gxui.KeyI,
+	"KeyJ":           gxui.KeyJ,
+	"KeyK":           gxui.KeyK,
+	"KeyL":           gxui.KeyL,
+	"KeyM":           gxui.KeyM,
+	"KeyN":           gxui.KeyN,
+	"KeyO":           gxui.KeyO,
+	"KeyP":           gxui.KeyP,
+	"KeyQ":           gxui.KeyQ,
+	"KeyR":           gxui.KeyR,
+	"KeyS":           gxui.KeyS,
+	"KeyT":           gxui.KeyT,
+	"KeyU":           gxui.KeyU,
+	"KeyV":           gxui.KeyV,
+	"KeyW":           gxui.KeyW,
+	"KeyX":           gxui.KeyX,
+	"KeyY":           gxui.KeyY,
+	"KeyZ":           gxui.KeyZ,
+	"BracketLeft":    gxui.KeyLeftBracket,
+	"Backslash":      gxui.KeyBackslash,
+	"BracketRight":   gxui.KeyRightBracket,
+	"Backquote":      gxui.KeyGraveAccent,
+	"IntlBackslash":  gxui.KeyWorld1,
+	"IntlRo":         gxui.KeyWorld2,
+	"Escape":         gxui.KeyEscape,
+	"Enter":          gxui.KeyEnter,
+	"Tab":            gxui.KeyTab,
+	"Backspace":      gxui.KeyBackspace,
+	"Insert":         gxui.KeyInsert,
+	"Delete":         gxui.KeyDelete,
+	"ArrowRight":     gxui.KeyRight,
+	"ArrowLeft":      gxui.KeyLeft,
+	"ArrowDown":      gxui.KeyDown,
+	"ArrowUp":        gxui.KeyUp,
+	"PageUp":         gxui.KeyPageUp,
+	"PageDown":       gxui.KeyPageDown,
+	"Home":           gxui.KeyHome,
+	"End":            gxui.KeyEnd,
+	"CapsLock":       gxui.KeyCapsLock,
+	"ScrollLock":     gxui.KeyScrollLock,
+	"NumLock":        gxui.KeyNumLock,
+	"PrintScreen":    gxui.KeyPrintScreen,
+	"Pause":          gxui.KeyPause,
+	"F1":             gxui.KeyF1,
+	"F2":             gxui.KeyF2,
+	"F3":             gxui.KeyF3,
+	"F4":             gxui.KeyF4,
+	"F5":             gxui.KeyF5,
+	"F6":             gxui.KeyF6,
+	"F7":             gxui.KeyF7,
+	"F8":             gxui.KeyF8,
+	"F9":             gxui.KeyF9,
+	"F10":            gxui.KeyF10,
+	"F11":            gxui.KeyF11,
+	"F12":            gxui.KeyF12,
+	"Numpad0":        gxui.KeyKp0,
+	"Numpad1":        gxui.KeyKp1,
+	"Numpad2":        gxui.KeyKp2,
+	"Numpad3":        gxui.KeyKp3,
+	"Numpad4":        gxui.KeyKp4,
+	"Numpad5":        gxui.KeyKp5,
+	"Numpad6":        gxui.KeyKp6,
+	"Numpad7":        gxui.KeyKp7,
+	"Numpad8":        gxui.KeyKp8,
+	"Numpad9":        gxui.KeyKp9,
+	"NumpadDecimal":  gxui.KeyKpDecimal,
+	"NumpadDivide":   gxui.KeyKpDivide,
+	"NumpadMultiply": gxui.KeyKpMultiply,
+	"NumpadSubtract": gxui.KeyKpSubtract,
+	"NumpadAdd":      gxui.KeyKpAdd,
+	"NumpadEnter":    gxui.KeyKpEnter,
+	"NumpadEqual":    gxui.KeyKpEqual,
+	"ShiftLeft":      gxui.KeyLeftShift,
+	"ControlLeft":    gxui.KeyLeftControl,
+	"AltLeft":        gxui.KeyLeftAlt,
+	"MetaLeft":       gxui.KeyLeftSuper,
+	"ShiftRight":     gxui.KeyRightShift,
+	"ControlRight":   gxui.KeyRightControl,
+	"AltRight":       gxui.KeyRightAlt,
+	"MetaRight":      gxui.KeyRightSuper,
+	"ContextMenu":    gxui.KeyMenu,
+}
+
+func translateKeyboardKey(code string) gxui.KeyboardKey {
+	if key, found := keys[code]; found {
+		return key
+	}
+	return gxui.KeyUnknown
+}
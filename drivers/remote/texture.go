@@ -0,0 +1,66 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"sync"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type texture struct {
+	id           int
+	image        image.Image
+	pixelsPerDip float32
+	flipY        bool
+
+	encodeOnce sync.Once
+	png        []byte // The image encoded as a PNG, sent to the browser
+}
+
+func newTexture(id int, img image.Image, pixelsPerDip float32) *texture {
+	return &texture{
+		id:           id,
+		image:        img,
+		pixelsPerDip: pixelsPerDip,
+	}
+}
+
+// encoded returns the image of the texture encoded as a PNG.
+func (t *texture) encoded() []byte {
+	t.encodeOnce.Do(func() {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, t.image); err != nil {
+			panic(err)
+		}
+		t.png = buf.Bytes()
+	})
+	return t.png
+}
+
+// gxui.Texture compliance
+func (t *texture) Image() image.Image {
+	return t.image
+}
+
+func (t *texture) Size() math.Size {
+	return t.SizePixels().ScaleS(1.0 / t.pixelsPerDip)
+}
+
+func (t *texture) SizePixels() math.Size {
+	s := t.image.Bounds().Size()
+	return math.Size{W: s.X, H: s.Y}
+}
+
+func (t *texture) FlipY() bool {
+	return t.flipY
+}
+
+func (t *texture) SetFlipY(flipY bool) {
+	t.flipY = flipY
+}
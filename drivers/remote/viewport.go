@@ -0,0 +1,296 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"sync"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type viewport struct {
+	sync.Mutex
+	headless.Events
+
+	driver           *driver
+	id               int
+	fullscreen       bool
+	scaling          float32
+	sizeDipsUnscaled math.Size
+	sizeDips         math.Size
+	position         math.Point
+	title            string
+	icon             image.Image
+	visible          bool
+	cursor           string // CSS cursor property value
+	canvas           *displaylist.Canvas
+	destroyed        bool
+	stats            gxui.RedrawStats
+
+	// Called once when the viewport is destroyed
+	onDestroy func()
+}
+
+func newViewport(driver *driver, width, height int, title string, fullscreen bool) *viewport {
+	v := &viewport{
+		driver:     driver,
+		id:         driver.newID(),
+		fullscreen: fullscreen,
+		scaling:    1,
+		title:      title,
+		visible:    true,
+	}
+	v.Events.Init(&driver.Driver)
+	v.sizeDipsUnscaled = math.Size{W: width, H: height}
+	v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / v.scaling)
+	return v
+}
+
+// gxui.Viewport compliance
+// These methods are all called on the application routine
+func (v *viewport) SetCanvas(cc gxui.Canvas) {
+	v.setCanvas(cc, v.sizePixels().Area(), true)
+}
+
+func (v *viewport) SetCanvasDamaged(cc gxui.Canvas, damage []math.Rect) {
+	drawn := 0
+	for _, r := range gxui.DamagePixels(damage, v.Scale(), v.sizePixels()) {
+		drawn += r.Size().Area()
+	}
+	v.setCanvas(cc, drawn, false)
+}
+
+func (v *viewport) setCanvas(cc gxui.Canvas, drawn int, full bool) {
+	var c *displaylist.Canvas
+	if cc != nil {
+		c = cc.(*displaylist.Canvas)
+	}
+	v.Lock()
+	if v.destroyed {
+		v.Unlock()
+		return
+	}
+	v.canvas = c
+	v.stats.Add(v.sizeDipsUnscaled, drawn, full)
+	v.Unlock()
+	if c != nil {
+		v.driver.present(v, c.DisplayList())
+	}
+}
+
+// displayList returns the display list of the canvas most recently presented
+// by the viewport, or nil if there is none.
+func (v *viewport) displayList() *displaylist.List {
+	v.Lock()
+	defer v.Unlock()
+	if v.canvas == nil {
+		return nil
+	}
+	return v.canvas.DisplayList()
+}
+
+// changed sends the state of the viewport to every connected client, and
+// redraws the viewport if its size or scale changed.
+func (v *viewport) changed(redraw bool) {
+	v.driver.broadcast(func(c *client) {
+		c.sendViewport(v)
+		if l := v.displayList(); redraw && l != nil {
+			c.sendFrame(v, l)
+		}
+	})
+}
+
+func (v *viewport) sizePixels() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizeDipsUnscaled
+}
+
+func (v *viewport) RedrawStats() gxui.RedrawStats {
+	v.Lock()
+	defer v.Unlock()
+	return v.stats
+}
+
+func (v *viewport) Scale() float32 {
+	v.Lock()
+	defer v.Unlock()
+	return v.scaling
+}
+
+func (v *viewport) SetScale(s float32) {
+	v.Lock()
+	if s != v.scaling {
+		v.scaling = s
+		v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / s)
+		v.ResizeEvent.Fire()
+	}
+	v.Unlock()
+	v.changed(true)
+}
+
+func (v *viewport) SizeDips() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizeDips
+}
+
+func (v *viewport) SetSizeDips(size math.Size) {
+	v.Lock()
+	v.sizeDips = size
+	v.sizeDipsUnscaled = size.ScaleS(v.scaling)
+	v.ResizeEvent.Fire()
+	v.Unlock()
+	v.changed(true)
+}
+
+func (v *viewport) SizePixels() math.Size {
+	return v.sizePixels()
+}
+
+func (v *viewport) Title() string {
+	v.Lock()
+	defer v.Unlock()
+	return v.title
+}
+
+func (v *viewport) SetTitle(title string) {
+	v.Lock()
+	v.title = title
+	v.Unlock()
+	v.changed(false)
+}
+
+func (v *viewport) Icon() image.Image {
+	v.Lock()
+	defer v.Unlock()
+	return v.icon
+}
+
+func (v *viewport) SetIcon(i image.Image) {
+	v.Lock()
+	defer v.Unlock()
+	v.icon = i
+}
+
+func (v *viewport) Position() math.Point {
+	v.Lock()
+	defer v.Unlock()
+	return v.position
+}
+
+func (v *viewport) SetPosition(pos math.Point) {
+	v.Lock()
+	defer v.Unlock()
+	v.position = pos
+}
+
+// cssCursors maps the gxui cursor shapes to CSS cursor property values.
+var cssCursors = map[gxui.CursorShape]string{
+	gxui.ArrowCursor:      "default",
+	gxui.IBeamCursor:      "text",
+	gxui.HandCursor:       "pointer",
+	gxui.ResizeNSCursor:   "ns-resize",
+	gxui.ResizeEWCursor:   "ew-resize",
+	gxui.ResizeNWSECursor: "nwse-resize",
+	gxui.MoveCursor:       "move",
+	gxui.WaitCursor:       "wait",
+	gxui.NotAllowedCursor: "not-allowed",
+}
+
+// cssCursor returns the CSS cursor property value displaying c. Custom cursors
+// are sent as PNG data URLs.
+func cssCursor(c gxui.Cursor) string {
+	if c.Shape == gxui.CustomCursor {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, c.Image); err == nil {
+			data := base64.StdEncoding.EncodeToString(buf.Bytes())
+			return fmt.Sprintf("url(data:image/png;base64,%s) %d %d, default", data, c.Hotspot.X, c.Hotspot.Y)
+		}
+	}
+	if css, found := cssCursors[c.Shape]; found {
+		return css
+	}
+	return "default"
+}
+
+func (v *viewport) SetCursor(c gxui.Cursor) {
+	css := cssCursor(c)
+	v.Lock()
+	if v.cursor == css {
+		v.Unlock()
+		return
+	}
+	v.cursor = css
+	v.Unlock()
+	v.changed(false)
+}
+
+// Browsers do not compose text in canvases, so input methods commit their
+// text with key-strokes and composition rectangles are ignored.
+func (v *viewport) SetCompositionRect(r math.Rect) {}
+
+func (v *viewport) Fullscreen() bool {
+	return v.fullscreen
+}
+
+func (v *viewport) Show() {
+	v.Lock()
+	v.visible = true
+	v.Unlock()
+	v.changed(false)
+}
+
+func (v *viewport) Hide() {
+	v.Lock()
+	v.visible = false
+	v.Unlock()
+	v.changed(false)
+}
+
+func (v *viewport) Close() {
+	v.CloseEvent.Fire()
+	v.Destroy()
+}
+
+func (v *viewport) Destroy() {
+	v.Lock()
+	if v.destroyed {
+		v.Unlock()
+		return
+	}
+	v.destroyed = true
+	v.canvas = nil
+	onDestroy := v.onDestroy
+	v.Unlock()
+	if onDestroy != nil {
+		onDestroy()
+	}
+}
+
+// state returns the message describing the viewport to the browser.
+func (v *viewport) state() serverMessage {
+	v.Lock()
+	defer v.Unlock()
+	size := v.sizeDipsUnscaled
+	return serverMessage{
+		Type:     "viewport",
+		Viewport: v.id,
+		Title:    v.title,
+		Size:     &size,
+		Scale:    v.scaling,
+		Hidden:   !v.visible,
+		Cursor:   v.cursor,
+	}
+}
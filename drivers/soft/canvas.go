@@ -0,0 +1,266 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type drawStateStack []drawState
+
+func (s *drawStateStack) head() *drawState {
+	return &(*s)[len(*s)-1]
+}
+func (s *drawStateStack) push(ds drawState) {
+	*s = append(*s, ds)
+}
+func (s *drawStateStack) pop() {
+	*s = (*s)[:len(*s)-1]
+}
+
+type canvasOp func(ctx *context, dss *drawStateStack)
+
+type drawState struct {
+	// The below are all in target image coordinates
+	ClipPixels   math.Rect
+	OriginPixels math.Point
+
+	// Transform, if not nil, maps local DIPs to target image pixels and is
+	// used in place of OriginPixels.
+	Transform *math.Mat3
+}
+
+type canvas struct {
+	sizeDips          math.Size
+	ops               []canvasOp
+	recorded          []displaylist.Op
+	built             bool
+	buildingPushCount int
+}
+
+func newCanvas(sizeDips math.Size) *canvas {
+	if sizeDips.W <= 0 || sizeDips.H < 0 {
+		panic(fmt.Errorf("Canvas width and height must be positive. Size: %d", sizeDips))
+	}
+	c := &canvas{
+		sizeDips: sizeDips,
+	}
+	return c
+}
+
+func (c *canvas) draw(ctx *context, dss *drawStateStack) {
+	for i, op := range c.ops {
+		ctx.canvasOps[c.recorded[i].Name()]++
+		op(ctx, dss)
+	}
+}
+
+func (c *canvas) appendOp(record displaylist.Op, op canvasOp) {
+	if c.built {
+		panic(fmt.Errorf("%s() called after Complete()", record.Name()))
+	}
+	c.ops = append(c.ops, op)
+	c.recorded = append(c.recorded, record)
+}
+
+// displaylist.Recorder compliance
+func (c *canvas) DisplayList() *displaylist.List {
+	return &displaylist.List{Size: c.sizeDips, Ops: c.recorded}
+}
+
+// gxui.Canvas compliance
+func (c *canvas) Size() math.Size {
+	return c.sizeDips
+}
+
+func (c *canvas) IsComplete() bool {
+	return c.built
+}
+
+func (c *canvas) Complete() {
+	if c.built {
+		panic("Complete() called twice")
+	}
+	if c.buildingPushCount != 0 {
+		panic(fmt.Errorf("Push() count was %d when calling Complete", c.buildingPushCount))
+	}
+	c.built = true
+}
+
+func (c *canvas) Push() {
+	c.buildingPushCount++
+	c.appendOp(displaylist.Push{}, func(ctx *context, dss *drawStateStack) {
+		dss.push(*dss.head())
+	})
+}
+
+func (c *canvas) PushTransform(m math.Mat3) {
+	c.buildingPushCount++
+	c.appendOp(displaylist.PushTransform{Transform: m}, func(ctx *context, dss *drawStateStack) {
+		dss.push(*dss.head())
+		ds := dss.head()
+		t := m.Mul(ctx.transform(ds))
+		ds.Transform = &t
+	})
+}
+
+func (c *canvas) Pop() {
+	c.buildingPushCount--
+	c.appendOp(displaylist.Pop{}, func(ctx *context, dss *drawStateStack) {
+		dss.pop()
+	})
+}
+
+func (c *canvas) PushLayer(opacity float32, blend gxui.BlendMode) {
+	c.buildingPushCount++
+	c.appendOp(displaylist.PushLayer{Opacity: opacity, Blend: blend}, func(ctx *context, dss *drawStateStack) {
+		dss.push(*dss.head())
+		ctx.pushLayer(dss.head().ClipPixels, opacity, blend)
+	})
+}
+
+func (c *canvas) PopLayer() {
+	c.buildingPushCount--
+	c.appendOp(displaylist.PopLayer{}, func(ctx *context, dss *drawStateStack) {
+		ctx.popLayer()
+		dss.pop()
+	})
+}
+
+func (c *canvas) AddClip(r math.Rect) {
+	c.appendOp(displaylist.AddClip{Rect: r}, func(ctx *context, dss *drawStateStack) {
+		ds := dss.head()
+		if ds.Transform != nil {
+			ds.ClipPixels = intersect(ds.ClipPixels, ds.Transform.TransformRect(r))
+			return
+		}
+		rectLocalPixels := ctx.resolution.rectDipsToPixels(r)
+		rectTargetPixels := rectLocalPixels.Offset(ds.OriginPixels)
+		ds.ClipPixels = intersect(ds.ClipPixels, rectTargetPixels)
+	})
+}
+
+func (c *canvas) Clear(color gxui.Color) {
+	c.appendOp(displaylist.Clear{Color: color}, func(ctx *context, dss *drawStateStack) {
+		clip := rectToImage(dss.head().ClipPixels)
+		draw.Draw(ctx.target, clip, image.NewUniform(colorToRGBA(color)), image.ZP, draw.Src)
+	})
+}
+
+func (c *canvas) DrawCanvas(cc gxui.Canvas, offsetDips math.Point) {
+	if cc == nil {
+		panic("Canvas cannot be nil")
+	}
+	childCanvas := cc.(*canvas)
+	record := displaylist.DrawCanvas{Canvas: childCanvas.DisplayList(), Offset: offsetDips}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
+		offsetPixels := ctx.resolution.pointDipsToPixels(offsetDips)
+		dss.push(*dss.head())
+		ds := dss.head()
+		if ds.Transform != nil {
+			t := math.CreateMat3Translate(offsetDips.Vec2()).Mul(*ds.Transform)
+			ds.Transform = &t
+		} else {
+			ds.OriginPixels = ds.OriginPixels.Add(offsetPixels)
+		}
+		childCanvas.draw(ctx, dss)
+		dss.pop()
+	})
+}
+
+func (c *canvas) DrawRunes(f gxui.Font, r []rune, p []math.Point, col gxui.Color) {
+	if f == nil {
+		panic("Font cannot be nil")
+	}
+	runes := append([]rune{}, r...)
+	points := append([]math.Point{}, p...)
+	record := displaylist.DrawRunes{
+		Font:   displaylist.CreateFontRef(f),
+		Runes:  runes,
+		Points: points,
+		Color:  col,
+	}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
+		f.(*font).DrawRunes(ctx, runes, points, col, dss.head())
+	})
+}
+
+func (c *canvas) DrawLines(lines gxui.Polygon, pen gxui.Pen) {
+	record := displaylist.DrawLines{Lines: append(gxui.Polygon{}, lines...), Pen: pen}
+	edge := openPolyToShape(lines, pen)
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
+		if edge != nil && pen.Color.A > 0 {
+			ctx.fillShape(*edge, gxui.CreateBrush(pen.Color), math.Rect{}, dss.head())
+		}
+	})
+}
+
+func (c *canvas) DrawPolygon(poly gxui.Polygon, pen gxui.Pen, brush gxui.Brush) {
+	record := displaylist.DrawPolygon{Polygon: append(gxui.Polygon{}, poly...), Pen: pen, Brush: brush}
+	c.appendOp(record, drawPolygon(poly, pen, brush))
+}
+
+func drawPolygon(poly gxui.Polygon, pen gxui.Pen, brush gxui.Brush) canvasOp {
+	fill, edge := closedPolyToShape(poly, pen)
+	bounds := poly.Bounds()
+	return func(ctx *context, dss *drawStateStack) {
+		ds := dss.head()
+		if fill != nil && brush.Color.A > 0 {
+			ctx.fillShape(*fill, brush, bounds, ds)
+		}
+		if edge != nil && pen.Color.A > 0 {
+			ctx.fillShape(*edge, gxui.CreateBrush(pen.Color), bounds, ds)
+		}
+	}
+}
+
+func (c *canvas) DrawRect(r math.Rect, brush gxui.Brush) {
+	c.appendOp(displaylist.DrawRect{Rect: r, Brush: brush}, drawRect(r, brush))
+}
+
+func drawRect(r math.Rect, brush gxui.Brush) canvasOp {
+	return func(ctx *context, dss *drawStateStack) {
+		ctx.fillRect(r, brush, dss.head())
+	}
+}
+
+func (c *canvas) DrawRoundedRect(r math.Rect, tl, tr, bl, br float32, pen gxui.Pen, brush gxui.Brush) {
+	record := displaylist.DrawRoundedRect{Rect: r, TL: tl, TR: tr, BL: bl, BR: br, Pen: pen, Brush: brush}
+	if tl == 0 && tr == 0 && bl == 0 && br == 0 && pen.Color.A == 0 {
+		c.appendOp(record, drawRect(r, brush))
+		return
+	}
+	p := gxui.Polygon{
+		gxui.PolygonVertex{Position: r.TL(), RoundedRadius: tl},
+		gxui.PolygonVertex{Position: r.TR(), RoundedRadius: tr},
+		gxui.PolygonVertex{Position: r.BR(), RoundedRadius: br},
+		gxui.PolygonVertex{Position: r.BL(), RoundedRadius: bl},
+	}
+	c.appendOp(record, drawPolygon(p, pen, brush))
+}
+
+func (c *canvas) DrawShadow(r math.Rect, s gxui.Shadow) {
+	c.appendOp(displaylist.DrawShadow{Rect: r, Shadow: s}, func(ctx *context, dss *drawStateStack) {
+		ctx.drawShadow(r, s, dss.head())
+	})
+}
+
+func (c *canvas) DrawTexture(t gxui.Texture, r math.Rect) {
+	if t == nil {
+		panic("Texture cannot be nil")
+	}
+
+	record := displaylist.DrawTexture{Texture: displaylist.CreateTextureRef(t), Rect: r}
+	c.appendOp(record, func(ctx *context, dss *drawStateStack) {
+		ctx.drawTexture(t.(*texture), r, dss.head())
+	})
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/vector"
+)
+
+// context holds the state used to rasterize a canvas into a target image.
+type context struct {
+	target     *image.RGBA
+	resolution resolution
+	rasterizer *vector.Rasterizer
+	layers     []layer
+	canvasOps  map[string]int // Canvas ops executed, by name
+}
+
+// layer is an off-screen image being drawn to between a PushLayer and the
+// matching PopLayer.
+type layer struct {
+	parent  *image.RGBA // The target drawn to before the layer was pushed
+	opacity float32
+	blend   gxui.BlendMode
+}
+
+func newContext(target *image.RGBA, pixelsPerDip float32) *context {
+	return &context{
+		target:     target,
+		resolution: resolution(pixelsPerDip*65536 + 0.5),
+		rasterizer: &vector.Rasterizer{},
+		canvasOps:  make(map[string]int),
+	}
+}
+
+// render draws the canvas c into the context's target image.
+func (ctx *context) render(c *canvas) {
+	dss := drawStateStack{drawState{
+		ClipPixels: imageToRect(ctx.target.Bounds()),
+	}}
+	c.draw(ctx, &dss)
+	if len(dss) != 1 {
+		panic("DrawStateStack count was not 1 after calling Canvas.Draw")
+	}
+}
+
+// pushLayer redirects drawing to a new transparent layer covering clip.
+func (ctx *context) pushLayer(clip math.Rect, opacity float32, blend gxui.BlendMode) {
+	ctx.layers = append(ctx.layers, layer{parent: ctx.target, opacity: opacity, blend: blend})
+	ctx.target = image.NewRGBA(rectToImage(clip).Intersect(ctx.target.Bounds()))
+}
+
+// popLayer composites the layer pushed by the last call to pushLayer onto
+// the target beneath it.
+func (ctx *context) popLayer() {
+	l := ctx.layers[len(ctx.layers)-1]
+	ctx.layers = ctx.layers[:len(ctx.layers)-1]
+	src, dst := ctx.target, l.parent
+	ctx.target = dst
+	opacity := math.Saturate(l.opacity)
+	b := src.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i, j := src.PixOffset(x, y), dst.PixOffset(x, y)
+			s, d := src.Pix[i:i+4:i+4], dst.Pix[j:j+4:j+4]
+			if s[3] == 0 {
+				continue
+			}
+			sa := float32(s[3]) / 255 * opacity
+			da := float32(d[3]) / 255
+			for c := 0; c < 4; c++ {
+				sc := float32(s[c]) / 255 * opacity
+				dc := float32(d[c]) / 255
+				d[c] = uint8(math.Saturate(blend(l.blend, sc, dc, sa, da))*255 + 0.5)
+			}
+		}
+	}
+}
+
+// blend returns the premultiplied color channel of the source channel s with
+// alpha sa composited over the destination channel d with alpha da. The
+// alpha channel itself is blended with s = sa and d = da.
+func blend(mode gxui.BlendMode, s, d, sa, da float32) float32 {
+	switch mode {
+	case gxui.MultiplyBlend:
+		return s*d + s*(1-da) + d*(1-sa)
+	case gxui.ScreenBlend:
+		return s + d - s*d
+	case gxui.AdditiveBlend:
+		return s + d
+	default:
+		return s + d*(1-sa)
+	}
+}
+
+// transform returns the transform from local DIPs to target image pixels
+// for the draw state ds.
+func (ctx *context) transform(ds *drawState) math.Mat3 {
+	if ds.Transform != nil {
+		return *ds.Transform
+	}
+	s := ctx.resolution.dipsToPixels()
+	return math.CreateMat3Scale(math.Vec2{X: s, Y: s}).Mul(math.CreateMat3Translate(ds.OriginPixels.Vec2()))
+}
+
+// fillRect fills the rectangle r, in DIPs, with brush.
+func (ctx *context) fillRect(r math.Rect, brush gxui.Brush, ds *drawState) {
+	if brush.Color.A == 0 {
+		return
+	}
+	if ds.Transform != nil {
+		quad := []math.Vec2{r.TL().Vec2(), r.TR().Vec2(), r.BR().Vec2(), r.BL().Vec2()}
+		ctx.fillShape(shape{paths: [][]math.Vec2{quad}}, brush, r, ds)
+		return
+	}
+	dstRect := intersect(ctx.resolution.rectDipsToPixels(r).Offset(ds.OriginPixels), ds.ClipPixels)
+	area := rectToImage(dstRect)
+	draw.Draw(ctx.target, area, ctx.paint(brush, r, area, ds), area.Min, draw.Over)
+}
+
+// fillShape fills the shape s with brush. bounds is the bounding rectangle
+// of the shape, in DIPs, used to position gradients and patterns.
+func (ctx *context) fillShape(s shape, brush gxui.Brush, bounds math.Rect, ds *drawState) {
+	clip := rectToImage(ds.ClipPixels).Intersect(ctx.target.Bounds())
+	if clip.Empty() {
+		return
+	}
+	// Rasterize relative to the top-left of the clip rectangle.
+	m := ctx.transform(ds).Mul(math.CreateMat3Translate(math.Vec2{X: float32(-clip.Min.X), Y: float32(-clip.Min.Y)}))
+	z := ctx.rasterizer
+	z.Reset(clip.Dx(), clip.Dy())
+	for _, path := range s.paths {
+		for i, v := range path {
+			p := m.Transform(v)
+			if i == 0 {
+				z.MoveTo(p.X, p.Y)
+			} else {
+				z.LineTo(p.X, p.Y)
+			}
+		}
+		z.ClosePath()
+	}
+	z.Draw(ctx.target, clip, ctx.paint(brush, bounds, clip, ds), clip.Min)
+}
+
+// paint returns the source image for filling the pixels of area with brush,
+// where bounds is the filled shape's bounds in DIPs. Gradients and patterns
+// are evaluated at the center of each pixel.
+func (ctx *context) paint(brush gxui.Brush, bounds math.Rect, area image.Rectangle, ds *drawState) image.Image {
+	if brush.IsSolid() {
+		return image.NewUniform(colorToRGBA(brush.Color))
+	}
+	img := image.NewRGBA(area)
+	pixelsToDips := ctx.transform(ds).Invert()
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			p := pixelsToDips.Transform(math.Vec2{X: float32(x) + 0.5, Y: float32(y) + 0.5})
+			img.SetRGBA(x, y, colorToRGBA(brush.ColorAt(p, bounds)))
+		}
+	}
+	return img
+}
+
+// drawTexture draws the texture t stretched over the rectangle r, in DIPs.
+func (ctx *context) drawTexture(t *texture, r math.Rect, ds *drawState) {
+	clip := rectToImage(ds.ClipPixels)
+	src := t.image
+	if t.flipY {
+		src = flipY(src)
+	}
+	dst := ctx.target.SubImage(clip).(*image.RGBA)
+	if ds.Transform == nil {
+		dstRect := ctx.resolution.rectDipsToPixels(r).Offset(ds.OriginPixels)
+		xdraw.BiLinear.Scale(dst, rectToImage(dstRect), src, src.Bounds(), xdraw.Over, nil)
+		return
+	}
+	sr := src.Bounds()
+	if sr.Empty() {
+		return
+	}
+	// Map source pixels to r, then through the transform.
+	m := math.CreateMat3Translate(math.Vec2{X: float32(-sr.Min.X), Y: float32(-sr.Min.Y)}).
+		Mul(math.CreateMat3Scale(math.Vec2{
+			X: float32(r.W()) / float32(sr.Dx()),
+			Y: float32(r.H()) / float32(sr.Dy()),
+		})).
+		Mul(math.CreateMat3Translate(r.Min.Vec2())).
+		Mul(*ds.Transform)
+	xdraw.BiLinear.Transform(dst, aff3(m), src, sr, xdraw.Over, nil)
+}
+
+// aff3 returns the 2D affine transform m in the form used by
+// golang.org/x/image/draw.
+func aff3(m math.Mat3) f64.Aff3 {
+	return f64.Aff3{
+		float64(m[0]), float64(m[3]), float64(m[6]),
+		float64(m[1]), float64(m[4]), float64(m[7]),
+	}
+}
+
+// colorToRGBA converts the non-premultiplied gxui.Color to a premultiplied
+// color.RGBA.
+func colorToRGBA(c gxui.Color) color.RGBA {
+	c = c.Saturate()
+	return color.RGBA{
+		R: uint8(c.R*c.A*255 + 0.5),
+		G: uint8(c.G*c.A*255 + 0.5),
+		B: uint8(c.B*c.A*255 + 0.5),
+		A: uint8(c.A*255 + 0.5),
+	}
+}
+
+func rectToImage(r math.Rect) image.Rectangle {
+	return image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+func imageToRect(r image.Rectangle) math.Rect {
+	return math.CreateRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// intersect returns the intersection of a and b. Unlike math.Rect.Intersect,
+// non-overlapping rectangles produce an empty rectangle.
+func intersect(a, b math.Rect) math.Rect {
+	return imageToRect(rectToImage(a).Intersect(rectToImage(b)))
+}
+
+func flipY(src image.Image) image.Image {
+	b := src.Bounds()
+	dst := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(x, b.Max.Y-1-(y-b.Min.Y), src.At(x, y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package soft contains a software rasterizing implementation of the
+// gxui.Driver interface. It requires no GPU or display, rendering each
+// viewport into an off-screen image.RGBA that can be read back.
+package soft
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Size of the virtual screen used by fullscreen viewports created with a
+// width or height of 0.
+var defaultScreenSize = math.Size{W: 1920, H: 1080}
+
+// An Opt is a type which modifies the Driver, usually during setup.
+type Opt = headless.Opt
+
+// An OptFunc is an Opt that doesn't carry any state.
+type OptFunc = headless.OptFunc
+
+// Debug is an Opt that sets d to debug mode (so that d.Debug() == true).
+func Debug() Opt {
+	return headless.Debug()
+}
+
+// ScreenSize is an Opt that sets the size of the virtual screen adopted by
+// fullscreen viewports.
+func ScreenSize(size math.Size) Opt {
+	return OptFunc(func(d gxui.Driver) gxui.Driver {
+		d.(*driver).screenSize = size
+		return d
+	})
+}
+
+type driver struct {
+	headless.Driver
+
+	screenSize math.Size
+}
+
+// StartDriver starts the software driver with the given appRoutine.
+// StartDriver blocks until the driver is terminated. Unlike the gl driver,
+// StartDriver has no requirement to be called on the main thread.
+func StartDriver(appRoutine func(driver gxui.Driver), opts ...Opt) {
+	d := &driver{
+		screenSize: defaultScreenSize,
+	}
+	d.Init(nil)
+	for _, opt := range opts {
+		d = opt.Apply(d).(*driver)
+	}
+	d.Run(func() { appRoutine(d) })
+}
+
+// gxui.Driver compliance
+func (d *driver) CreateFont(data []byte, size int) (gxui.Font, error) {
+	f, err := newFont(data, size)
+	if err != nil {
+		// Return an untyped nil, so that callers can compare the font to nil.
+		return nil, err
+	}
+	return f, nil
+}
+
+func (d *driver) CreateWindowedViewport(width, height int, name string) gxui.Viewport {
+	return d.createViewport(width, height, name, false)
+}
+
+func (d *driver) CreateFullscreenViewport(width, height int, name string) gxui.Viewport {
+	if width == 0 || height == 0 {
+		width, height = d.screenSize.WH()
+	}
+	return d.createViewport(width, height, name, true)
+}
+
+func (d *driver) createViewport(width, height int, name string, fullscreen bool) *viewport {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Errorf("Viewport width and height must be positive. Got %dx%d", width, height))
+	}
+	v := newViewport(d, width, height, name, fullscreen)
+	v.onDestroy = d.AddViewport(v)
+	return v
+}
+
+func (d *driver) CreateCanvas(s math.Size) gxui.Canvas {
+	return newCanvas(s)
+}
+
+func (d *driver) CreateTexture(img image.Image, pixelsPerDip float32) gxui.Texture {
+	return newTexture(img, pixelsPerDip)
+}
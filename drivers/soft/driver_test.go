@@ -0,0 +1,525 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/gxfont"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func run(t *testing.T, f func(driver gxui.Driver)) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+		f(driver)
+	})
+}
+
+func TestCanvasDrawRect(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 8, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 16, H: 8})
+		c.Clear(gxui.Blue)
+		c.Push()
+		c.AddClip(math.CreateRect(0, 0, 4, 8))
+		c.DrawRect(math.CreateRect(0, 0, 8, 8), gxui.CreateBrush(gxui.Red))
+		c.Pop()
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		if got, expected := img.Bounds().Size().X, 16; got != expected {
+			t.Errorf("Image width was %d, expected %d", got, expected)
+		}
+		red := color.RGBA{R: 0xff, A: 0xff}
+		blue := color.RGBA{B: 0xff, A: 0xff}
+		if got := img.RGBAAt(2, 4); got != red {
+			t.Errorf("Pixel (2, 4) was %v, expected %v", got, red)
+		}
+		if got := img.RGBAAt(6, 4); got != blue {
+			t.Errorf("Pixel (6, 4) was %v, expected %v", got, blue)
+		}
+	})
+}
+
+func TestCanvasRespectsScale(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 16, "test").(soft.Viewport)
+		v.SetScale(2)
+		if got, expected := v.SizeDips(), (math.Size{W: 8, H: 8}); got != expected {
+			t.Errorf("SizeDips was %v, expected %v", got, expected)
+		}
+		c := driver.CreateCanvas(v.SizeDips())
+		c.Clear(gxui.Black)
+		c.DrawRect(math.CreateRect(0, 0, 4, 4), gxui.CreateBrush(gxui.White))
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		black := color.RGBA{A: 0xff}
+		if got := img.RGBAAt(7, 7); got != white {
+			t.Errorf("Pixel (7, 7) was %v, expected %v", got, white)
+		}
+		if got := img.RGBAAt(9, 9); got != black {
+			t.Errorf("Pixel (9, 9) was %v, expected %v", got, black)
+		}
+
+		// Changing the scale redraws the last canvas at the new scale.
+		v.SetScale(1)
+		img = v.Image()
+		if got := img.RGBAAt(3, 3); got != white {
+			t.Errorf("Pixel (3, 3) was %v, expected %v", got, white)
+		}
+		if got := img.RGBAAt(7, 7); got != black {
+			t.Errorf("Pixel (7, 7) was %v, expected %v", got, black)
+		}
+	})
+}
+
+func TestCanvasGradientBrush(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 16, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 16, H: 16})
+		c.Clear(gxui.Black)
+		c.DrawRect(math.CreateRect(0, 0, 16, 8), gxui.CreateLinearGradientBrush(
+			math.Vec2{X: 0, Y: 0}, math.Vec2{X: 1, Y: 0},
+			gxui.GradientStop{Offset: 0, Color: gxui.Red},
+			gxui.GradientStop{Offset: 1, Color: gxui.Blue},
+		))
+		c.DrawRoundedRect(math.CreateRect(0, 8, 16, 16), 2, 2, 2, 2, gxui.TransparentPen,
+			gxui.CreateRadialGradientBrush(math.Vec2{X: 0.5, Y: 0.5}, 0.5,
+				gxui.GradientStop{Offset: 0, Color: gxui.White},
+				gxui.GradientStop{Offset: 1, Color: gxui.Black},
+			))
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		if left := img.RGBAAt(0, 4); left.R < 0xf0 || left.B > 0x10 {
+			t.Errorf("Left of the linear gradient was %v, expected red", left)
+		}
+		if right := img.RGBAAt(15, 4); right.B < 0xf0 || right.R > 0x10 {
+			t.Errorf("Right of the linear gradient was %v, expected blue", right)
+		}
+		if mid := img.RGBAAt(8, 4); mid.R < 0x60 || mid.B < 0x60 {
+			t.Errorf("Middle of the linear gradient was %v, expected purple", mid)
+		}
+		if center, edge := img.RGBAAt(8, 12), img.RGBAAt(2, 12); center.R <= edge.R {
+			t.Errorf("Radial gradient center %v should be lighter than its edge %v", center, edge)
+		}
+	})
+}
+
+func TestCanvasPatternBrush(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		tile := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		tile.SetRGBA(0, 0, color.RGBA{R: 0xff, A: 0xff})
+		tile.SetRGBA(1, 1, color.RGBA{R: 0xff, A: 0xff})
+		texture := driver.CreateTexture(tile, 1)
+
+		v := driver.CreateWindowedViewport(8, 8, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 8, H: 8})
+		c.Clear(gxui.Black)
+		c.DrawRect(math.CreateRect(1, 1, 7, 7), gxui.CreatePatternBrush(texture))
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		red := color.RGBA{R: 0xff, A: 0xff}
+		black := color.RGBA{A: 0xff}
+		for _, test := range []struct {
+			x, y     int
+			expected color.RGBA
+		}{
+			{1, 1, red}, {2, 1, black}, {2, 2, red}, {5, 3, red}, {5, 4, black}, {0, 0, black},
+		} {
+			if got := img.RGBAAt(test.x, test.y); got != test.expected {
+				t.Errorf("Pixel (%d, %d) was %v, expected %v", test.x, test.y, got, test.expected)
+			}
+		}
+	})
+}
+
+func TestCanvasDashedPen(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(20, 20, "test").(soft.Viewport)
+		dashed := gxui.CreateDashedPen(2, gxui.White, 4)
+		offset := dashed
+		offset.DashOffset = 2
+		square := gxui.CreatePen(2, gxui.White)
+		square.Cap = gxui.SquareCap
+
+		c := driver.CreateCanvas(math.Size{W: 20, H: 20})
+		c.Clear(gxui.Black)
+		// Lines are stroked below the line, for lines drawn left to right.
+		line := func(y, x0, x1 int, pen gxui.Pen) {
+			c.DrawLines(gxui.Polygon{
+				{Position: math.Point{X: x0, Y: y}},
+				{Position: math.Point{X: x1, Y: y}},
+			}, pen)
+		}
+		line(0, 0, 20, dashed)
+		line(4, 0, 20, offset)
+		line(8, 4, 12, square)
+		line(12, 4, 12, gxui.CreatePen(2, gxui.White))
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		for _, test := range []struct {
+			x, y int
+			lit  bool
+		}{
+			{1, 0, true}, {3, 1, true}, {5, 0, false}, {7, 1, false}, {9, 0, true},
+			{1, 4, true}, {3, 4, false}, {5, 5, false}, {7, 4, true},
+			{3, 8, true}, {12, 9, true}, {2, 8, false}, {13, 8, false},
+			{3, 12, false}, {4, 12, true}, {11, 12, true}, {12, 12, false},
+		} {
+			if got := img.RGBAAt(test.x, test.y).R > 0x80; got != test.lit {
+				t.Errorf("Pixel (%d, %d) lit was %v, expected %v", test.x, test.y, got, test.lit)
+			}
+		}
+	})
+}
+
+func TestCanvasPenJoins(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(30, 10, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 30, H: 10})
+		c.Clear(gxui.Black)
+		for i, join := range []gxui.LineJoin{gxui.MiterJoin, gxui.RoundJoin, gxui.BevelJoin} {
+			pen := gxui.CreatePen(3, gxui.White)
+			pen.Join = join
+			c.DrawRoundedRect(math.CreateRect(i*10, 0, i*10+10, 10), 0, 0, 0, 0, pen, gxui.TransparentBrush)
+		}
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		for i, expected := range []bool{true, false, false} {
+			if got := img.RGBAAt(i*10, 0).R > 0x80; got != expected {
+				t.Errorf("Corner of join %d lit was %v, expected %v", i, got, expected)
+			}
+			if got := img.RGBAAt(i*10+2, 2).R > 0x80; !got {
+				t.Errorf("Expected the inside of the corner of join %d to be lit", i)
+			}
+		}
+	})
+}
+
+func TestCanvasPushTransform(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 16, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 16, H: 16})
+		c.Clear(gxui.Black)
+		// Rotate a 8x4 rect by 90 degrees about the origin and move it right,
+		// making a 4x8 rect at (12, 0).
+		c.PushTransform(math.CreateMat3Rotate(math.Pi / 2).Mul(math.CreateMat3Translate(math.Vec2{X: 16})))
+		c.DrawRect(math.CreateRect(0, 0, 8, 4), gxui.CreateBrush(gxui.White))
+		c.Pop()
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		black := color.RGBA{A: 0xff}
+		for _, test := range []struct {
+			x, y     int
+			expected color.RGBA
+		}{
+			{14, 2, white},
+			{14, 6, white},
+			{10, 2, black},
+			{14, 10, black},
+		} {
+			if got := img.RGBAAt(test.x, test.y); got != test.expected {
+				t.Errorf("Pixel (%d, %d) was %v, expected %v", test.x, test.y, got, test.expected)
+			}
+		}
+	})
+}
+
+func TestCanvasPushLayer(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 8, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 16, H: 8})
+		c.Clear(gxui.Black)
+		// Overlapping rects in a layer fade as one.
+		c.PushLayer(0.5, gxui.NormalBlend)
+		c.DrawRect(math.CreateRect(0, 0, 6, 8), gxui.CreateBrush(gxui.White))
+		c.DrawRect(math.CreateRect(2, 0, 8, 8), gxui.CreateBrush(gxui.White))
+		c.PopLayer()
+		c.Push()
+		c.AddClip(math.CreateRect(8, 0, 16, 8))
+		c.Clear(gxui.Color{R: 1, G: 0.5, B: 1, A: 1})
+		c.PushLayer(1, gxui.MultiplyBlend)
+		c.DrawRect(math.CreateRect(8, 0, 16, 8), gxui.CreateBrush(gxui.Color{R: 0.5, G: 1, B: 0, A: 1}))
+		c.PopLayer()
+		c.Pop()
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		gray := color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+		multiplied := color.RGBA{R: 0x80, G: 0x80, A: 0xff}
+		for _, test := range []struct {
+			x, y     int
+			expected color.RGBA
+		}{
+			{1, 4, gray},
+			{4, 4, gray},
+			{7, 4, gray},
+			{12, 4, multiplied},
+		} {
+			if got := img.RGBAAt(test.x, test.y); got != test.expected {
+				t.Errorf("Pixel (%d, %d) was %v, expected %v", test.x, test.y, got, test.expected)
+			}
+		}
+	})
+}
+
+func TestCanvasDrawShadow(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(32, 16, "test").(soft.Viewport)
+		c := driver.CreateCanvas(math.Size{W: 32, H: 16})
+		c.Clear(gxui.White)
+		// A hard-edged shadow spreading 2 DIPs around a gray square.
+		r := math.CreateRect(4, 4, 12, 12)
+		c.DrawRect(r, gxui.CreateBrush(gxui.Gray50))
+		c.DrawShadow(r, gxui.Shadow{Color: gxui.Black, Spread: 2})
+		// A backdrop blur across a black and white edge.
+		c.DrawRect(math.CreateRect(16, 0, 24, 16), gxui.CreateBrush(gxui.Black))
+		c.DrawShadow(math.CreateRect(16, 0, 32, 16), gxui.Shadow{BackdropBlur: 2})
+		c.Complete()
+		v.SetCanvas(c)
+
+		img := v.Image()
+		white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		black := color.RGBA{A: 0xff}
+		gray := img.RGBAAt(8, 8)
+		for _, test := range []struct {
+			x, y     int
+			expected color.RGBA
+		}{
+			{1, 8, white},
+			{3, 8, black},
+			{8, 13, black},
+			{8, 8, gray},
+			{16, 8, black},
+			{31, 8, white},
+		} {
+			if got := img.RGBAAt(test.x, test.y); got != test.expected {
+				t.Errorf("Pixel (%d, %d) was %v, expected %v", test.x, test.y, got, test.expected)
+			}
+		}
+		if got := img.RGBAAt(23, 8); got.R == 0 || got.R == 0xff {
+			t.Errorf("Pixel (23, 8) was %v, expected a blurred edge", got)
+		}
+		if gray == white || gray == black {
+			t.Errorf("Pixel (8, 8) was %v, expected the rectangle to be unshadowed", gray)
+		}
+	})
+}
+
+func TestDriverStats(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		v := driver.CreateWindowedViewport(16, 8, "test").(soft.Viewport)
+		before := driver.Stats()
+		c := driver.CreateCanvas(math.Size{W: 16, H: 8})
+		c.Clear(gxui.Blue)
+		c.DrawRect(math.CreateRect(0, 0, 8, 8), gxui.CreateBrush(gxui.Red))
+		c.DrawRect(math.CreateRect(8, 0, 16, 8), gxui.CreateBrush(gxui.Green))
+		c.Complete()
+		v.SetCanvas(c)
+
+		after := driver.Stats()
+		if got, expected := after.Frames, before.Frames+1; got != expected {
+			t.Errorf("Frames was %d, expected %d", got, expected)
+		}
+		frames := 0
+		for _, n := range after.FrameTimes {
+			frames += n
+		}
+		if frames != after.Frames {
+			t.Errorf("FrameTimes counted %d frames, expected %d", frames, after.Frames)
+		}
+		if got := after.CanvasOps["DrawRect"]; got != 2 {
+			t.Errorf("CanvasOps[DrawRect] was %d, expected 2", got)
+		}
+		if got := after.CanvasOps["Clear"]; got != 1 {
+			t.Errorf("CanvasOps[Clear] was %d, expected 1", got)
+		}
+	})
+}
+
+func TestDriverOnFrame(t *testing.T) {
+	times := []time.Duration{}
+	// The driver is terminated by the last frame, as run would terminate it
+	// before the first frame.
+	soft.StartDriver(func(driver gxui.Driver) {
+		var frame gxui.EventSubscription
+		frame = driver.OnFrame(func(t time.Duration) {
+			times = append(times, t)
+			if len(times) == 3 {
+				frame.Unlisten()
+				driver.OnFrame(func(time.Duration) { driver.Terminate() })
+			}
+		})
+	})
+	if len(times) != 3 {
+		t.Fatalf("Got %d frames, expected 3", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i] <= times[i-1] {
+			t.Errorf("Frame times were not increasing: %v", times)
+		}
+	}
+}
+
+func TestWindowRendersLabel(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window := theme.CreateWindow(64, 32, "test")
+		label := theme.CreateLabel()
+		label.SetText("Hello")
+		window.AddChild(label)
+
+		// The window draws on the next update, queued on the driver.
+		driver.Call(func() {
+			img := window.Viewport().(soft.Viewport).Image()
+			bg := color.RGBA{A: 0xff}
+			lit := 0
+			for y := 0; y < 32; y++ {
+				for x := 0; x < 64; x++ {
+					if img.RGBAAt(x, y) != bg {
+						lit++
+					}
+				}
+			}
+			if lit == 0 {
+				t.Error("Expected the label text to be rendered")
+			}
+			window.Close()
+		})
+	})
+}
+
+func TestWindowRedrawsDamage(t *testing.T) {
+	// The driver is terminated by the last queued call, as run would
+	// terminate it before the frames queued by the test are drawn.
+	soft.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window := theme.CreateWindow(64, 32, "test")
+		label := theme.CreateLabel()
+		label.SetText("Hello")
+		window.AddChild(label)
+
+		driver.Call(func() {
+			viewport := window.Viewport().(soft.Viewport)
+			before := viewport.RedrawStats()
+			label.SetColor(gxui.Red)
+
+			driver.Call(func() {
+				after := viewport.RedrawStats()
+				if got, expected := after.Frames, before.Frames+1; got != expected {
+					t.Errorf("Frames was %d, expected %d", got, expected)
+				}
+				if got, expected := after.FullFrames, before.FullFrames; got != expected {
+					t.Errorf("FullFrames was %d, expected %d", got, expected)
+				}
+				drawn := after.PixelsDrawn - before.PixelsDrawn
+				if drawn <= 0 || drawn >= 64*32 {
+					t.Errorf("Redrew %d pixels, expected only the label's bounds", drawn)
+				}
+
+				// The partial redraw matches redrawing the whole window.
+				partial := viewport.Image()
+				window.Redraw()
+				driver.Call(func() {
+					if got := viewport.RedrawStats().FullFrames; got != after.FullFrames+1 {
+						t.Errorf("FullFrames was %d, expected %d", got, after.FullFrames+1)
+					}
+					if !bytes.Equal(partial.Pix, viewport.Image().Pix) {
+						t.Error("Expected the partially redrawn frame to match the fully redrawn frame")
+					}
+					window.Close()
+					driver.Terminate()
+				})
+			})
+		})
+	})
+}
+
+func TestRenderToImage(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		label := theme.CreateLabel()
+		label.SetText("Hello")
+
+		img1x := gxui.RenderToImage(theme, label, math.MaxSize, 1)
+		img2x := gxui.RenderToImage(theme, label, math.MaxSize, 2)
+		if label.Attached() {
+			t.Error("Expected the label to be detached after RenderToImage")
+		}
+
+		s1x, s2x := img1x.Bounds().Size(), img2x.Bounds().Size()
+		if s1x.X == 0 || s1x.Y == 0 {
+			t.Fatalf("Expected a non-empty image, got %v", s1x)
+		}
+		if s2x.X != s1x.X*2 || s2x.Y != s1x.Y*2 {
+			t.Errorf("Expected the @2x image to be %v, got %v", s1x.Mul(2), s2x)
+		}
+
+		opaque := 0
+		for i := 3; i < len(img1x.Pix); i += 4 {
+			if img1x.Pix[i] != 0 {
+				opaque++
+			}
+		}
+		if opaque == 0 {
+			t.Error("Expected the label text to be rendered")
+		}
+	})
+}
+
+// TestFontConcurrentUse measures and draws text with a font from several
+// go-routines, which is reported by the race detector if the font's caches
+// are not guarded.
+func TestFontConcurrentUse(t *testing.T) {
+	run(t, func(driver gxui.Driver) {
+		font, err := driver.CreateFont(gxfont.Default, 12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v := driver.CreateWindowedViewport(32, 16, "test").(soft.Viewport)
+				for j := 0; j < 10; j++ {
+					runes := []rune{rune('a' + i*10 + j), 'x'}
+					font.Measure(&gxui.TextBlock{Runes: runes})
+					c := driver.CreateCanvas(math.Size{W: 32, H: 16})
+					c.DrawRunes(font, runes, []math.Point{{X: 0, Y: 0}, {X: 8, Y: 0}}, gxui.White)
+					c.Complete()
+					v.SetCanvas(c)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sync"
+	"unicode"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+
+	"github.com/golang/freetype/truetype"
+	xdraw "golang.org/x/image/draw"
+	fnt "golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+type font struct {
+	size             int
+	scale            fixed.Int26_6
+	glyphMaxSizeDips math.Size
+	ascentDips       int
+	ttf              *truetype.Font
+	family           string
+	data             []byte
+
+	// mutex guards faces and glyphAdvanceDips, and the use of the faces, as
+	// fonts are used by the rasterizer and by other go-routines.
+	mutex            sync.Mutex
+	faces            map[resolution]fnt.Face
+	glyphAdvanceDips map[rune]int
+}
+
+func newFont(data []byte, size int) (*font, error) {
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Metrics are rounded exactly as the gl driver does, so that both drivers
+	// lay out text identically.
+	scale := fixed.Int26_6(size << 6)
+	b := ttf.Bounds(scale)
+	bounds := math.CreateRect(int(b.Min.X)>>6, int(b.Min.Y)>>6, int(b.Max.X)>>6, int(b.Max.Y)>>6)
+	ascentDips := bounds.Max.Y
+
+	return &font{
+		size:             size,
+		scale:            scale,
+		glyphMaxSizeDips: bounds.Size(),
+		ascentDips:       ascentDips,
+		ttf:              ttf,
+		family:           ttf.Name(truetype.NameIDFontFamily),
+		data:             data,
+		faces:            make(map[resolution]fnt.Face),
+		glyphAdvanceDips: make(map[rune]int),
+	}, nil
+}
+
+func (f *font) advanceDips(r rune) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if g, found := f.glyphAdvanceDips[r]; found {
+		return g
+	}
+	idx := f.ttf.Index(r)
+	gb := &truetype.GlyphBuf{}
+	err := gb.Load(f.ttf, f.scale, idx, fnt.HintingFull)
+	if err != nil {
+		panic(err)
+	}
+
+	advance := int((gb.AdvanceWidth + 0x3f) >> 6)
+	f.glyphAdvanceDips[r] = advance
+	return advance
+}
+
+// face returns the face used to draw the font at the given resolution.
+// Must be called with the font's mutex locked, which must be held for as long
+// as the face is used.
+func (f *font) face(resolution resolution) fnt.Face {
+	face, found := f.faces[resolution]
+	if !found {
+		opt := truetype.Options{
+			Size:    float64(f.size),
+			DPI:     float64(resolution.intDipsToPixels(72)),
+			Hinting: fnt.HintingFull,
+		}
+		face = truetype.NewFace(f.ttf, &opt)
+		f.faces[resolution] = face
+	}
+	return face
+}
+
+func (f *font) align(rect math.Rect, size math.Size, ascent int, h gxui.HorizontalAlignment, v gxui.VerticalAlignment) math.Point {
+	var origin math.Point
+	switch h {
+	case gxui.AlignLeft:
+		origin.X = rect.Min.X
+	case gxui.AlignCenter:
+		origin.X = rect.Mid().X - (size.W / 2)
+	case gxui.AlignRight:
+		origin.X = rect.Max.X - size.W
+	}
+	switch v {
+	case gxui.AlignTop:
+		origin.Y = rect.Min.Y + ascent
+	case gxui.AlignMiddle:
+		origin.Y = rect.Mid().Y - (size.H / 2) + ascent
+	case gxui.AlignBottom:
+		origin.Y = rect.Max.Y - size.H + ascent
+	}
+	return origin
+}
+
+func (f *font) DrawRunes(ctx *context, runes []rune, offsets []math.Point, col gxui.Color, ds *drawState) {
+	if len(runes) != len(offsets) {
+		panic(fmt.Errorf("There must be the same number of runes to offsets. Got %d runes and %d offsets",
+			len(runes), len(offsets)))
+	}
+	if col.A == 0 {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	face := f.face(ctx.resolution)
+	src := image.NewUniform(colorToRGBA(col))
+	clip := rectToImage(ds.ClipPixels)
+
+	if ds.Transform != nil {
+		f.drawTransformedRunes(ctx, face, runes, offsets, col, ds)
+		return
+	}
+
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		p := ctx.resolution.pointDipsToPixels(offsets[i]).Add(ds.OriginPixels)
+		dot := fixed.P(p.X, p.Y)
+		dr, mask, maskp, _, ok := face.Glyph(dot, r)
+		if !ok {
+			continue
+		}
+		clipped := dr.Intersect(clip)
+		if clipped.Empty() {
+			continue
+		}
+		maskp = maskp.Add(clipped.Min.Sub(dr.Min))
+		draw.DrawMask(ctx.target, clipped, src, image.ZP, mask, maskp, draw.Over)
+	}
+}
+
+// drawTransformedRunes draws each glyph, rasterized at the context's
+// resolution, through the draw state's transform.
+func (f *font) drawTransformedRunes(ctx *context, face fnt.Face, runes []rune, offsets []math.Point, col gxui.Color, ds *drawState) {
+	dst := ctx.target.SubImage(rectToImage(ds.ClipPixels)).(*image.RGBA)
+	src := image.NewUniform(colorToRGBA(col))
+	pixelsToDips := 1 / ctx.resolution.dipsToPixels()
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		p := ctx.resolution.pointDipsToPixels(offsets[i])
+		dr, mask, maskp, _, ok := face.Glyph(fixed.P(p.X, p.Y), r)
+		if !ok || dr.Empty() {
+			continue
+		}
+		glyph := image.NewRGBA(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+		draw.DrawMask(glyph, glyph.Bounds(), src, image.ZP, mask, maskp, draw.Src)
+		m := math.CreateMat3Translate(math.Vec2{X: float32(dr.Min.X), Y: float32(dr.Min.Y)}).
+			Mul(math.CreateMat3Scale(math.Vec2{X: pixelsToDips, Y: pixelsToDips})).
+			Mul(*ds.Transform)
+		xdraw.BiLinear.Transform(dst, aff3(m), glyph, glyph.Bounds(), xdraw.Over, nil)
+	}
+}
+
+// gxui.NamedFont compliance
+func (f *font) Family() string {
+	return f.family
+}
+
+// gxui.TrueTypeFont compliance
+func (f *font) TrueTypeData() []byte {
+	return f.data
+}
+
+// gxui.Font compliance
+func (f *font) Index(r rune) truetype.Index {
+	return f.ttf.Index(r)
+}
+
+func (f *font) Size() int {
+	return f.size
+}
+
+func (f *font) Measure(fl *gxui.TextBlock) math.Size {
+	size := math.Size{W: 0, H: f.glyphMaxSizeDips.H}
+	var offset math.Point
+	for _, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.glyphMaxSizeDips.H
+			continue
+		}
+		offset.X += f.advanceDips(r)
+		size = size.Max(math.Size{W: offset.X, H: offset.Y + f.glyphMaxSizeDips.H})
+	}
+	return size
+}
+
+func (f *font) Layout(fl *gxui.TextBlock) (offsets []math.Point) {
+	sizeDips := math.Size{}
+	offsets = make([]math.Point, len(fl.Runes))
+	var offset math.Point
+	for i, r := range fl.Runes {
+		if r == '\n' {
+			offset.X = 0
+			offset.Y += f.glyphMaxSizeDips.H
+			continue
+		}
+
+		offsets[i] = offset
+		offset.X += f.advanceDips(r)
+		sizeDips = sizeDips.Max(math.Size{W: offset.X, H: offset.Y + f.glyphMaxSizeDips.H})
+	}
+
+	origin := f.align(fl.AlignRect, sizeDips, f.ascentDips, fl.H, fl.V)
+	for i, p := range offsets {
+		offsets[i] = p.Add(origin)
+	}
+	return offsets
+}
+
+func (f *font) LoadGlyphs(first, last rune) {
+	if first > last {
+		first, last = last, first
+	}
+	for r := first; r < last; r++ {
+		f.advanceDips(r)
+	}
+}
+
+func (f *font) GlyphMaxSize() math.Size {
+	return f.glyphMaxSizeDips
+}
@@ -0,0 +1,207 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/internal/stroke"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// shape is a set of closed paths in DIPs. Overlapping paths of the same
+// winding are unioned when rasterized.
+type shape struct {
+	paths [][]math.Vec2
+}
+
+type edgePair = stroke.EdgePair
+
+func pruneDuplicates(p gxui.Polygon) gxui.Polygon {
+	pruned := make(gxui.Polygon, 0, len(p))
+	last := gxui.PolygonVertex{}
+	for i, v := range p {
+		if i == 0 || last.Position.Sub(v.Position).Vec2().Len() > 0.001 {
+			pruned = append(pruned, v)
+		}
+		last = v
+	}
+	return pruned
+}
+
+// segment is the software equivalent of the gl driver's segment function,
+// appending the stroke edge pairs for the vertex a with neighbours b and c.
+// See drivers/gl/polygon.go for a diagram of the terms used below.
+func segment(penWidth, r float32, join gxui.LineJoin, a, b, c math.Vec2, aIsLast bool, edge []edgePair) []edgePair {
+	ba, ca := a.Sub(b), a.Sub(c)
+	baLen, caLen := ba.Len(), ca.Len()
+	baDir, caDir := ba.DivS(baLen), ca.DivS(caLen)
+	dp := baDir.Dot(caDir)
+	if dp < -0.99999 {
+		// Straight lines cause DBZs, special case
+		inner := a.Sub(caDir.Tangent().MulS(penWidth))
+		return append(edge, edgePair{Outer: a, Inner: inner})
+	}
+	α := math.Acosf(dp) / 2
+	v := baDir.Add(caDir).Normalize()
+	u := v.Tangent()
+	convex := baDir.Tangent().Dot(caDir) <= 0
+	if convex && join == gxui.RoundJoin {
+		r = math.Maxf(r, penWidth)
+	}
+	d := r / math.Sinf(α)
+
+	// X cannot be futher than half way along ab or ac
+	dMax := math.Minf(baLen, caLen) / (2 * math.Cosf(α))
+	if d > dMax {
+		// Adjust d and r to compensate
+		d = dMax
+		r = d * math.Sinf(α)
+	}
+
+	x := a.Sub(v.MulS(d))
+
+	w := penWidth
+	β := math.Pi/2 - α
+
+	// Special case for convex vertices where the pen width is greater than
+	// the rounding.
+	useFixedInnerPoint := convex && w > r
+	fixedInnerPoint := a.Sub(v.MulS(math.Minf(w/math.Sinf(α), dMax)))
+
+	if useFixedInnerPoint && r == 0 && join == gxui.BevelJoin {
+		// Cut the corner between the feet of the perpendiculars from the
+		// inner point to each edge.
+		t := math.Minf(w/math.Tanf(α), math.Minf(baLen, caLen)/2)
+		return append(edge,
+			edgePair{Outer: a.Sub(baDir.MulS(t)), Inner: fixedInnerPoint},
+			edgePair{Outer: a.Sub(caDir.MulS(t)), Inner: fixedInnerPoint})
+	}
+
+	// Concave vertices behave much the same as convex, but we have to flip
+	// β as the sweep is reversed and w as we're extruding.
+	if !convex {
+		w, β = -w, -β
+	}
+
+	steps := 1 + int(d*α)
+
+	if aIsLast {
+		// No curvy edge required for the last vertex.
+		// This is already done by the first vertex.
+		steps = 1
+	}
+
+	for j := 0; j < steps; j++ {
+		γ := float32(0)
+		if steps > 1 {
+			γ = math.Lerpf(-β, β, float32(j)/float32(steps-1))
+		}
+
+		dir := v.MulS(math.Cosf(γ)).Add(u.MulS(math.Sinf(γ)))
+		va := x.Add(dir.MulS(r))
+		vb := va.Sub(dir.MulS(w))
+		if useFixedInnerPoint {
+			vb = fixedInnerPoint
+		}
+		edge = append(edge, edgePair{Outer: va, Inner: vb})
+	}
+	return edge
+}
+
+// stripsToShape converts the strips of edge pairs into a shape of
+// consistently wound quads.
+func stripsToShape(strips [][]edgePair) *shape {
+	s := &shape{}
+	for _, edge := range strips {
+		s.appendStrip(edge)
+	}
+	if len(s.paths) == 0 {
+		return nil
+	}
+	return s
+}
+
+func (s *shape) appendStrip(edge []edgePair) {
+	for i := 0; i+1 < len(edge); i++ {
+		a, b := edge[i], edge[i+1]
+		quad := []math.Vec2{a.Outer, b.Outer, b.Inner, a.Inner}
+		switch area := signedArea(quad); {
+		case area < 0:
+			quad[0], quad[1], quad[2], quad[3] = quad[3], quad[2], quad[1], quad[0]
+		case area == 0:
+			continue
+		}
+		s.paths = append(s.paths, quad)
+	}
+}
+
+func signedArea(path []math.Vec2) float32 {
+	area := float32(0)
+	for i, a := range path {
+		b := path[(i+1)%len(path)]
+		area += a.Cross(b)
+	}
+	return area / 2
+}
+
+func closedPolyToShape(p gxui.Polygon, pen gxui.Pen) (fillShape, edgeShape *shape) {
+	p = pruneDuplicates(p)
+
+	edge := []edgePair{}
+	for i, cnt := 0, len(p); i < cnt; i++ {
+		r := p[i].RoundedRadius
+		a := p[i].Position.Vec2()
+		b := p[(i+cnt-1)%cnt].Position.Vec2()
+		c := p[(i+1)%cnt].Position.Vec2()
+		edge = segment(pen.Width, r, pen.Join, a, b, c, i == len(p), edge)
+	}
+	if len(edge) < 3 {
+		return nil, nil
+	}
+
+	fill := make([]math.Vec2, len(edge))
+	for i, e := range edge {
+		fill[i] = e.Inner
+	}
+	fillShape = &shape{paths: [][]math.Vec2{fill}}
+
+	// Close the edge
+	edge = append(edge, edge[0])
+	if pen.Width > 0 {
+		edgeShape = stripsToShape(stroke.Strips(edge, pen, true))
+	}
+	return fillShape, edgeShape
+}
+
+func openPolyToShape(p gxui.Polygon, pen gxui.Pen) *shape {
+	p = pruneDuplicates(p)
+	penWidth := pen.Width
+	if len(p) < 2 || penWidth <= 0 {
+		return nil
+	}
+
+	edge := []edgePair{}
+	{ // p[0] -> p[1]
+		a, c := p[0].Position.Vec2(), p[1].Position.Vec2()
+		caDir := a.Sub(c).Normalize()
+		inner := a.Sub(caDir.Tangent().MulS(penWidth))
+		edge = append(edge, edgePair{Outer: a, Inner: inner})
+	}
+	for i := 1; i < len(p)-1; i++ {
+		r := p[i].RoundedRadius
+		a := p[i].Position.Vec2()
+		b := p[i-1].Position.Vec2()
+		c := p[i+1].Position.Vec2()
+		edge = segment(penWidth, r, pen.Join, a, b, c, false, edge)
+	}
+	{ // p[N-2] -> p[N-1]
+		a, c := p[len(p)-2].Position.Vec2(), p[len(p)-1].Position.Vec2()
+		caDir := a.Sub(c).Normalize()
+		inner := c.Sub(caDir.Tangent().MulS(penWidth))
+		edge = append(edge, edgePair{Outer: c, Inner: inner})
+	}
+	return stripsToShape(stroke.Strips(edge, pen, false))
+}
@@ -0,0 +1,20 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"image"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// gxui.CanvasRasterizer compliance
+func (d *driver) RasterizeCanvas(cc gxui.Canvas, pixelsPerDip float32) *image.RGBA {
+	c := cc.(*canvas)
+	w, h := c.sizeDips.ScaleS(pixelsPerDip).WH()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	newContext(img, pixelsPerDip).render(c)
+	return img
+}
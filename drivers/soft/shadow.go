@@ -0,0 +1,106 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// drawShadow blurs the target behind the rectangle r, in DIPs, and then
+// draws the shadow s cast by r.
+func (ctx *context) drawShadow(r math.Rect, s gxui.Shadow, ds *drawState) {
+	m := ctx.transform(ds)
+	clip := rectToImage(ds.ClipPixels).Intersect(ctx.target.Bounds())
+	// The scale from DIPs to pixels, ignoring any rotation.
+	scale := math.Sqrtf(math.Absf(m[0]*m[4] - m[1]*m[3]))
+
+	if s.BackdropBlur > 0 {
+		ctx.blur(rectToImage(m.TransformRect(r)).Intersect(clip), s.BackdropBlur*scale)
+	}
+	if s.Color.A <= 0 {
+		return
+	}
+	area := rectToImage(m.TransformRect(s.Bounds(r))).Intersect(clip)
+	if area.Empty() {
+		return
+	}
+	mask := image.NewAlpha(area)
+	pixelsToDips := m.Invert()
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			p := pixelsToDips.Transform(math.Vec2{X: float32(x) + 0.5, Y: float32(y) + 0.5})
+			mask.Pix[mask.PixOffset(x, y)] = uint8(s.AlphaAt(p, r)*255 + 0.5)
+		}
+	}
+	src := image.NewUniform(colorToRGBA(s.Color))
+	draw.DrawMask(ctx.target, area, src, image.ZP, mask, area.Min, draw.Over)
+}
+
+// blur blurs the pixels of the target within area with a Gaussian of
+// standard deviation sigma, in pixels. Pixels outside of area are not read.
+func (ctx *context) blur(area image.Rectangle, sigma float32) {
+	if area.Empty() || sigma <= 0 {
+		return
+	}
+	kernel := gaussianKernel(sigma)
+	n := len(kernel) / 2
+	w, h := area.Dx(), area.Dy()
+	img := ctx.target
+
+	// Horizontal pass into tmp, then vertical pass back into the target.
+	tmp := make([]float32, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum [4]float32
+			for k, weight := range kernel {
+				sx := math.Clamp(x+k-n, 0, w-1)
+				i := img.PixOffset(area.Min.X+sx, area.Min.Y+y)
+				for c := 0; c < 4; c++ {
+					sum[c] += float32(img.Pix[i+c]) * weight
+				}
+			}
+			copy(tmp[(y*w+x)*4:], sum[:])
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum [4]float32
+			for k, weight := range kernel {
+				sy := math.Clamp(y+k-n, 0, h-1)
+				i := (sy*w + x) * 4
+				for c := 0; c < 4; c++ {
+					sum[c] += tmp[i+c] * weight
+				}
+			}
+			i := img.PixOffset(area.Min.X+x, area.Min.Y+y)
+			for c := 0; c < 4; c++ {
+				img.Pix[i+c] = uint8(math.Clampf(sum[c]+0.5, 0, 255))
+			}
+		}
+	}
+}
+
+// gaussianKernel returns the normalized weights of a Gaussian with standard
+// deviation sigma, sampled at whole pixels out to three standard deviations
+// either side of the center.
+func gaussianKernel(sigma float32) []float32 {
+	n := int(math.Ceilf(sigma * 3))
+	kernel := make([]float32, n*2+1)
+	sum := float32(0)
+	for i := range kernel {
+		x := float32(i - n)
+		kernel[i] = math.Expf(-x * x / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"image"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type texture struct {
+	image        image.Image
+	pixelsPerDip float32
+	flipY        bool
+}
+
+func newTexture(img image.Image, pixelsPerDip float32) *texture {
+	t := &texture{
+		image:        img,
+		pixelsPerDip: pixelsPerDip,
+	}
+	return t
+}
+
+// gxui.Texture compliance
+func (t *texture) Image() image.Image {
+	return t.image
+}
+
+func (t *texture) Size() math.Size {
+	return t.SizePixels().ScaleS(1.0 / t.pixelsPerDip)
+}
+
+func (t *texture) SizePixels() math.Size {
+	s := t.image.Bounds().Size()
+	return math.Size{W: s.X, H: s.Y}
+}
+
+func (t *texture) FlipY() bool {
+	return t.flipY
+}
+
+func (t *texture) SetFlipY(flipY bool) {
+	t.flipY = flipY
+}
@@ -0,0 +1,293 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package soft
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/internal/headless"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+var clearColor = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+
+// Viewport is the interface implemented by all viewports created by the
+// software driver.
+type Viewport interface {
+	gxui.Viewport
+
+	// Image returns the most recently rendered frame of the viewport.
+	// The returned image must not be modified.
+	Image() *image.RGBA
+}
+
+type viewport struct {
+	sync.Mutex
+	headless.Events
+
+	driver           *driver
+	fullscreen       bool
+	scaling          float32
+	sizeDipsUnscaled math.Size
+	sizeDips         math.Size
+	sizePixels       math.Size
+	position         math.Point
+	title            string
+	icon             image.Image
+	visible          bool
+	frame            *image.RGBA
+	canvas           *canvas
+	destroyed        bool
+	stats            gxui.RedrawStats
+
+	// Called once when the viewport is destroyed
+	onDestroy func()
+}
+
+func newViewport(driver *driver, width, height int, title string, fullscreen bool) *viewport {
+	v := &viewport{
+		driver:     driver,
+		fullscreen: fullscreen,
+		scaling:    1,
+		title:      title,
+	}
+	v.Events.Init(&driver.Driver)
+	v.sizeDipsUnscaled = math.Size{W: width, H: height}
+	v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / v.scaling)
+	v.sizePixels = v.sizeDipsUnscaled
+	v.frame = v.newFrame()
+	return v
+}
+
+// newFrame returns a new frame image sized to the viewport, cleared to the
+// same color as the gl driver's viewports.
+// Must be called with the viewport locked.
+func (v *viewport) newFrame() *image.RGBA {
+	w, h := v.sizePixels.WH()
+	frame := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(clearColor), image.ZP, draw.Src)
+	return frame
+}
+
+// render rasterizes the current canvas into a new frame.
+// Must be called with the viewport locked.
+func (v *viewport) render() {
+	start := time.Now()
+	ops := map[string]int{}
+	frame := v.newFrame()
+	if v.canvas != nil && v.sizeDips.W > 0 {
+		pixelsPerDip := float32(v.sizePixels.W) / float32(v.sizeDips.W)
+		ctx := newContext(frame, pixelsPerDip)
+		ctx.render(v.canvas)
+		ops = ctx.canvasOps
+	}
+	v.frame = frame
+	v.stats.Add(v.sizePixels, v.sizePixels.Area(), true)
+	v.driver.AddFrame(time.Since(start), ops)
+}
+
+// renderDamaged rasterizes the damaged rectangles of the current canvas,
+// in DIPs, into a copy of the previous frame.
+// Must be called with the viewport locked.
+func (v *viewport) renderDamaged(damage []math.Rect) {
+	w, h := v.sizePixels.WH()
+	if v.canvas == nil || v.sizeDips.W <= 0 || v.frame.Bounds() != image.Rect(0, 0, w, h) {
+		v.render()
+		return
+	}
+	start := time.Now()
+	ops := map[string]int{}
+	frame := image.NewRGBA(v.frame.Bounds())
+	copy(frame.Pix, v.frame.Pix)
+	pixelsPerDip := float32(v.sizePixels.W) / float32(v.sizeDips.W)
+	drawn := 0
+	for _, r := range gxui.DamagePixels(damage, pixelsPerDip, v.sizePixels) {
+		region := frame.SubImage(rectToImage(r)).(*image.RGBA)
+		draw.Draw(region, region.Bounds(), image.NewUniform(clearColor), image.ZP, draw.Src)
+		ctx := newContext(region, pixelsPerDip)
+		ctx.render(v.canvas)
+		for name, n := range ctx.canvasOps {
+			ops[name] += n
+		}
+		drawn += r.Size().Area()
+	}
+	v.frame = frame
+	v.stats.Add(v.sizePixels, drawn, false)
+	v.driver.AddFrame(time.Since(start), ops)
+}
+
+// Viewport compliance
+func (v *viewport) Image() *image.RGBA {
+	v.Lock()
+	defer v.Unlock()
+	return v.frame
+}
+
+// gxui.Viewport compliance
+// These methods are all called on the application routine
+func (v *viewport) SetCanvas(cc gxui.Canvas) {
+	var c *canvas
+	if cc != nil {
+		c = cc.(*canvas)
+	}
+	v.Lock()
+	defer v.Unlock()
+	if v.destroyed {
+		return
+	}
+	v.canvas = c
+	v.render()
+}
+
+func (v *viewport) SetCanvasDamaged(cc gxui.Canvas, damage []math.Rect) {
+	var c *canvas
+	if cc != nil {
+		c = cc.(*canvas)
+	}
+	v.Lock()
+	defer v.Unlock()
+	if v.destroyed {
+		return
+	}
+	if v.canvas == nil || c == nil {
+		v.canvas = c
+		v.render()
+		return
+	}
+	v.canvas = c
+	v.renderDamaged(damage)
+}
+
+func (v *viewport) RedrawStats() gxui.RedrawStats {
+	v.Lock()
+	defer v.Unlock()
+	return v.stats
+}
+
+func (v *viewport) Scale() float32 {
+	v.Lock()
+	defer v.Unlock()
+	return v.scaling
+}
+
+func (v *viewport) SetScale(s float32) {
+	v.Lock()
+	defer v.Unlock()
+	if s != v.scaling {
+		v.scaling = s
+		v.sizeDips = v.sizeDipsUnscaled.ScaleS(1 / s)
+		if !v.destroyed {
+			v.render() // Redraw the last canvas at the new scale
+		}
+		v.ResizeEvent.Fire()
+	}
+}
+
+func (v *viewport) SizeDips() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizeDips
+}
+
+func (v *viewport) SetSizeDips(size math.Size) {
+	v.Lock()
+	defer v.Unlock()
+	v.sizeDips = size
+	v.sizeDipsUnscaled = size.ScaleS(v.scaling)
+	v.sizePixels = v.sizeDipsUnscaled
+	v.render()
+	v.ResizeEvent.Fire()
+}
+
+func (v *viewport) SizePixels() math.Size {
+	v.Lock()
+	defer v.Unlock()
+	return v.sizePixels
+}
+
+func (v *viewport) Title() string {
+	v.Lock()
+	defer v.Unlock()
+	return v.title
+}
+
+func (v *viewport) SetTitle(title string) {
+	v.Lock()
+	defer v.Unlock()
+	v.title = title
+}
+
+func (v *viewport) Icon() image.Image {
+	v.Lock()
+	defer v.Unlock()
+	return v.icon
+}
+
+func (v *viewport) SetIcon(i image.Image) {
+	v.Lock()
+	defer v.Unlock()
+	v.icon = i
+}
+
+func (v *viewport) Position() math.Point {
+	v.Lock()
+	defer v.Unlock()
+	return v.position
+}
+
+func (v *viewport) SetPosition(pos math.Point) {
+	v.Lock()
+	defer v.Unlock()
+	v.position = pos
+}
+
+// The software driver has no mouse, so cursors are ignored.
+func (v *viewport) SetCursor(c gxui.Cursor) {}
+
+// The software driver has no input methods, so composition rectangles are
+// ignored.
+func (v *viewport) SetCompositionRect(r math.Rect) {}
+
+func (v *viewport) Fullscreen() bool {
+	return v.fullscreen
+}
+
+func (v *viewport) Show() {
+	v.Lock()
+	defer v.Unlock()
+	v.visible = true
+}
+
+func (v *viewport) Hide() {
+	v.Lock()
+	defer v.Unlock()
+	v.visible = false
+}
+
+func (v *viewport) Close() {
+	v.CloseEvent.Fire()
+	v.Destroy()
+}
+
+func (v *viewport) Destroy() {
+	v.Lock()
+	if v.destroyed {
+		v.Unlock()
+		return
+	}
+	v.destroyed = true
+	v.canvas = nil
+	onDestroy := v.onDestroy
+	v.Unlock()
+	if onDestroy != nil {
+		onDestroy()
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// FileDropTarget is the optional interface implemented by Controls that
+// accept files dropped onto the window from the operating system, such as
+// from a file manager. The MouseController of the window offers the drop to
+// the controls under the drop point, innermost first, until one consumes it.
+type FileDropTarget interface {
+	// FileDrop is called when the files paths are dropped at the point at,
+	// local to the control. FileDrop returns true to consume the drop, or
+	// false to offer it to the control's ancestors.
+	FileDrop(paths []string, at math.Point) (consume bool)
+}
+
+// FileDropAcceptor is the interface implemented by the standard controls,
+// which become FileDropTargets consuming the drops once they have a handler.
+type FileDropAcceptor interface {
+	FileDropTarget
+
+	// SetFileDropHandler sets the function called with the files dropped on
+	// the control. A nil handler, the default, leaves the drops to the
+	// control's ancestors.
+	SetFileDropHandler(f func(paths []string, at math.Point))
+}
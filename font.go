@@ -20,6 +20,26 @@ type Font interface {
 	Index(rune) truetype.Index
 }
 
+// NamedFont is the interface implemented by Fonts that know the family name
+// of their typeface.
+type NamedFont interface {
+	Font
+
+	// Family returns the font family name, such as "Roboto".
+	Family() string
+}
+
+// TrueTypeFont is the interface implemented by Fonts that can provide the
+// TrueType data they were created from, allowing the typeface to be embedded
+// in exported documents.
+type TrueTypeFont interface {
+	Font
+
+	// TrueTypeData returns the TrueType data passed to Driver.CreateFont.
+	// The returned slice must not be modified.
+	TrueTypeData() []byte
+}
+
 // TextBlock is a sequence of runes to be laid out.
 type TextBlock struct {
 	Runes     []rune
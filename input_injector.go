@@ -0,0 +1,180 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// InputInjector is the interface implemented by Windows that accept
+// synthetic input events. Injected events are dispatched exactly as the
+// events raised by the window's viewport, through the window's
+// MouseController and KeyboardController. The points of mouse events are in
+// window coordinates. Events must be injected on the UI go-routine.
+type InputInjector interface {
+	InjectMouseMove(MouseEvent)
+	InjectMouseEnter(MouseEvent)
+	InjectMouseExit(MouseEvent)
+	InjectMouseDown(MouseEvent)
+	InjectMouseUp(MouseEvent)
+	InjectMouseScroll(MouseEvent)
+	InjectKeyDown(KeyboardEvent)
+	InjectKeyUp(KeyboardEvent)
+	InjectKeyRepeat(KeyboardEvent)
+	InjectKeyStroke(KeyStrokeEvent)
+	InjectComposition(CompositionEvent)
+	InjectFileDrop(paths []string, at math.Point)
+
+	// SetInputClock sets the function returning the time of the input events,
+	// used to detect double-clicks. Players of recorded input use the
+	// recorded times. A nil clock uses time.Now.
+	SetInputClock(clock func() time.Time)
+}
+
+// ClickInterval is the time an InputClock is advanced by for each automated
+// click. It is longer than the double-click time, so consecutive automated
+// clicks are never mistaken for a double-click.
+const ClickInterval = time.Second
+
+// InputClock is an input clock for windows receiving automated input, such as
+// UI tests and automation servers. The clock reports the time of its base
+// clock, advanced by ClickInterval for each call to Click. It is installed
+// with SetInputClock(clock.Now), and must only be used on the UI go-routine.
+type InputClock struct {
+	base func() time.Time
+	skew time.Duration
+}
+
+// CreateInputClock returns an InputClock advancing the times returned by
+// base. A nil base uses time.Now.
+func CreateInputClock(base func() time.Time) *InputClock {
+	if base == nil {
+		base = time.Now
+	}
+	return &InputClock{base: base}
+}
+
+// Now returns the current time of the clock.
+func (c *InputClock) Now() time.Time {
+	return c.base().Add(c.skew)
+}
+
+// Click advances the clock by ClickInterval. Call Click before injecting each
+// automated click, double-click or drag, so that it does not combine with the
+// previous click into a double-click.
+func (c *InputClock) Click() {
+	c.skew += ClickInterval
+}
+
+// ControlToWindow returns the window containing the control c, and the point
+// p, local to c, in the window's coordinates. ControlToWindow panics if c is
+// not in a window.
+func ControlToWindow(c Control, p math.Point) (Window, math.Point) {
+	if !c.Attached() {
+		panic(fmt.Errorf("Control %T is not attached to a window", c))
+	}
+	w := WindowContaining(c)
+	return w, ChildToParent(p, c, w)
+}
+
+// MoveMouseTo injects a mouse move to the point p, local to the control c,
+// into the window containing c.
+func MoveMouseTo(c Control, p math.Point) {
+	w, wp := ControlToWindow(c, p)
+	w.InjectMouseMove(MouseEvent{Point: wp})
+}
+
+// ClickControl injects a left mouse button click at the center of the control
+// c into the window containing c. The mouse is moved over c, then the button
+// is pressed and released. Like real clicks, a click made soon after a
+// previous click with the same button is a double-click.
+func ClickControl(c Control) {
+	ClickControlAt(c, c.Size().Rect().Mid(), MouseButtonLeft, 0)
+}
+
+// ClickControlAt injects a click of button at the point p, local to the
+// control c, with the modifier keys held, into the window containing c.
+func ClickControlAt(c Control, p math.Point, button MouseButton, modifier KeyboardModifier) {
+	w, wp := ControlToWindow(c, p)
+	w.InjectMouseMove(MouseEvent{Point: wp, Modifier: modifier})
+	w.InjectMouseDown(MouseEvent{
+		Point:    wp,
+		Button:   button,
+		State:    MouseState(1 << uint(button)),
+		Modifier: modifier,
+	})
+	w.InjectMouseUp(MouseEvent{Point: wp, Button: button, Modifier: modifier})
+}
+
+// DoubleClickControl injects two left mouse button clicks at the center of
+// the control c into the window containing c.
+func DoubleClickControl(c Control) {
+	ClickControl(c)
+	ClickControl(c)
+}
+
+// DragControl injects a left mouse button press at the point from, a move to
+// the point to, and a release at to, all local to the control c, into the
+// window containing c.
+func DragControl(c Control, from, to math.Point) {
+	w, wf := ControlToWindow(c, from)
+	wt := ChildToParent(to, c, w)
+	state := MouseState(1 << uint(MouseButtonLeft))
+	w.InjectMouseMove(MouseEvent{Point: wf})
+	w.InjectMouseDown(MouseEvent{Point: wf, Button: MouseButtonLeft, State: state})
+	w.InjectMouseMove(MouseEvent{Point: wt, State: state})
+	w.InjectMouseUp(MouseEvent{Point: wt, Button: MouseButtonLeft})
+}
+
+// ScrollControl injects a scroll of the mouse wheel by (x, y) over the center
+// of the control c into the window containing c.
+func ScrollControl(c Control, x, y int) {
+	w, wp := ControlToWindow(c, c.Size().Rect().Mid())
+	w.InjectMouseMove(MouseEvent{Point: wp})
+	w.InjectMouseScroll(MouseEvent{Point: wp, ScrollX: x, ScrollY: y})
+}
+
+// PressKey injects a press and release of key, with the modifier keys held,
+// into the window w. The key is delivered to the focused control.
+func PressKey(w Window, key KeyboardKey, modifier KeyboardModifier) {
+	ev := KeyboardEvent{Key: key, Modifier: modifier}
+	w.InjectKeyDown(ev)
+	w.InjectKeyUp(ev)
+}
+
+// DropFiles injects a drop of the files paths from the operating system at
+// the point p, local to the control c, into the window containing c.
+func DropFiles(c Control, p math.Point, paths []string) {
+	w, wp := ControlToWindow(c, p)
+	w.InjectFileDrop(paths, wp)
+}
+
+// ComposeText injects an input method composition session into the window
+// w, updating the pre-edit text to each of preedit in turn, with the caret
+// at its end, before committing commit. The events are delivered to the
+// focused control.
+func ComposeText(w Window, commit string, preedit ...string) {
+	w.InjectComposition(CompositionEvent{Type: CompositionStart})
+	for _, text := range preedit {
+		w.InjectComposition(CompositionEvent{
+			Type:   CompositionUpdate,
+			Text:   text,
+			Cursor: utf8.RuneCountInString(text),
+		})
+	}
+	w.InjectComposition(CompositionEvent{Type: CompositionEnd, Text: commit})
+}
+
+// TypeText injects a key-stroke for each rune of text into the window w.
+// The key-strokes are delivered to the focused control.
+func TypeText(w Window, text string) {
+	for _, r := range text {
+		w.InjectKeyStroke(KeyStrokeEvent{Character: r})
+	}
+}
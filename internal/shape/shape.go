@@ -0,0 +1,97 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package shape holds the polygon geometry shared by the vector exporters,
+// matching the tessellation performed by the drivers.
+package shape
+
+import (
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// PruneDuplicates returns p with consecutive coincident vertices removed.
+func PruneDuplicates(p gxui.Polygon) gxui.Polygon {
+	pruned := make(gxui.Polygon, 0, len(p))
+	last := gxui.PolygonVertex{}
+	for i, v := range p {
+		if i == 0 || last.Position.Sub(v.Position).Vec2().Len() > 0.001 {
+			pruned = append(pruned, v)
+		}
+		last = v
+	}
+	return pruned
+}
+
+// Corner is the circular arc replacing a rounded polygon vertex.
+type Corner struct {
+	Start, End math.Vec2 // Ends of the arc, on the incoming and outgoing edges
+	Center     math.Vec2
+	Radius     float32
+	Clockwise  bool // True if the arc sweeps clockwise in y-down coordinates
+}
+
+// RoundCorner returns the arc replacing the vertex a, with neighbours b and
+// c, rounded with radius r. The arc starts on the edge ab and ends on the edge
+// ac. If the vertex is not rounded then RoundCorner returns false.
+func RoundCorner(r float32, a, b, c math.Vec2) (Corner, bool) {
+	if r <= 0 {
+		return Corner{}, false
+	}
+	ba, ca := a.Sub(b), a.Sub(c)
+	baLen, caLen := ba.Len(), ca.Len()
+	baDir, caDir := ba.DivS(baLen), ca.DivS(caLen)
+	dp := baDir.Dot(caDir)
+	if dp < -0.99999 || dp > 0.99999 {
+		return Corner{}, false // Straight or degenerate
+	}
+	α := math.Acosf(dp) / 2
+	d := r / math.Sinf(α)
+
+	// The arc centre cannot be futher than half way along ab or ac
+	dMax := math.Minf(baLen, caLen) / (2 * math.Cosf(α))
+	if d > dMax {
+		d = dMax
+		r = d * math.Sinf(α)
+	}
+
+	t := d * math.Cosf(α) // Distance from a to each end of the arc
+	bisector := baDir.Add(caDir).Normalize()
+	return Corner{
+		Start:     a.Sub(baDir.MulS(t)),
+		End:       a.Sub(caDir.MulS(t)),
+		Center:    a.Sub(bisector.MulS(d)),
+		Radius:    r,
+		Clockwise: ba.Cross(c.Sub(a)) > 0,
+	}, true
+}
+
+// OffsetLine returns the points of the open polyline p shifted by half the
+// pen width w to the side of the line's tangent. Stroking the result with a
+// centered pen of width w covers the same pixels as the drivers' DrawLines.
+func OffsetLine(p gxui.Polygon, w float32) []math.Vec2 {
+	p = PruneDuplicates(p)
+	if len(p) < 2 {
+		return nil
+	}
+	offset := func(i, j int) math.Vec2 {
+		dir := p[j].Position.Sub(p[i].Position).Vec2().Normalize()
+		return dir.Tangent()
+	}
+	points := make([]math.Vec2, len(p))
+	for i, v := range p {
+		var n math.Vec2
+		switch i {
+		case 0:
+			n = offset(0, 1)
+		case len(p) - 1:
+			n = offset(i-1, i)
+		default:
+			n = offset(i-1, i).Add(offset(i, i+1)).Normalize()
+		}
+		points[i] = v.Position.Vec2().Add(n.MulS(w / 2))
+	}
+	return points
+}
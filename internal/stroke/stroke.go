@@ -0,0 +1,171 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package stroke breaks the strokes tessellated by the drivers into dashes
+// and adds line caps.
+package stroke
+
+import (
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// EdgePair is a pair of vertices on the outer and inner side of a stroke.
+// The drivers tessellate a stroke as a strip of edge pairs.
+type EdgePair struct {
+	Outer, Inner math.Vec2
+}
+
+func (e EdgePair) center() math.Vec2 {
+	return e.Outer.Add(e.Inner).MulS(0.5)
+}
+
+func lerpPair(a, b EdgePair, s float32) EdgePair {
+	return EdgePair{
+		Outer: a.Outer.Add(b.Outer.Sub(a.Outer).MulS(s)),
+		Inner: a.Inner.Add(b.Inner.Sub(a.Inner).MulS(s)),
+	}
+}
+
+// Dash splits the stroke strip into a strip for each dash of the pattern.
+// Distances are measured along the center line of the stroke, starting
+// offset DIPs into the pattern. If the pattern has no length, the strip is
+// returned unbroken.
+func Dash(strip []EdgePair, dashes gxui.DashPattern, offset float32) [][]EdgePair {
+	period := dashes.Length()
+	if period <= 0 || len(strip) < 2 {
+		return [][]EdgePair{strip}
+	}
+	length := func(i int) float32 {
+		if l := dashes[i%len(dashes)]; l > 0 {
+			return l
+		}
+		return 0
+	}
+
+	// Find the position in the pattern at the start of the strip.
+	offset -= period * float32(int(offset/period))
+	if offset < 0 {
+		offset += period
+	}
+	i := 0
+	for offset >= length(i) && offset > 0 {
+		offset -= length(i)
+		i++
+	}
+	remaining := length(i) - offset
+
+	strips := [][]EdgePair{}
+	var dash []EdgePair
+	if i%2 == 0 {
+		dash = []EdgePair{strip[0]}
+	}
+	for k := 0; k+1 < len(strip); k++ {
+		a, b := strip[k], strip[k+1]
+		l := b.center().Sub(a.center()).Len()
+		t := float32(0)
+		for l-t > remaining {
+			t += remaining
+			p := lerpPair(a, b, t/l)
+			if i%2 == 0 {
+				strips = append(strips, append(dash, p))
+				dash = nil
+			} else {
+				dash = []EdgePair{p}
+			}
+			i++
+			remaining = length(i)
+		}
+		remaining -= l - t
+		if dash != nil {
+			dash = append(dash, b)
+		}
+	}
+	if len(dash) > 1 {
+		strips = append(strips, dash)
+	}
+	return strips
+}
+
+// Cap returns the strip extended at both ends by the line cap c.
+func Cap(strip []EdgePair, c gxui.LineCap) []EdgePair {
+	if c == gxui.ButtCap || len(strip) == 0 {
+		return strip
+	}
+	first, last := strip[0], strip[len(strip)-1]
+	start := append(capEnd(first, direction(strip, false).Neg(), c), strip...)
+	end := capEnd(last, direction(strip, true), c)
+	// The end cap pairs are ordered out from the strip.
+	for i, j := 0, len(end)-1; i < j; i, j = i+1, j-1 {
+		end[i], end[j] = end[j], end[i]
+	}
+	return append(start, end...)
+}
+
+// direction returns the unit direction of the strip at its start, or at its
+// end if atEnd is true, perpendicular to the end's edge pair.
+func direction(strip []EdgePair, atEnd bool) math.Vec2 {
+	e, along := strip[0], math.Vec2{}
+	if n := len(strip); atEnd {
+		e = strip[n-1]
+		for i := n - 2; i >= 0 && along.ZeroLength(); i-- {
+			along = e.center().Sub(strip[i].center())
+		}
+	} else {
+		for i := 1; i < n && along.ZeroLength(); i++ {
+			along = strip[i].center().Sub(e.center())
+		}
+	}
+	dir := e.Outer.Sub(e.Inner).Normalize().Tangent()
+	if dir.Dot(along) < 0 {
+		dir = dir.Neg()
+	}
+	return dir
+}
+
+// capEnd returns the edge pairs of the cap c on the edge pair e, extending
+// in the direction dir, ordered from the outermost pair back to e.
+func capEnd(e EdgePair, dir math.Vec2, c gxui.LineCap) []EdgePair {
+	r := e.Outer.Sub(e.Inner).Len() / 2
+	switch c {
+	case gxui.SquareCap:
+		d := dir.MulS(r)
+		return []EdgePair{{e.Outer.Add(d), e.Inner.Add(d)}}
+	case gxui.RoundCap:
+		center := e.center()
+		n := e.Outer.Sub(center).Normalize()
+		steps := 2 + int(r)
+		pairs := make([]EdgePair, 0, steps)
+		for i := steps; i > 0; i-- {
+			φ := math.Pi / 2 * float32(i) / float32(steps)
+			along := dir.MulS(r * math.Sinf(φ))
+			across := n.MulS(r * math.Cosf(φ))
+			pairs = append(pairs, EdgePair{
+				center.Add(along).Add(across),
+				center.Add(along).Sub(across),
+			})
+		}
+		return pairs
+	default:
+		return nil
+	}
+}
+
+// Strips returns the strips of the stroke strip drawn with the dash pattern
+// and line cap of pen. closed should be true if the strip is the outline of
+// a closed polygon, in which case undashed strips are not capped.
+func Strips(strip []EdgePair, pen gxui.Pen, closed bool) [][]EdgePair {
+	if !pen.IsDashed() {
+		if closed {
+			return [][]EdgePair{strip}
+		}
+		return [][]EdgePair{Cap(strip, pen.Cap)}
+	}
+	strips := Dash(strip, *pen.Dashes, pen.DashOffset)
+	for i, s := range strips {
+		strips[i] = Cap(s, pen.Cap)
+	}
+	return strips
+}
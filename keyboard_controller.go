@@ -4,6 +4,10 @@
 
 package gxui
 
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
 type KeyboardController struct {
 	window Window
 }
@@ -16,6 +20,7 @@ func CreateKeyboardController(w Window) *KeyboardController {
 	w.OnKeyUp(c.keyUp)
 	w.OnKeyRepeat(c.keyPress)
 	w.OnKeyStroke(c.keyStroke)
+	w.OnComposition(c.composition)
 	return c
 }
 
@@ -57,3 +62,16 @@ func (c *KeyboardController) keyStroke(ev KeyStrokeEvent) {
 	}
 	c.window.KeyStroke(ev)
 }
+
+func (c *KeyboardController) composition(ev CompositionEvent) {
+	f := Control(c.window.Focus())
+	for f != nil {
+		if composer, ok := f.(Composer); ok && composer.Composition(ev) {
+			r := composer.CompositionRect()
+			r = r.Offset(ChildToParent(math.ZeroPoint, f, c.window))
+			c.window.Viewport().SetCompositionRect(r)
+			return
+		}
+		f, _ = f.Parent().(Control)
+	}
+}
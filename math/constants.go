@@ -19,6 +19,7 @@ var Mat3Ident Mat3 = CreateMat3(1, 0, 0, 0, 1, 0, 0, 0, 1)
 
 const Pi = float32(math.Pi)
 const TwoPi = Pi * 2.0
+const Sqrt2 = float32(math.Sqrt2)
 
 const MaxUint = ^uint(0)
 const MinUint = 0
@@ -131,3 +131,88 @@ func (m Mat3) DivS(s float32) Mat3 {
 		m.Row(2).DivS(s),
 	)
 }
+
+// The 2D affine transforms below operate on row vectors, so the point
+// (x, y) is transformed as [x, y, 1] ⨯ M and transforms are applied left to
+// right by Mul.
+
+// CreateMat3Translate returns the 2D transform that translates by t.
+func CreateMat3Translate(t Vec2) Mat3 {
+	return CreateMat3(
+		1, 0, 0,
+		0, 1, 0,
+		t.X, t.Y, 1,
+	)
+}
+
+// CreateMat3Scale returns the 2D transform that scales by s about the
+// origin.
+func CreateMat3Scale(s Vec2) Mat3 {
+	return CreateMat3(
+		s.X, 0, 0,
+		0, s.Y, 0,
+		0, 0, 1,
+	)
+}
+
+// CreateMat3Rotate returns the 2D transform that rotates by the angle r, in
+// radians, about the origin. With the y-axis pointing down, positive angles
+// rotate clockwise.
+func CreateMat3Rotate(r float32) Mat3 {
+	s, c := Sinf(r), Cosf(r)
+	return CreateMat3(
+		c, s, 0,
+		-s, c, 0,
+		0, 0, 1,
+	)
+}
+
+// Mul returns the matrix product m ⨯ n. As a 2D transform the result applies
+// m then n.
+func (m Mat3) Mul(n Mat3) Mat3 {
+	return CreateMat3FromRows(
+		m.Row(0).MulM(n),
+		m.Row(1).MulM(n),
+		m.Row(2).MulM(n),
+	)
+}
+
+// Transform returns the point v transformed by the 2D affine transform m.
+func (m Mat3) Transform(v Vec2) Vec2 {
+	return Vec2{
+		v.X*m[0] + v.Y*m[3] + m[6],
+		v.X*m[1] + v.Y*m[4] + m[7],
+	}
+}
+
+// TransformRect returns the smallest rectangle holding r transformed by the
+// 2D affine transform m. Corners within a small tolerance of a whole number
+// are rounded to it, so that rotations by multiples of 90 degrees do not grow
+// the rectangle.
+func (m Mat3) TransformRect(r Rect) Rect {
+	const e = 1e-3
+	a, b := m.Transform(r.TL().Vec2()), m.Transform(r.TR().Vec2())
+	c, d := m.Transform(r.BL().Vec2()), m.Transform(r.BR().Vec2())
+	return Rect{
+		Min: Point{
+			X: int(Floorf(Minf(a.X, b.X, c.X, d.X) + e)),
+			Y: int(Floorf(Minf(a.Y, b.Y, c.Y, d.Y) + e)),
+		},
+		Max: Point{
+			X: int(Ceilf(Maxf(a.X, b.X, c.X, d.X) - e)),
+			Y: int(Ceilf(Maxf(a.Y, b.Y, c.Y, d.Y) - e)),
+		},
+	}
+}
+
+// IsTranslation returns true if the 2D affine transform m only translates.
+func (m Mat3) IsTranslation() bool {
+	return m[0] == 1 && m[1] == 0 && m[3] == 0 && m[4] == 1
+}
+
+// IsAxisAligned returns true if the 2D affine transform m maps axis-aligned
+// rectangles to axis-aligned rectangles, as translations, scales and
+// rotations by multiples of 90 degrees do.
+func (m Mat3) IsAxisAligned() bool {
+	return (m[1] == 0 && m[3] == 0) || (m[0] == 0 && m[4] == 0)
+}
@@ -24,3 +24,19 @@ func TestCreateMat3PositionToBarycentric(t *testing.T) {
 	test.AssertEquals(t, Vec3{0.0, 1.0, 1.0}, b.Vec3(1).MulM(m))
 	test.AssertEquals(t, Vec3{0.0, 0.0, 1.0}, c.Vec3(1).MulM(m))
 }
+
+func TestMat3Transforms(t *testing.T) {
+	m := CreateMat3Scale(Vec2{2, 3}).Mul(CreateMat3Translate(Vec2{10, 20}))
+	test.AssertEquals(t, Vec2{12, 23}, m.Transform(Vec2{1, 1}))
+	v := m.Invert().Transform(Vec2{12, 23})
+	test.AssertEquals(t, Point{1, 1}, Point{Round(v.X), Round(v.Y)})
+	test.AssertEquals(t, true, m.IsAxisAligned())
+	test.AssertEquals(t, false, m.IsTranslation())
+
+	r := CreateMat3Rotate(Pi / 2)
+	v = r.Transform(Vec2{1, 0})
+	test.AssertEquals(t, Point{0, 1}, Point{Round(v.X), Round(v.Y)})
+	test.AssertEquals(t, CreateRect(-2, 0, 0, 1), CreateMat3(0, 1, 0, -1, 0, 0, 0, 0, 1).TransformRect(CreateRect(0, 0, 1, 2)))
+	test.AssertEquals(t, true, CreateMat3(0, 1, 0, -1, 0, 0, 0, 0, 1).IsAxisAligned())
+	test.AssertEquals(t, false, CreateMat3Rotate(Pi/4).IsAxisAligned())
+}
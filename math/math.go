@@ -60,6 +60,22 @@ func Sqrtf(v float32) float32 {
 	return float32(math.Sqrt(float64(v)))
 }
 
+func Floorf(v float32) float32 {
+	return float32(math.Floor(float64(v)))
+}
+
+func Ceilf(v float32) float32 {
+	return float32(math.Ceil(float64(v)))
+}
+
+func Expf(v float32) float32 {
+	return float32(math.Exp(float64(v)))
+}
+
+func Erfcf(v float32) float32 {
+	return float32(math.Erfc(float64(v)))
+}
+
 func Powf(v, e float32) float32 {
 	return float32(math.Pow(float64(v), float64(e)))
 }
@@ -28,9 +28,12 @@ type Container struct {
 	parts.DrawPaint
 	parts.InputEventHandler
 	parts.Layoutable
+	parts.MouseCursor
+	parts.FileDropHandler
 	parts.Paddable
 	parts.PaintChildren
 	parts.Parentable
+	parts.Testable
 	parts.Visible
 }
 
@@ -46,5 +49,8 @@ func (c *Container) Init(outer ContainerOuter, theme gxui.Theme) {
 	c.Visible.Init(outer)
 
 	// Interface compliance test
+	_ = gxui.CursorOwner(c)
+	_ = gxui.FileDropAcceptor(c)
+	_ = gxui.Testable(c)
 	_ = gxui.Container(c)
 }
@@ -24,7 +24,10 @@ type Control struct {
 	parts.DrawPaint
 	parts.InputEventHandler
 	parts.Layoutable
+	parts.MouseCursor
+	parts.FileDropHandler
 	parts.Parentable
+	parts.Testable
 	parts.Visible
 }
 
@@ -37,6 +40,9 @@ func (c *Control) Init(outer ControlOuter, theme gxui.Theme) {
 	c.Visible.Init(outer)
 
 	// Interface compliance test
+	_ = gxui.CursorOwner(c)
+	_ = gxui.FileDropAcceptor(c)
+	_ = gxui.Testable(c)
 	_ = gxui.Control(c)
 }
 
@@ -23,6 +23,7 @@ type BubbleOverlay struct {
 	arrowWidth  int
 	brush       gxui.Brush
 	pen         gxui.Pen
+	shadow      gxui.Shadow
 }
 
 func (o *BubbleOverlay) Init(outer BubbleOverlayOuter, theme gxui.Theme) {
@@ -82,6 +83,20 @@ func (o *BubbleOverlay) SetPen(pen gxui.Pen) {
 	}
 }
 
+// BubbleShadow returns the shadow cast by the bubble.
+func (o *BubbleOverlay) BubbleShadow() gxui.Shadow {
+	return o.shadow
+}
+
+// SetBubbleShadow sets the shadow cast by the bubble. The overlay covers its
+// parent, so the shadow is drawn around the bubble rather than the overlay.
+func (o *BubbleOverlay) SetBubbleShadow(shadow gxui.Shadow) {
+	if o.shadow != shadow {
+		o.shadow = shadow
+		o.Redraw()
+	}
+}
+
 func (o *BubbleOverlay) Paint(c gxui.Canvas) {
 	if !o.IsVisible() {
 		return
@@ -168,6 +183,9 @@ func (o *BubbleOverlay) Paint(c gxui.Canvas) {
 			}
 			// fmt.Printf("D: %+v\n", p)
 		}
+		if o.shadow.IsVisible() {
+			c.DrawShadow(b, o.shadow)
+		}
 		c.DrawPolygon(p, o.pen, o.brush)
 	}
 	o.PaintChildren.Paint(c)
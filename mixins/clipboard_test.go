@@ -0,0 +1,52 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/testing/uitest"
+)
+
+func TestTextBoxClipboard(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var textBox gxui.TextBox
+	h.Do(func() {
+		textBox = h.Theme.CreateTextBox()
+		h.Window.AddChild(textBox)
+	})
+	clipboard := h.Driver.Clipboard()
+
+	h.Click(textBox)
+	h.Type("hello")
+	h.PressKey(gxui.KeyA, gxui.ModControl)
+	h.PressKey(gxui.KeyC, gxui.ModControl)
+	if formats := clipboard.Formats(); !reflect.DeepEqual(formats, []string{gxui.TextMimeType}) {
+		t.Errorf("Clipboard formats were %v after a copy, expected only %s", formats, gxui.TextMimeType)
+	}
+	if str, _ := h.Driver.GetClipboard(); str != "hello" {
+		t.Errorf("Clipboard text was %q after a copy, expected %q", str, "hello")
+	}
+	h.PressKey(gxui.KeyX, gxui.ModControl)
+	h.AssertText(textBox, "")
+
+	// Pasting uses the text of clipboards holding several formats.
+	clipboard.Set(
+		gxui.ClipboardEntry{MimeType: gxui.HTMLMimeType, Data: []byte("<b>rich</b>")},
+		gxui.ClipboardEntry{MimeType: gxui.TextMimeType, Data: []byte("rich")},
+	)
+	h.PressKey(gxui.KeyV, gxui.ModControl)
+	h.AssertText(textBox, "rich")
+
+	// Clipboards without text paste nothing.
+	clipboard.Set(gxui.ClipboardEntry{MimeType: gxui.PNGMimeType, Data: []byte{0x89, 'P', 'N', 'G'}})
+	h.PressKey(gxui.KeyA, gxui.ModControl)
+	h.PressKey(gxui.KeyV, gxui.ModControl)
+	h.AssertText(textBox, "rich")
+}
@@ -118,15 +118,61 @@ func (l *CodeEditorLine) endOfChar(position math.Point) math.Point {
 	return position.AddX(l.ce.Font().GlyphMaxSize().W / 2)
 }
 
+// compositionOffsets returns the offsets of the runes of the pre-edit text of
+// ev, displayed before the rune col of the line laid out at offsets.
+func (l *CodeEditorLine) compositionOffsets(font gxui.Font, ev gxui.CompositionEvent, col int, runes []rune, offsets []math.Point) []math.Point {
+	x := l.caretWidth - l.offset
+	if col > 0 && len(offsets) > 0 {
+		x = offsets[col-1].X + font.Measure(&gxui.TextBlock{Runes: runes[col-1 : col]}).W
+	}
+	preedit := font.Layout(&gxui.TextBlock{
+		Runes:     []rune(ev.Text),
+		AlignRect: l.Size().Rect(),
+		H:         gxui.AlignLeft,
+		V:         gxui.AlignMiddle,
+	})
+	for i := range preedit {
+		preedit[i].X += x
+	}
+	return preedit
+}
+
+// compositionCaret returns the offset of the caret in the pre-edit text of ev,
+// displayed before the rune col of the line laid out at offsets.
+func (l *CodeEditorLine) compositionCaret(font gxui.Font, ev gxui.CompositionEvent, col int, runes []rune, offsets []math.Point) math.Point {
+	if cursor := len(compositionCursor(ev)); cursor > 0 {
+		return l.endOfChar(l.compositionOffsets(font, ev, col, runes, offsets)[cursor-1])
+	}
+	if col > 0 && len(offsets) > 0 {
+		return l.endOfChar(offsets[col-1])
+	}
+	return math.Point{}
+}
+
+func (l *CodeEditorLine) compositionRect() (math.Rect, bool) {
+	ev, col, ok := l.composition()
+	if !ok {
+		return math.Rect{}, false
+	}
+	font := l.ce.Font()
+	runes := l.ce.Controller().LineRunes(l.lineIndex)
+	offset := l.compositionCaret(font, ev, col, runes, l.offsets(font))
+	x := l.caretWidth + offset.X
+	return math.CreateRect(x, 0, x+l.caretWidth, l.Size().H), true
+}
+
 func (l *CodeEditorLine) PaintEditorCarets(c gxui.Canvas, info CodeEditorLinePaintInfo) {
 	controller := l.textbox.controller
+	ev, col, composing := l.composition()
 	for i, count := 0, controller.SelectionCount(); i < count; i++ {
 		caret := controller.Caret(i)
 		line := controller.LineIndex(caret)
 		if line == l.lineIndex {
 			var offset math.Point
 			start := controller.LineStart(line)
-			if len(info.GlyphOffsets) > 0 && caret > start {
+			if composing && i == 0 {
+				offset = l.compositionCaret(l.ce.Font(), ev, col, info.Runes, info.GlyphOffsets)
+			} else if len(info.GlyphOffsets) > 0 && caret > start {
 				caretOffsetIndex := caret - start - 1
 				offset = l.endOfChar(info.GlyphOffsets[caretOffsetIndex])
 			}
@@ -222,6 +268,8 @@ func (l *CodeEditorLine) Paint(c gxui.Canvas) {
 	start := controller.LineStart(l.lineIndex)
 	end := controller.LineEnd(l.lineIndex)
 
+	ev, col, composing := l.composition()
+	var preedit []math.Point
 	var info CodeEditorLinePaintInfo
 	if start != end {
 		info = CodeEditorLinePaintInfo{
@@ -232,11 +280,24 @@ func (l *CodeEditorLine) Paint(c gxui.Canvas) {
 			LineHeight:   l.Size().H,
 			Font:         font,
 		}
+		if composing {
+			// The pre-edit text is laid out inline, before the rune at col.
+			preedit = l.compositionOffsets(font, ev, col, info.Runes, info.GlyphOffsets)
+			width := font.Measure(&gxui.TextBlock{Runes: []rune(ev.Text)}).W
+			for i := col; i < len(info.GlyphOffsets); i++ {
+				info.GlyphOffsets[i].X += width
+			}
+		}
 
 		l.outer.PaintBackgroundSpans(c, info)
 		l.outer.PaintEditorSelections(c, info)
 		l.outer.PaintGlyphs(c, info)
 		l.outer.PaintBorders(c, info)
+	} else if composing {
+		preedit = l.compositionOffsets(font, ev, col, nil, nil)
+	}
+	if composing {
+		l.outer.PaintComposition(c, ev, preedit)
 	}
 
 	if l.textbox.HasFocus() {
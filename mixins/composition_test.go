@@ -0,0 +1,137 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/mixins"
+	"github.com/robertt-smg/gxui/testing/uitest"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// paintOps paints the line c onto a recording canvas, returning the runes
+// it draws and the number of lines it draws.
+func paintOps(c interface {
+	Size() math.Size
+	Paint(gxui.Canvas)
+}) (runes string, lines int) {
+	canvas := displaylist.NewCanvas(c.Size())
+	c.Paint(canvas)
+	canvas.Complete()
+	for _, op := range canvas.DisplayList().Ops {
+		switch op := op.(type) {
+		case displaylist.DrawRunes:
+			runes += string(op.Runes)
+		case displaylist.DrawLines:
+			lines++
+		}
+	}
+	return runes, lines
+}
+
+func TestTextBoxComposition(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var textBox gxui.TextBox
+	h.Do(func() {
+		textBox = h.Theme.CreateTextBox()
+		h.Window.AddChild(textBox)
+	})
+	h.Click(textBox)
+	h.Type("ab")
+	h.PressKey(gxui.KeyLeft, 0)
+
+	var viewport fake.Viewport
+	var line *mixins.DefaultTextBoxLine
+	var glyphWidth int
+	h.Do(func() {
+		viewport = h.Window.Viewport().(fake.Viewport)
+		line = textBox.(interface {
+			ItemControl(gxui.AdapterItem) gxui.Control
+		}).ItemControl(0).(*mixins.DefaultTextBoxLine)
+		glyphWidth = textBox.Font().GlyphMaxSize().W
+		viewport.Compose(gxui.CompositionEvent{Type: gxui.CompositionStart})
+	})
+	var start math.Rect
+	h.Do(func() { start = viewport.CompositionRect() })
+
+	// The pre-edit text is drawn inline at the caret, underlined once per
+	// clause, without changing the text of the text box.
+	h.Do(func() {
+		viewport.Compose(gxui.CompositionEvent{
+			Type:       gxui.CompositionUpdate,
+			Text:       "にほん",
+			Cursor:     2,
+			Attributes: []gxui.CompositionAttribute{gxui.CompositionTarget, gxui.CompositionTarget},
+		})
+	})
+	h.AssertText(textBox, "ab")
+	var runes string
+	var lines int
+	var rect math.Rect
+	h.Do(func() {
+		runes, lines = paintOps(line)
+		rect = viewport.CompositionRect()
+	})
+	if runes != "にほんab" || lines != 2 {
+		t.Errorf("Line drew the runes %q and %d underlines, expected %q and 2", runes, lines, "にほんab")
+	}
+	if got, expected := rect.Min.X-start.Min.X, 2*glyphWidth; got != expected || rect.H() != start.H() {
+		t.Errorf("Composition rect moved from %v to %v, expected it to move right by %d", start, rect, expected)
+	}
+
+	h.Do(func() { viewport.Compose(gxui.CompositionEvent{Type: gxui.CompositionEnd, Text: "日本"}) })
+	h.AssertText(textBox, "a日本b")
+	h.Do(func() { runes, lines = paintOps(line) })
+	if runes != "a日本b" || lines != 0 {
+		t.Errorf("Line drew the runes %q and %d underlines, expected %q and none", runes, lines, "a日本b")
+	}
+
+	// Cancelled sessions leave the text unchanged.
+	h.Compose("", "x")
+	h.AssertText(textBox, "a日本b")
+
+	// Composed text replaces the selection.
+	h.PressKey(gxui.KeyA, gxui.ModControl)
+	h.Compose("語", "ご")
+	h.AssertText(textBox, "語")
+}
+
+func TestCodeEditorComposition(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var editor gxui.CodeEditor
+	h.Do(func() {
+		editor = h.Theme.CreateCodeEditor()
+		editor.SetText("x")
+		h.Window.AddChild(editor)
+	})
+	h.Click(editor)
+	h.PressKey(gxui.KeyEnd, 0)
+
+	var runes string
+	var lines int
+	h.Do(func() {
+		line := gxui.FindControl(editor.(gxui.Parent), func(c gxui.Control) bool {
+			_, ok := c.(*mixins.CodeEditorLine)
+			return ok
+		}).(*mixins.CodeEditorLine)
+		h.Window.InjectComposition(gxui.CompositionEvent{Type: gxui.CompositionStart})
+		h.Window.InjectComposition(gxui.CompositionEvent{Type: gxui.CompositionUpdate, Text: "せかい", Cursor: 3})
+		runes, lines = paintOps(line)
+		h.Window.InjectComposition(gxui.CompositionEvent{Type: gxui.CompositionEnd, Text: "世界"})
+	})
+	if runes != "xせかい" || lines != 1 {
+		t.Errorf("Line drew the runes %q and %d underlines, expected %q and 1", runes, lines, "xせかい")
+	}
+	h.AssertText(editor, "x世界")
+}
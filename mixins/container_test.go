@@ -0,0 +1,72 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/math"
+	"github.com/robertt-smg/gxui/testing/uitest"
+)
+
+// findTransform returns the first PushTransform op of l or of the canvases it
+// draws.
+func findTransform(l *displaylist.List) (math.Mat3, bool) {
+	for _, op := range l.Ops {
+		switch op := op.(type) {
+		case displaylist.PushTransform:
+			return op.Transform, true
+		case displaylist.DrawCanvas:
+			if m, found := findTransform(op.Canvas); found {
+				return m, true
+			}
+		}
+	}
+	return math.Mat3{}, false
+}
+
+func TestSetChildTransform(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var layout gxui.LinearLayout
+	var button gxui.Button
+	h.Do(func() {
+		layout = h.Theme.CreateLinearLayout()
+		button = h.Theme.CreateButton()
+		button.SetText("Rotated")
+		layout.AddChild(button)
+		h.Window.AddChild(layout)
+	})
+
+	presented := func() (math.Mat3, bool) {
+		var m math.Mat3
+		var found bool
+		h.Do(func() {
+			m, found = findTransform(h.Window.Viewport().(fake.Viewport).DisplayList())
+		})
+		return m, found
+	}
+	if _, found := presented(); found {
+		t.Fatalf("The untransformed button was drawn with a transform")
+	}
+
+	// A transform set after the first paint reaches the screen.
+	m := math.CreateMat3Rotate(math.Pi / 2)
+	h.Do(func() { layout.SetChildTransform(button, &m) })
+	if got, found := presented(); !found {
+		t.Errorf("The transformed button was drawn without a transform")
+	} else if expected := layout.Children().Find(button).Matrix(); got != expected {
+		t.Errorf("The button was drawn with the transform %v, expected %v", got, expected)
+	}
+
+	h.Do(func() { layout.SetChildTransform(button, nil) })
+	if _, found := presented(); found {
+		t.Errorf("The button was drawn with a transform after it was removed")
+	}
+}
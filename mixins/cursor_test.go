@@ -0,0 +1,67 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/testing/uitest"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestCursor(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var textBox gxui.TextBox
+	var label gxui.Label
+	var splitter gxui.SplitterLayout
+	h.Do(func() {
+		textBox = h.Theme.CreateTextBox()
+		label = h.Theme.CreateLabel()
+		label.SetText("Label")
+		splitter = h.Theme.CreateSplitterLayout()
+		splitter.SetOrientation(gxui.Horizontal)
+		splitter.AddChild(textBox)
+		splitter.AddChild(label)
+		h.Window.AddChild(splitter)
+	})
+	var bar gxui.Control
+	h.Do(func() { bar = splitter.Children()[1].Control })
+
+	h.MoveMouse(textBox)
+	h.AssertCursor(gxui.IBeamCursor)
+	h.MoveMouse(label)
+	h.AssertCursor(gxui.ArrowCursor)
+	h.MoveMouse(bar)
+	h.AssertCursor(gxui.ResizeEWCursor)
+
+	// The cursor of the pressed control is kept while dragging over others.
+	state := gxui.MouseState(1 << uint(gxui.MouseButtonLeft))
+	var to math.Point
+	h.Do(func() {
+		from := gxui.ChildToParent(bar.Size().Rect().Mid(), bar, h.Window)
+		to = from.AddX(-20)
+		h.Window.InjectMouseDown(gxui.MouseEvent{Point: from, Button: gxui.MouseButtonLeft, State: state})
+		h.Window.InjectMouseMove(gxui.MouseEvent{Point: to, State: state})
+	})
+	h.AssertCursor(gxui.ResizeEWCursor)
+	h.Do(func() {
+		h.Window.InjectMouseUp(gxui.MouseEvent{Point: to, Button: gxui.MouseButtonLeft})
+	})
+	h.AssertCursor(gxui.ResizeEWCursor) // The bar has followed the mouse
+	h.MoveMouse(textBox)
+	h.AssertCursor(gxui.IBeamCursor)
+
+	h.Do(func() { splitter.SetOrientation(gxui.Vertical) })
+	h.MoveMouse(bar)
+	h.AssertCursor(gxui.ResizeNSCursor)
+
+	h.Do(func() { textBox.(gxui.CursorOwner).SetCursor(gxui.CreateCursor(gxui.WaitCursor)) })
+	h.MoveMouse(textBox)
+	h.AssertCursor(gxui.WaitCursor)
+}
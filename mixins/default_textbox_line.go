@@ -21,6 +21,7 @@ type DefaultTextBoxLineOuter interface {
 	PaintCaret(c gxui.Canvas, top, bottom math.Point)
 	PaintSelections(c gxui.Canvas)
 	PaintSelection(c gxui.Canvas, top, bottom math.Point)
+	PaintComposition(c gxui.Canvas, ev gxui.CompositionEvent, offsets []math.Point)
 }
 
 // DefaultTextBoxLine
@@ -84,8 +85,48 @@ func (t *DefaultTextBoxLine) MeasureRunes(s, e int) math.Size {
 	return size
 }
 
+// composition returns the pre-edit text displayed by the line, and the index
+// of the rune of the line it is displayed before, or false if the line
+// displays no pre-edit text.
+func (t *DefaultTextBoxLine) composition() (ev gxui.CompositionEvent, col int, ok bool) {
+	textbox := t.textbox
+	if !textbox.composing {
+		return ev, 0, false
+	}
+	caret := textbox.controller.FirstCaret()
+	if textbox.controller.LineIndex(caret) != t.lineIndex {
+		return ev, 0, false
+	}
+	return textbox.composition, caret - textbox.controller.LineStart(t.lineIndex), true
+}
+
+// compositionCursor returns the runes of the pre-edit text of ev before the
+// caret.
+func compositionCursor(ev gxui.CompositionEvent) []rune {
+	runes := []rune(ev.Text)
+	return runes[:math.Clamp(ev.Cursor, 0, len(runes))]
+}
+
+func (t *DefaultTextBoxLine) compositionRect() (math.Rect, bool) {
+	ev, col, ok := t.composition()
+	if !ok {
+		return math.Rect{}, false
+	}
+	s := t.textbox.controller.LineStart(t.lineIndex)
+	x := t.caretWidth + t.outer.MeasureRunes(s, s+col).W
+	x += t.textbox.font.Measure(&gxui.TextBlock{Runes: compositionCursor(ev)}).W
+	return math.CreateRect(x, 0, x+t.caretWidth, t.Size().H), true
+}
+
 func (t *DefaultTextBoxLine) PaintText(c gxui.Canvas) {
 	runes := []rune(t.textbox.controller.Line(t.lineIndex))
+	ev, col, composing := t.composition()
+	var preedit []rune
+	if composing {
+		// The pre-edit text is laid out inline, before the rune at col.
+		preedit = []rune(ev.Text)
+		runes = append(runes[:col:col], append(preedit, runes[col:]...)...)
+	}
 	f := t.textbox.font
 	offsets := f.Layout(&gxui.TextBlock{
 		Runes:     runes,
@@ -96,17 +137,32 @@ func (t *DefaultTextBoxLine) PaintText(c gxui.Canvas) {
 	for i, offset := range offsets {
 		offsets[i] = offset.AddX(-t.offset)
 	}
+	if composing {
+		end := col + len(preedit)
+		t.outer.PaintComposition(c, ev, offsets[col:end])
+		runes = append(runes[:col:col], runes[end:]...)
+		offsets = append(offsets[:col:col], offsets[end:]...)
+	}
 	c.DrawRunes(f, runes, offsets, t.textbox.textColor)
 }
 
 func (t *DefaultTextBoxLine) PaintCarets(c gxui.Canvas) {
 	controller := t.textbox.controller
+	ev, col, composing := t.composition()
 	for i, cnt := 0, controller.SelectionCount(); i < cnt; i++ {
 		e := controller.Caret(i)
 		l := controller.LineIndex(e)
 		if l == t.lineIndex {
 			s := controller.LineStart(l)
 			m := t.outer.MeasureRunes(s, e)
+			if composing && e-s >= col {
+				// Carets after the pre-edit text are moved past it.
+				preedit := []rune(ev.Text)
+				if i == 0 {
+					preedit = compositionCursor(ev)
+				}
+				m.W += t.textbox.font.Measure(&gxui.TextBlock{Runes: preedit}).W
+			}
 			top := math.Point{X: t.caretWidth + m.W, Y: 0}
 			bottom := top.Add(math.Point{X: 0, Y: t.Size().H})
 			t.outer.PaintCaret(c, top, bottom)
@@ -182,6 +238,33 @@ func (t *DefaultTextBoxLine) PaintSelection(c gxui.Canvas, top, bottom math.Poin
 	c.DrawRoundedRect(r, 1, 1, 1, 1, gxui.TransparentPen, gxui.Brush{Color: gxui.Gray40})
 }
 
+// PaintComposition draws the pre-edit text of ev with its runes at offsets,
+// underlining each run of runes with the same attribute. The target clause of
+// the conversion is underlined with a thicker line.
+func (t *DefaultTextBoxLine) PaintComposition(c gxui.Canvas, ev gxui.CompositionEvent, offsets []math.Point) {
+	runes := []rune(ev.Text)
+	f := t.textbox.font
+	c.DrawRunes(f, runes, offsets, t.textbox.textColor)
+	y := t.Size().H - 1
+	for s := 0; s < len(runes); {
+		e := s + 1
+		for e < len(runes) && ev.Attribute(e) == ev.Attribute(s) {
+			e++
+		}
+		width := float32(1)
+		if ev.Attribute(s) == gxui.CompositionTarget {
+			width = 2
+		}
+		x0 := offsets[s].X
+		x1 := x0 + f.Measure(&gxui.TextBlock{Runes: runes[s:e]}).W - 1 // Gap between runs
+		c.DrawLines(gxui.Polygon{
+			{Position: math.Point{X: x0, Y: y}},
+			{Position: math.Point{X: x1, Y: y}},
+		}, gxui.CreatePen(width, t.textbox.textColor))
+		s = e
+	}
+}
+
 // TextBoxLine compliance
 func (t *DefaultTextBoxLine) RuneIndexAt(p math.Point) int {
 	font := t.textbox.font
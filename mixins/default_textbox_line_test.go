@@ -0,0 +1,58 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/mixins"
+	"github.com/robertt-smg/gxui/themes/basic"
+	"github.com/robertt-smg/gxui/themes/mock"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestDefaultTextBoxLine(t *testing.T) {
+	run(t, func(theme gxui.Theme) {
+		textBox := theme.CreateTextBox().(*basic.TextBox)
+		textBox.SetMultiline(true)
+		textBox.SetText("hello\nworld")
+		textBox.Attach()
+		defer textBox.Detach()
+		textBox.SetSize(math.Size{W: 200, H: 100})
+
+		// Lines are laid out one glyph high, inside the padding of 3.
+		for i, expected := range []math.Rect{
+			math.CreateRect(3, 3, 197, 3+mock.GlyphHeight),
+			math.CreateRect(3, 3+mock.GlyphHeight, 197, 3+2*mock.GlyphHeight),
+		} {
+			line := textBox.ItemControl(i)
+			if line == nil {
+				t.Fatalf("No control for line %d", i)
+			}
+			if got := textBox.Children().Find(line).Bounds(); got != expected {
+				t.Errorf("Line %d bounds were %v, expected %v", i, got, expected)
+			}
+		}
+
+		line := textBox.ItemControl(1).(*mixins.DefaultTextBoxLine)
+		if got, expected := line.PositionAt(9), (math.Point{X: 3 * mock.GlyphWidth, Y: mock.GlyphHeight}); got != expected {
+			t.Errorf("PositionAt(9) was %v, expected %v", got, expected)
+		}
+		for _, test := range []struct {
+			x, expected int
+		}{
+			{0, 6},
+			{mock.GlyphWidth, 6},
+			{mock.GlyphWidth + 1, 7},
+			{100, 11},
+		} {
+			if got := line.RuneIndexAt(math.Point{X: test.x}); got != test.expected {
+				t.Errorf("RuneIndexAt(%d) was %d, expected %d", test.x, got, test.expected)
+			}
+		}
+	})
+}
@@ -0,0 +1,247 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/math"
+	"github.com/robertt-smg/gxui/testing/uitest"
+)
+
+// startLists starts a harness with two lists side by side, the first holding
+// the items red, green and blue, and the second the items cyan and magenta.
+func startLists(t *testing.T) (*uitest.Harness, gxui.List, gxui.List) {
+	h := uitest.Start(t, 300, 200)
+	var from, to gxui.List
+	h.Do(func() {
+		layout := h.Theme.CreateLinearLayout()
+		layout.SetDirection(gxui.LeftToRight)
+		for i, items := range [][]string{{"red", "green", "blue"}, {"cyan", "magenta"}} {
+			adapter := gxui.CreateDefaultAdapter()
+			adapter.SetItems(items)
+			list := h.Theme.CreateList()
+			list.SetAdapter(adapter)
+			layout.AddChild(list)
+			if i == 0 {
+				from = list
+			} else {
+				to = list
+			}
+		}
+		h.Window.AddChild(layout)
+	})
+	return h, from, to
+}
+
+func TestDragListItem(t *testing.T) {
+	h, from, to := startLists(t)
+	defer h.Close()
+
+	var dropped []gxui.AdapterItem
+	var onto gxui.AdapterItem
+	h.Do(func() {
+		target := to.(gxui.ItemDropTarget)
+		target.SetDropFilter(func(payload gxui.DragPayload, item gxui.AdapterItem) bool {
+			return payload.Type == gxui.ItemDragType && item != nil
+		})
+		target.OnItemDrop(func(ev gxui.DragEvent, item gxui.AdapterItem) {
+			dropped = append(dropped, ev.Payload.Data.(gxui.ItemDrag).Item)
+			onto = item
+			if ev.Source != from {
+				t.Errorf("Drag source was %T, expected the first list", ev.Source)
+			}
+		})
+	})
+
+	var green, magenta gxui.Control
+	h.Do(func() {
+		green = from.ItemControl("green")
+		magenta = to.ItemControl("magenta")
+	})
+	h.Drag(green, magenta)
+	if len(dropped) != 1 || dropped[0] != "green" || onto != "magenta" {
+		t.Errorf("Dropped %v onto %v, expected green onto magenta", dropped, onto)
+	}
+	// The release ending the drag is not a click.
+	h.AssertSelected(from, nil)
+	h.AssertCursor(gxui.ArrowCursor)
+
+	// Drops onto the list itself are refused by the filter.
+	var red gxui.Control
+	h.Do(func() { red = from.ItemControl("red") })
+	h.Drag(red, green)
+	if len(dropped) != 1 {
+		t.Errorf("Dropped %v, expected the refused drop to be ignored", dropped)
+	}
+}
+
+func TestDragWithoutTargetClicks(t *testing.T) {
+	h, from, _ := startLists(t)
+	defer h.Close()
+
+	// A drag released over no DropTarget accepting it falls through to a
+	// click.
+	h.Do(func() {
+		red := from.ItemControl("red")
+		p := red.Size().Rect().Mid()
+		gxui.DragControl(red, p, p.AddX(gxui.DragThreshold+1))
+	})
+	h.AssertSelected(from, "red")
+}
+
+func TestItemDragOntoItselfClicks(t *testing.T) {
+	h, from, _ := startLists(t)
+	defer h.Close()
+
+	var enabled bool
+	drops := 0
+	h.Do(func() {
+		enabled = from.(gxui.ItemDragSource).ItemDragEnabled()
+		target := from.(gxui.ItemDropTarget)
+		target.SetDropFilter(func(gxui.DragPayload, gxui.AdapterItem) bool { return true })
+		target.OnItemDrop(func(gxui.DragEvent, gxui.AdapterItem) { drops++ })
+	})
+	if !enabled {
+		t.Errorf("Item dragging was disabled, expected it to be enabled by default")
+	}
+
+	// A click that jitters past the drag threshold, but stays over the item,
+	// still selects the item, even though the list accepts its own items.
+	h.Do(func() {
+		green := from.ItemControl("green")
+		p := green.Size().Rect().Mid()
+		gxui.DragControl(green, p, p.AddX(gxui.DragThreshold+1))
+	})
+	if drops != 0 {
+		t.Errorf("The item was dropped onto itself %d times, expected 0", drops)
+	}
+	h.AssertSelected(from, "green")
+}
+
+func TestDragScrollBar(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var list gxui.List
+	h.Do(func() {
+		items := make([]string, 50)
+		for i := range items {
+			items[i] = fmt.Sprintf("item %d", i)
+		}
+		adapter := gxui.CreateDefaultAdapter()
+		adapter.SetItems(items)
+		list = h.Theme.CreateList()
+		list.SetAdapter(adapter)
+		h.Window.AddChild(list)
+	})
+
+	bar := h.Find(func(c gxui.Control) bool {
+		_, ok := c.(gxui.ScrollBar)
+		return ok
+	}).(gxui.ScrollBar)
+
+	// Press on the thumb, at the start of the bar, and drag it down.
+	var at gxui.MouseEvent
+	var overlay bool
+	h.Do(func() {
+		p := gxui.ChildToParent(math.Point{X: bar.Size().W / 2, Y: 2}, bar, h.Window)
+		at = gxui.MouseEvent{Point: p, Button: gxui.MouseButtonLeft, State: gxui.MouseState(1 << uint(gxui.MouseButtonLeft))}
+		h.Window.InjectMouseMove(at)
+		h.Window.InjectMouseDown(at)
+		at.Point = p.AddY(30)
+		h.Window.InjectMouseMove(at)
+		for _, child := range h.Window.Children() {
+			if _, ok := child.Control.(gxui.BubbleOverlay); ok {
+				overlay = true
+			}
+		}
+		h.Window.InjectMouseUp(at)
+	})
+	if overlay {
+		t.Errorf("Dragging the scroll bar thumb opened a drag overlay")
+	}
+
+	var from int
+	h.Do(func() { from, _ = bar.ScrollPosition() })
+	if from == 0 {
+		t.Errorf("Dragging the scroll bar thumb did not scroll the list")
+	}
+}
+
+func TestDragCancel(t *testing.T) {
+	h, from, to := startLists(t)
+	defer h.Close()
+
+	drops := 0
+	h.Do(func() {
+		target := to.(gxui.ItemDropTarget)
+		target.SetDropFilter(func(gxui.DragPayload, gxui.AdapterItem) bool { return true })
+		target.OnItemDrop(func(gxui.DragEvent, gxui.AdapterItem) { drops++ })
+	})
+
+	var at gxui.MouseEvent
+	h.Do(func() {
+		red := from.ItemControl("red")
+		p := gxui.ChildToParent(red.Size().Rect().Mid(), red, h.Window)
+		at = gxui.MouseEvent{Point: p, Button: gxui.MouseButtonLeft, State: gxui.MouseState(1 << uint(gxui.MouseButtonLeft))}
+		h.Window.InjectMouseDown(at)
+		at.Point = p.AddX(-10)
+		h.Window.InjectMouseMove(at)
+	})
+	h.AssertCursor(gxui.NotAllowedCursor)
+	h.Do(func() {
+		cyan := to.ItemControl("cyan")
+		at.Point = gxui.ChildToParent(cyan.Size().Rect().Mid(), cyan, h.Window)
+		h.Window.InjectMouseMove(at)
+	})
+	h.AssertCursor(gxui.ArrowCursor)
+
+	h.PressKey(gxui.KeyEscape, 0)
+	h.AssertCursor(gxui.ArrowCursor)
+	h.Do(func() { h.Window.InjectMouseUp(at) })
+	if drops != 0 {
+		t.Errorf("The cancelled drag was dropped %d times, expected 0", drops)
+	}
+	h.AssertSelected(from, nil)
+	h.AssertSelected(to, nil)
+}
+
+func TestDragPanel(t *testing.T) {
+	h := uitest.Start(t, 400, 200)
+	defer h.Close()
+
+	var left, right gxui.PanelHolder
+	var a, b, c gxui.Control
+	h.Do(func() {
+		layout := h.Theme.CreateSplitterLayout()
+		layout.SetOrientation(gxui.Horizontal)
+		left = h.Theme.CreatePanelHolder()
+		right = h.Theme.CreatePanelHolder()
+		a, b, c = h.Theme.CreateLabel(), h.Theme.CreateLabel(), h.Theme.CreateLabel()
+		left.AddPanel(a, "A")
+		left.AddPanel(b, "B")
+		right.AddPanel(c, "C")
+		layout.AddChild(left)
+		layout.AddChild(right)
+		h.Window.AddChild(layout)
+	})
+
+	var tabA, tabC gxui.Control
+	h.Do(func() { tabA, tabC = left.Tab(0), right.Tab(0) })
+	h.Drag(tabA, tabC)
+
+	var leftCount, index int
+	h.Do(func() {
+		leftCount = left.PanelCount()
+		index = right.PanelIndex(a)
+	})
+	if leftCount != 1 || index < 0 {
+		t.Errorf("After the drag the left holder had %d panels and A was at %d on the right, expected 1 and >= 0",
+			leftCount, index)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/testing/uitest"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestFileDrop(t *testing.T) {
+	h := uitest.Start(t, 200, 100)
+	defer h.Close()
+
+	var layout gxui.LinearLayout
+	var label gxui.Label
+	var dropped []string
+	var droppedOn string
+	var droppedAt math.Point
+	h.Do(func() {
+		label = h.Theme.CreateLabel()
+		label.SetText("Drop here")
+		layout = h.Theme.CreateLinearLayout()
+		layout.AddChild(label)
+		h.Window.AddChild(layout)
+		layout.(gxui.FileDropAcceptor).SetFileDropHandler(func(paths []string, at math.Point) {
+			dropped, droppedOn, droppedAt = paths, "layout", at
+		})
+		label.(gxui.FileDropAcceptor).SetFileDropHandler(func(paths []string, at math.Point) {
+			dropped, droppedOn, droppedAt = paths, "label", at
+		})
+	})
+
+	// Drops from the driver are offered to the innermost control first.
+	var at math.Point
+	h.Do(func() {
+		at = gxui.ChildToParent(math.Point{X: 2, Y: 3}, label, h.Window)
+		h.Window.Viewport().(fake.Viewport).DropFiles([]string{"/tmp/a.txt", "/tmp/b.txt"}, at)
+	})
+	if !reflect.DeepEqual(dropped, []string{"/tmp/a.txt", "/tmp/b.txt"}) || droppedOn != "label" || droppedAt != (math.Point{X: 2, Y: 3}) {
+		t.Errorf("Dropped %v on %s at %v, expected two files on the label at (2, 3)", dropped, droppedOn, droppedAt)
+	}
+
+	// Controls without a handler leave the drop to their ancestors.
+	h.Do(func() {
+		label.(gxui.FileDropAcceptor).SetFileDropHandler(nil)
+		gxui.DropFiles(label, math.Point{X: 1, Y: 1}, []string{"/tmp/c.txt"})
+	})
+	if !reflect.DeepEqual(dropped, []string{"/tmp/c.txt"}) || droppedOn != "layout" {
+		t.Errorf("Dropped %v on %s, expected c.txt on the layout", dropped, droppedOn)
+	}
+}
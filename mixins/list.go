@@ -34,6 +34,7 @@ type List struct {
 	base.Container
 	parts.BackgroundBorderPainter
 	parts.Focusable
+	parts.ShadowCaster
 
 	outer ListOuter
 
@@ -55,6 +56,9 @@ type List struct {
 	mousePosition            math.Point
 	itemMouseOver            *gxui.Child
 	onItemClicked            gxui.Event
+	itemDragEnabled          bool
+	dropFilter               func(gxui.DragPayload, gxui.AdapterItem) bool
+	onItemDrop               gxui.Event
 	dataChangedSubscription  gxui.EventSubscription
 	dataReplacedSubscription gxui.EventSubscription
 }
@@ -64,6 +68,7 @@ func (l *List) Init(outer ListOuter, theme gxui.Theme) {
 	l.Container.Init(outer, theme)
 	l.BackgroundBorderPainter.Init(outer)
 	l.Focusable.Init(outer)
+	l.ShadowCaster.Init(outer)
 
 	l.theme = theme
 	l.scrollBar = theme.CreateScrollBar()
@@ -75,11 +80,14 @@ func (l *List) Init(outer ListOuter, theme gxui.Theme) {
 	l.SetOrientation(gxui.Vertical)
 	l.SetBackgroundBrush(gxui.TransparentBrush)
 	l.SetMouseEventTarget(true)
+	l.itemDragEnabled = true
 
 	l.details = make(map[gxui.AdapterItem]itemDetails)
 
 	// Interface compliance test
 	_ = gxui.List(l)
+	_ = gxui.ItemDragSource(l)
+	_ = gxui.ItemDropTarget(l)
 }
 
 func (l *List) UpdateItemMouseOver() {
@@ -562,3 +570,96 @@ func (l *List) OnSelectionChanged(f func(gxui.AdapterItem)) gxui.EventSubscripti
 func (l *List) ChangeHiddenCount(value int) {
 	l.hiddenItemCount += value
 }
+
+// ItemAt returns the item displayed at the point p, or nil if there is no item
+// at p.
+func (l *List) ItemAt(p math.Point) gxui.AdapterItem {
+	for item, details := range l.details {
+		if details.child.Bounds().Contains(p) {
+			return item
+		}
+	}
+	return nil
+}
+
+// itemControlAt returns the item whose control is the topmost child of the
+// list at the point p, or nil if p is over no item or over another child, such
+// as the scroll bar.
+func (l *List) itemControlAt(p math.Point) gxui.AdapterItem {
+	children := l.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		if !child.Control.ContainsPoint(child.ToChild(p)) {
+			continue
+		}
+		for item, details := range l.details {
+			if details.child == child {
+				return item
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// ItemDragEnabled returns true if the items of the list can be dragged.
+func (l *List) ItemDragEnabled() bool {
+	return l.itemDragEnabled
+}
+
+// SetItemDragEnabled sets whether the items of the list can be dragged, which
+// is the default.
+func (l *List) SetItemDragEnabled(enabled bool) {
+	l.itemDragEnabled = enabled
+}
+
+// gxui.DragSource compliance
+func (l *List) DragStart(ev gxui.MouseEvent) (gxui.DragPayload, gxui.Control, bool) {
+	if !l.itemDragEnabled || l.adapter == nil {
+		return gxui.DragPayload{}, nil, false
+	}
+	item := l.itemControlAt(ev.Point)
+	if item == nil {
+		return gxui.DragPayload{}, nil, false
+	}
+	image := l.adapter.Create(l.theme, l.details[item].index)
+	payload := gxui.DragPayload{
+		Type: gxui.ItemDragType,
+		Data: gxui.ItemDrag{Source: l.outer, Item: item},
+	}
+	return payload, image, true
+}
+
+func (l *List) DragEnd(dropped bool) {}
+
+// gxui.ItemDropTarget compliance
+func (l *List) SetDropFilter(f func(payload gxui.DragPayload, item gxui.AdapterItem) bool) {
+	l.dropFilter = f
+}
+
+func (l *List) OnItemDrop(f func(ev gxui.DragEvent, item gxui.AdapterItem)) gxui.EventSubscription {
+	if l.onItemDrop == nil {
+		l.onItemDrop = gxui.CreateEvent(f)
+	}
+	return l.onItemDrop.Listen(f)
+}
+
+func (l *List) DragEnter(ev gxui.DragEvent) {}
+
+func (l *List) DragOver(ev gxui.DragEvent) bool {
+	item := l.ItemAt(ev.Point)
+	// An item is never dropped onto itself, so that a click that strays past
+	// the drag threshold is still released as a click.
+	if d, ok := ev.Payload.Data.(gxui.ItemDrag); ok && d.Source == gxui.Control(l.outer) && d.Item == item {
+		return false
+	}
+	return l.dropFilter != nil && l.dropFilter(ev.Payload, item)
+}
+
+func (l *List) DragLeave(ev gxui.DragEvent) {}
+
+func (l *List) Drop(ev gxui.DragEvent) {
+	if l.onItemDrop != nil {
+		l.onItemDrop.Fire(ev, l.ItemAt(ev.Point))
+	}
+}
@@ -35,6 +35,7 @@ type PanelHolderOuter interface {
 type PanelEntry struct {
 	Tab                   PanelTab
 	Panel                 gxui.Control
+	Name                  string
 	MouseDownSubscription gxui.EventSubscription
 }
 
@@ -56,6 +57,7 @@ type PanelHolder struct {
 	switchMode       gxui.SwitchMode
 	switchButtonMode gxui.SwitchButtonMode
 	oldsize          math.Size
+	pressed          PanelEntry // The entry of the tab the mouse was pressed over
 }
 
 func insertIndex(holder gxui.PanelHolder, at math.Point) int {
@@ -80,30 +82,6 @@ func insertIndex(holder gxui.PanelHolder, at math.Point) int {
 	return bestIndex
 }
 
-func beginTabDragging(holder gxui.PanelHolder, panel gxui.Control, name string, window gxui.Window) {
-	var mms, mos gxui.EventSubscription
-	mms = window.OnMouseMove(func(ev gxui.MouseEvent) {
-		for _, c := range gxui.TopControlsUnder(ev.WindowPoint, ev.Window) {
-			if over, ok := c.C.(gxui.PanelHolder); ok {
-				insertAt := insertIndex(over, c.P)
-				if over == holder {
-					if insertAt > over.PanelIndex(panel) {
-						insertAt--
-					}
-				}
-				holder.RemovePanel(panel)
-				holder = over
-				holder.AddPanelAt(panel, name, insertAt)
-				holder.Select(insertAt)
-			}
-		}
-	})
-	mos = window.OnMouseUp(func(gxui.MouseEvent) {
-		mms.Unlisten()
-		mos.Unlisten()
-	})
-}
-
 func (p *PanelHolder) Init(outer PanelHolderOuter, theme gxui.Theme) {
 	p.Container.Init(outer, theme)
 
@@ -148,6 +126,8 @@ func (p *PanelHolder) Init(outer PanelHolderOuter, theme gxui.Theme) {
 
 	// Interface compliance test
 	_ = gxui.PanelHolder(p)
+	_ = gxui.DragSource(p)
+	_ = gxui.DropTarget(p)
 }
 
 func (p *PanelHolder) LayoutChildren() {
@@ -198,15 +178,20 @@ func (p *PanelHolder) AddPanelAt(panel gxui.Control, name string, index int) {
 	}
 	tab := p.outer.CreatePanelTab()
 	tab.SetText(name)
+	if c, ok := tab.(gxui.CursorOwner); ok && c.Cursor().Shape == gxui.DefaultCursor {
+		c.SetCursor(gxui.CreateCursor(gxui.HandCursor))
+	}
 	mds := tab.OnMouseDown(func(ev gxui.MouseEvent) {
-		p.Select(p.PanelIndex(panel))
-		beginTabDragging(p.outer, panel, name, ev.Window)
+		index := p.PanelIndex(panel)
+		p.pressed = p.entries[index]
+		p.Select(index)
 	})
 
 	p.entries = append(p.entries, PanelEntry{})
 	copy(p.entries[index+1:], p.entries[index:])
 	p.entries[index] = PanelEntry{
 		Panel:                 panel,
+		Name:                  name,
 		Tab:                   tab,
 		MouseDownSubscription: mds,
 	}
@@ -395,3 +380,50 @@ func (p *PanelHolder) show(index int) {
 		p.update()
 	}
 }
+
+// InputEventHandler overrides
+func (p *PanelHolder) MouseDown(ev gxui.MouseEvent) {
+	// Called before the MouseDown of the tabs.
+	p.pressed = PanelEntry{}
+	p.Container.MouseDown(ev)
+}
+
+// gxui.DragSource compliance
+func (p *PanelHolder) DragStart(ev gxui.MouseEvent) (gxui.DragPayload, gxui.Control, bool) {
+	e := p.pressed
+	p.pressed = PanelEntry{}
+	if e.Panel == nil {
+		return gxui.DragPayload{}, nil, false
+	}
+	image := p.outer.CreatePanelTab()
+	image.SetText(e.Name)
+	payload := gxui.DragPayload{
+		Type: gxui.PanelDragType,
+		Data: gxui.PanelDrag{Holder: p.outer, Panel: e.Panel, Name: e.Name},
+	}
+	return payload, image, true
+}
+
+func (p *PanelHolder) DragEnd(dropped bool) {}
+
+// gxui.DropTarget compliance
+func (p *PanelHolder) DragEnter(ev gxui.DragEvent) {}
+
+func (p *PanelHolder) DragOver(ev gxui.DragEvent) bool {
+	return ev.Payload.Type == gxui.PanelDragType
+}
+
+func (p *PanelHolder) DragLeave(ev gxui.DragEvent) {}
+
+func (p *PanelHolder) Drop(ev gxui.DragEvent) {
+	d := ev.Payload.Data.(gxui.PanelDrag)
+	insertAt := math.Max(insertIndex(p.outer, ev.Point), 0)
+	if d.Holder == gxui.PanelHolder(p.outer) {
+		if index := p.PanelIndex(d.Panel); insertAt > index {
+			insertAt--
+		}
+	}
+	d.Holder.RemovePanel(d.Panel)
+	p.outer.AddPanelAt(d.Panel, d.Name, insertAt)
+	p.outer.Select(insertAt)
+}
@@ -128,12 +128,38 @@ func (c *Container) RemoveAll() {
 	}
 }
 
+func (c *Container) SetChildTransform(control gxui.Control, m *math.Mat3) {
+	child := c.children.Find(control)
+	if child == nil {
+		panic("Child not part of container")
+	}
+	if m != nil {
+		t := *m // Later changes to *m require another call
+		m = &t
+	}
+	// A transformed child can be drawn outside of the container, so damage
+	// its old and new bounds as well as redrawing the container.
+	c.damage(child.Bounds())
+	child.Transform = m
+	c.damage(child.Bounds())
+	c.outer.Redraw()
+}
+
+// damage damages the rectangle r in the coordinates of the container.
+func (c *Container) damage(r math.Rect) {
+	if d, ok := c.outer.(gxui.Damager); ok {
+		d.Damage(r)
+	} else if control, ok := c.outer.(gxui.Control); ok {
+		gxui.Damage(control, r)
+	}
+}
+
 func (c *Container) ContainsPoint(p math.Point) bool {
 	if !c.outer.IsVisible() || !c.outer.Size().Rect().Contains(p) {
 		return false
 	}
 	for _, v := range c.children {
-		if v.Control.ContainsPoint(p.Sub(v.Offset)) {
+		if v.Control.ContainsPoint(v.ToChild(p)) {
 			return true
 		}
 	}
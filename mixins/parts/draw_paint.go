@@ -27,6 +27,7 @@ type DrawPaint struct {
 	canvas          gxui.Canvas
 	dirty           bool
 	redrawRequested bool
+	damaged         bool // True if the control's bounds have been damaged
 }
 
 func verifyDetach(o DrawPaintOuter) {
@@ -44,12 +45,36 @@ func (d *DrawPaint) Init(outer DrawPaintOuter, theme gxui.Theme) {
 	}
 }
 
+// Redraw marks the control as needing to be repainted, damaging the
+// control's bounds in the window and invalidating the canvases of its
+// ancestors.
 func (d *DrawPaint) Redraw() {
+	d.driver.AssertUIGoroutine()
+	if p := d.outer.Parent(); p != nil {
+		if !d.damaged {
+			d.damaged = true
+			if c, ok := d.outer.(gxui.Control); ok {
+				gxui.Damage(c, d.outer.Size().Rect())
+			}
+		}
+		d.Invalidate()
+	}
+}
+
+// Invalidate marks the control as needing to be repainted without damaging
+// its bounds in the window. Invalidate is called on the ancestors of a
+// redrawn control, as their canvases draw the control's canvas but only the
+// control's bounds have changed.
+func (d *DrawPaint) Invalidate() {
 	d.driver.AssertUIGoroutine()
 	if !d.redrawRequested {
 		if p := d.outer.Parent(); p != nil {
 			d.redrawRequested = true
-			p.Redraw()
+			if i, ok := p.(outer.Invalidator); ok {
+				i.Invalidate()
+			} else {
+				p.Redraw()
+			}
 		}
 	}
 }
@@ -66,6 +91,7 @@ func (d *DrawPaint) Draw() gxui.Canvas {
 	if d.canvas == nil || d.canvas.Size() != s || d.redrawRequested {
 		d.canvas = d.driver.CreateCanvas(s)
 		d.redrawRequested = false
+		d.damaged = false
 		d.outer.Paint(d.canvas)
 		d.canvas.Complete()
 	}
@@ -0,0 +1,26 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parts
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+type FileDropHandler struct {
+	handler func(paths []string, at math.Point)
+}
+
+// gxui.FileDropAcceptor compliance
+func (h *FileDropHandler) SetFileDropHandler(f func(paths []string, at math.Point)) {
+	h.handler = f
+}
+
+func (h *FileDropHandler) FileDrop(paths []string, at math.Point) bool {
+	if h.handler == nil {
+		return false
+	}
+	h.handler(paths, at)
+	return true
+}
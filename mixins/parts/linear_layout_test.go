@@ -0,0 +1,108 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parts_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/themes/mock"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// layoutLabels lays out a linear layout of size, holding a label for each of
+// texts, and returns the bounds of the labels. Labels of the mock theme are
+// mock.GlyphWidth wide for each rune, mock.GlyphHeight high, and have a
+// margin of 3.
+func layoutLabels(setup func(gxui.LinearLayout), size math.Size, texts ...string) []math.Rect {
+	var bounds []math.Rect
+	fake.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+		theme := mock.CreateTheme(driver)
+		layout := theme.CreateLinearLayout()
+		setup(layout)
+		for _, text := range texts {
+			label := theme.CreateLabel()
+			label.SetText(text)
+			layout.AddChild(label)
+		}
+		layout.SetSize(size)
+		for _, c := range layout.Children() {
+			bounds = append(bounds, c.Bounds())
+		}
+	})
+	return bounds
+}
+
+func TestLinearLayout(t *testing.T) {
+	size := math.Size{W: 100, H: 60}
+	for _, test := range []struct {
+		name     string
+		setup    func(gxui.LinearLayout)
+		expected []math.Rect
+	}{
+		{
+			name:  "TopToBottom",
+			setup: func(l gxui.LinearLayout) {},
+			expected: []math.Rect{
+				math.CreateRect(3, 3, 11, 19),
+				math.CreateRect(3, 25, 27, 41),
+			},
+		},
+		{
+			name: "LeftToRight",
+			setup: func(l gxui.LinearLayout) {
+				l.SetDirection(gxui.LeftToRight)
+			},
+			expected: []math.Rect{
+				math.CreateRect(3, 3, 11, 19),
+				math.CreateRect(17, 3, 41, 19),
+			},
+		},
+		{
+			name: "RightToLeft",
+			setup: func(l gxui.LinearLayout) {
+				l.SetDirection(gxui.RightToLeft)
+			},
+			expected: []math.Rect{
+				math.CreateRect(89, 3, 97, 19),
+				math.CreateRect(59, 3, 83, 19),
+			},
+		},
+		{
+			name: "BottomToTop",
+			setup: func(l gxui.LinearLayout) {
+				l.SetDirection(gxui.BottomToTop)
+			},
+			expected: []math.Rect{
+				math.CreateRect(3, 41, 11, 57),
+				math.CreateRect(3, 19, 27, 35),
+			},
+		},
+		{
+			name: "Padded and centered",
+			setup: func(l gxui.LinearLayout) {
+				l.SetPadding(math.Spacing{L: 10, T: 5, R: 10, B: 5})
+				l.SetHorizontalAlignment(gxui.AlignCenter)
+			},
+			expected: []math.Rect{
+				math.CreateRect(46, 8, 54, 24),
+				math.CreateRect(38, 30, 62, 46),
+			},
+		},
+	} {
+		got := layoutLabels(test.setup, size, "a", "bcd")
+		if len(got) != len(test.expected) {
+			t.Fatalf("%s: got %d children, expected %d", test.name, len(got), len(test.expected))
+		}
+		for i := range got {
+			if got[i] != test.expected[i] {
+				t.Errorf("%s: child %d bounds were %v, expected %v", test.name, i, got[i], test.expected[i])
+			}
+		}
+	}
+}
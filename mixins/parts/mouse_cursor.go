@@ -0,0 +1,22 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parts
+
+import (
+	"github.com/robertt-smg/gxui"
+)
+
+type MouseCursor struct {
+	cursor gxui.Cursor
+}
+
+// gxui.CursorOwner compliance
+func (m *MouseCursor) Cursor() gxui.Cursor {
+	return m.cursor
+}
+
+func (m *MouseCursor) SetCursor(c gxui.Cursor) {
+	m.cursor = c
+}
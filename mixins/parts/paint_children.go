@@ -7,6 +7,8 @@ package parts
 import (
 	"github.com/robertt-smg/gxui"
 	"github.com/robertt-smg/gxui/mixins/outer"
+
+	"github.com/robertt-smg/gxui/math"
 )
 
 type PaintChildrenOuter interface {
@@ -25,17 +27,40 @@ func (p *PaintChildren) Init(outer PaintChildrenOuter) {
 
 func (p *PaintChildren) Paint(c gxui.Canvas) {
 	for i, v := range p.outer.Children() {
-		if v.Control.IsVisible() {
+		opacity := v.Control.Opacity()
+		if v.Control.IsVisible() && opacity > 0 {
 			c.Push()
-			c.AddClip(v.Control.Size().Rect().Offset(v.Offset))
-			p.outer.PaintChild(c, v, i)
+			if opacity < 1 {
+				c.PushLayer(opacity, gxui.NormalBlend)
+				p.paintChild(c, v, i)
+				c.PopLayer()
+			} else {
+				p.paintChild(c, v, i)
+			}
 			c.Pop()
 		}
 	}
 }
 
+// paintChild paints the shadow cast by child, if any, followed by the child
+// clipped to its bounds.
+func (p *PaintChildren) paintChild(c gxui.Canvas, child *gxui.Child, idx int) {
+	if s, ok := child.Control.(gxui.ShadowCaster); ok && s.Shadow().IsVisible() {
+		c.DrawShadow(child.Bounds(), s.Shadow())
+	}
+	c.AddClip(child.Bounds())
+	p.outer.PaintChild(c, child, idx)
+}
+
 func (p *PaintChildren) PaintChild(c gxui.Canvas, child *gxui.Child, idx int) {
 	if canvas := child.Control.Draw(); canvas != nil {
-		c.DrawCanvas(canvas, child.Offset)
+		if child.Transform != nil {
+			c.PushTransform(child.Matrix())
+			c.AddClip(child.Control.Size().Rect())
+			c.DrawCanvas(canvas, math.ZeroPoint)
+			c.Pop()
+		} else {
+			c.DrawCanvas(canvas, child.Offset)
+		}
 	}
 }
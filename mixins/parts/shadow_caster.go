@@ -0,0 +1,36 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parts
+
+import (
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/mixins/outer"
+)
+
+type ShadowCasterOuter interface {
+	outer.Parenter
+}
+
+type ShadowCaster struct {
+	outer  ShadowCasterOuter
+	shadow gxui.Shadow
+}
+
+func (s *ShadowCaster) Init(outer ShadowCasterOuter) {
+	s.outer = outer
+}
+
+func (s *ShadowCaster) Shadow() gxui.Shadow {
+	return s.shadow
+}
+
+func (s *ShadowCaster) SetShadow(shadow gxui.Shadow) {
+	if s.shadow != shadow {
+		s.shadow = shadow
+		if p := s.outer.Parent(); p != nil {
+			p.Redraw()
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parts
+
+type Testable struct {
+	testID string
+}
+
+// gxui.Testable compliance
+func (t *Testable) TestID() string {
+	return t.testID
+}
+
+func (t *Testable) SetTestID(id string) {
+	t.testID = id
+}
@@ -6,6 +6,8 @@ package parts
 
 import (
 	"github.com/robertt-smg/gxui/mixins/outer"
+
+	"github.com/robertt-smg/gxui/math"
 )
 
 type VisibleOuter interface {
@@ -16,11 +18,13 @@ type VisibleOuter interface {
 type Visible struct {
 	outer   VisibleOuter
 	visible bool
+	opacity float32
 }
 
 func (v *Visible) Init(outer VisibleOuter) {
 	v.outer = outer
 	v.visible = true
+	v.opacity = 1
 }
 
 func (v *Visible) IsVisible() bool {
@@ -35,3 +39,17 @@ func (v *Visible) SetVisible(visible bool) {
 		}
 	}
 }
+
+func (v *Visible) Opacity() float32 {
+	return v.opacity
+}
+
+func (v *Visible) SetOpacity(opacity float32) {
+	opacity = math.Saturate(opacity)
+	if v.opacity != opacity {
+		v.opacity = opacity
+		if p := v.outer.Parent(); p != nil {
+			p.Redraw()
+		}
+	}
+}
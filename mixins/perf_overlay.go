@@ -0,0 +1,173 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/mixins/base"
+	"github.com/robertt-smg/gxui/mixins/parts"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type PerfOverlayOuter interface {
+	base.ContainerOuter
+}
+
+type PerfOverlay struct {
+	base.Container
+	parts.BackgroundBorderPainter
+
+	outer       PerfOverlayOuter
+	driver      gxui.Driver
+	label       gxui.Label
+	hAlign      gxui.HorizontalAlignment
+	vAlign      gxui.VerticalAlignment
+	interval    time.Duration
+	ticker      *time.Ticker
+	refreshStop chan struct{}
+}
+
+func (o *PerfOverlay) Init(outer PerfOverlayOuter, theme gxui.Theme) {
+	o.Container.Init(outer, theme)
+	o.BackgroundBorderPainter.Init(outer)
+	o.outer = outer
+	o.driver = theme.Driver()
+	o.hAlign = gxui.AlignRight
+	o.vAlign = gxui.AlignTop
+	o.interval = time.Millisecond * 500
+
+	o.label = theme.CreateLabel()
+	o.label.SetMultiline(true)
+	o.label.SetText(o.driver.Stats().String())
+	o.AddChild(o.label)
+
+	o.OnAttach(o.startRefresh)
+	o.OnDetach(o.stopRefresh)
+
+	// Interface compliance test
+	_ = gxui.PerfOverlay(o)
+}
+
+// Label returns the label displaying the statistics.
+func (o *PerfOverlay) Label() gxui.Label {
+	return o.label
+}
+
+func (o *PerfOverlay) startRefresh() {
+	o.stopRefresh()
+	ticker, stop := time.NewTicker(o.interval), make(chan struct{})
+	o.ticker, o.refreshStop = ticker, stop
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if !o.driver.Call(o.refresh) {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (o *PerfOverlay) stopRefresh() {
+	if o.ticker != nil {
+		o.ticker.Stop()
+		close(o.refreshStop)
+		o.ticker, o.refreshStop = nil, nil
+	}
+}
+
+// refresh updates the displayed statistics.
+func (o *PerfOverlay) refresh() {
+	if o.Attached() {
+		o.label.SetText(o.driver.Stats().String())
+	}
+}
+
+func (o *PerfOverlay) LayoutChildren() {
+	bounds := o.outer.Size().Rect().Contract(o.outer.Padding())
+	for _, child := range o.outer.Children() {
+		cm := child.Control.Margin()
+		cs := child.Control.DesiredSize(math.ZeroSize, bounds.Size().Contract(cm).Max(math.ZeroSize))
+		r := cs.Expand(cm).Rect()
+		switch {
+		case o.hAlign.AlignCenter():
+			r = r.OffsetX(bounds.Min.X + (bounds.W()-r.W())/2)
+		case o.hAlign.AlignRight():
+			r = r.OffsetX(bounds.Max.X - r.W())
+		default:
+			r = r.OffsetX(bounds.Min.X)
+		}
+		switch {
+		case o.vAlign.AlignMiddle():
+			r = r.OffsetY(bounds.Min.Y + (bounds.H()-r.H())/2)
+		case o.vAlign.AlignBottom():
+			r = r.OffsetY(bounds.Max.Y - r.H())
+		default:
+			r = r.OffsetY(bounds.Min.Y)
+		}
+		child.Layout(r.Contract(cm))
+	}
+}
+
+func (o *PerfOverlay) DesiredSize(min, max math.Size) math.Size {
+	return max
+}
+
+// ContainsPoint returns false, so that the overlay does not take the mouse
+// events of the controls beneath it.
+func (o *PerfOverlay) ContainsPoint(p math.Point) bool {
+	return false
+}
+
+func (o *PerfOverlay) Paint(c gxui.Canvas) {
+	for _, child := range o.outer.Children() {
+		b := child.Bounds().Expand(child.Control.Margin())
+		o.PaintBackground(c, b)
+		o.PaintBorder(c, b)
+	}
+	o.PaintChildren.Paint(c)
+}
+
+// gxui.PerfOverlay compliance
+func (o *PerfOverlay) HorizontalAlignment() gxui.HorizontalAlignment {
+	return o.hAlign
+}
+
+func (o *PerfOverlay) SetHorizontalAlignment(a gxui.HorizontalAlignment) {
+	if o.hAlign != a {
+		o.hAlign = a
+		o.Relayout()
+	}
+}
+
+func (o *PerfOverlay) VerticalAlignment() gxui.VerticalAlignment {
+	return o.vAlign
+}
+
+func (o *PerfOverlay) SetVerticalAlignment(a gxui.VerticalAlignment) {
+	if o.vAlign != a {
+		o.vAlign = a
+		o.Relayout()
+	}
+}
+
+func (o *PerfOverlay) RefreshInterval() time.Duration {
+	return o.interval
+}
+
+func (o *PerfOverlay) SetRefreshInterval(d time.Duration) {
+	if o.interval != d {
+		o.interval = d
+		if o.ticker != nil {
+			o.startRefresh()
+		}
+	}
+}
@@ -81,6 +81,8 @@ func (s *ScrollBar) Init(outer ScrollBarOuter, theme gxui.Theme) {
 	s.scrollPositionTo = 100
 	s.scrollLimit = 100
 	s.onScroll = gxui.CreateEvent(s.SetScrollPosition)
+	// Scroll bars of text boxes should not inherit the I-beam.
+	s.SetCursor(gxui.CreateCursor(gxui.ArrowCursor))
 
 	// Interface compliance test
 	_ = gxui.ScrollBar(s)
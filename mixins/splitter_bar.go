@@ -63,6 +63,20 @@ func (b *SplitterBar) OnDragEnd(f func(gxui.MouseEvent)) gxui.EventSubscription
 	return b.onDragEnd.Listen(f)
 }
 
+// gxui.CursorOwner overrides
+func (b *SplitterBar) Cursor() gxui.Cursor {
+	if c := b.Control.Cursor(); c.Shape != gxui.DefaultCursor {
+		return c
+	}
+	// The bar is dragged along the major axis of its layout.
+	if o, ok := b.Parent().(interface {
+		Orientation() gxui.Orientation
+	}); ok && o.Orientation().Vertical() {
+		return gxui.CreateCursor(gxui.ResizeNSCursor)
+	}
+	return gxui.CreateCursor(gxui.ResizeEWCursor)
+}
+
 // parts.DrawPaint overrides
 func (b *SplitterBar) Paint(c gxui.Canvas) {
 	r := b.outer.Size().Rect()
@@ -73,6 +87,12 @@ func (b *SplitterBar) Paint(c gxui.Canvas) {
 }
 
 // InputEventHandler overrides
+//
+// The bar follows the mouse itself rather than being a gxui.DragSource: a
+// resize carries no payload and has no drop target, so the DragController
+// would show the not-allowed cursor for the whole resize and turn the release
+// into a click. The resize also starts on the press, not after
+// gxui.DragThreshold.
 func (b *SplitterBar) MouseDown(e gxui.MouseEvent) {
 	b.isDragging = true
 	b.onDragStart.Fire(e)
@@ -0,0 +1,50 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mixins_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/themes/mock"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// run calls f with a mock theme on the UI go-routine of a fake driver.
+func run(t *testing.T, f func(theme gxui.Theme)) {
+	fake.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+		f(mock.CreateTheme(driver))
+	})
+}
+
+func TestTableLayout(t *testing.T) {
+	run(t, func(theme gxui.Theme) {
+		table := theme.CreateTableLayout()
+		table.SetGrid(3, 2)
+		labels := make([]gxui.Label, 3)
+		for i := range labels {
+			labels[i] = theme.CreateLabel()
+		}
+		table.SetChildAt(0, 0, 1, 1, labels[0])
+		table.SetChildAt(1, 0, 2, 1, labels[1])
+		table.SetChildAt(0, 1, 3, 1, labels[2])
+		table.SetSize(math.Size{W: 300, H: 100})
+
+		// Each cell is 100x50, and labels have a margin of 3.
+		expected := []math.Rect{
+			math.CreateRect(3, 3, 97, 47),
+			math.CreateRect(103, 3, 297, 47),
+			math.CreateRect(3, 53, 297, 97),
+		}
+		for i, c := range table.Children() {
+			if got := c.Bounds(); got != expected[i] {
+				t.Errorf("Child %d bounds were %v, expected %v", i, got, expected[i])
+			}
+		}
+	})
+}
@@ -52,6 +52,8 @@ type TextBox struct {
 	adapter           *TextBoxAdapter
 	selectionDragging bool
 	selectionDrag     gxui.TextSelection
+	composing         bool                  // True while an input method composes text
+	composition       gxui.CompositionEvent // The pre-edit text, displayed at the first caret
 	desiredWidth      int
 	startOffset       int
 
@@ -104,9 +106,13 @@ func (t *TextBox) Init(outer TextBoxOuter, driver gxui.Driver, theme gxui.Theme,
 	t.controller = gxui.CreateTextBoxController()
 	t.adapter = &TextBoxAdapter{TextBox: t}
 	t.desiredWidth = 100
+	t.SetCursor(gxui.CreateCursor(gxui.IBeamCursor))
 	t.SetScrollBarEnabled(false) // Defaults to single line
 	t.OnGainedFocus(func() { t.onRedrawLines.Fire() })
-	t.OnLostFocus(func() { t.onRedrawLines.Fire() })
+	t.OnLostFocus(func() {
+		t.composing = false // The pre-edit text is discarded with the focus
+		t.onRedrawLines.Fire()
+	})
 	t.horizScroll = theme.CreateScrollBar()
 	t.horizScrollChild = t.AddChild(t.horizScroll)
 	t.horizScroll.SetOrientation(gxui.Horizontal)
@@ -126,6 +132,7 @@ func (t *TextBox) Init(outer TextBoxOuter, driver gxui.Driver, theme gxui.Theme,
 
 	// Interface compliance test
 	_ = gxui.TextBox(t)
+	_ = gxui.Composer(t)
 }
 
 func (t *TextBox) MaxLineWidth() int {
@@ -535,7 +542,7 @@ func (t *TextBox) KeyPress(ev gxui.KeyboardEvent) (consume bool) {
 				}
 			}
 			str := strings.Join(parts, "\n")
-			t.driver.SetClipboard(str)
+			gxui.SetClipboardText(t.driver.Clipboard(), str)
 
 			if ev.Key == gxui.KeyX {
 				t.controller.ReplaceAll("")
@@ -544,7 +551,10 @@ func (t *TextBox) KeyPress(ev gxui.KeyboardEvent) (consume bool) {
 		}
 	case gxui.KeyV:
 		if ev.Modifier.Control() {
-			str, _ := t.driver.GetClipboard()
+			str, ok := gxui.ClipboardText(t.driver.Clipboard())
+			if !ok {
+				return true
+			}
 			t.controller.ReplaceAll(str)
 			t.controller.Deselect(false)
 			return true
@@ -565,6 +575,56 @@ func (t *TextBox) KeyStroke(ev gxui.KeyStrokeEvent) (consume bool) {
 	return true
 }
 
+// gxui.Composer compliance
+func (t *TextBox) Composition(ev gxui.CompositionEvent) (consume bool) {
+	switch ev.Type {
+	case gxui.CompositionStart, gxui.CompositionUpdate:
+		if !t.composing {
+			// The composed text replaces the selected text.
+			for i, cnt := 0, t.controller.SelectionCount(); i < cnt; i++ {
+				if t.controller.Selection(i).Length() > 0 {
+					t.controller.ReplaceAll("")
+					break
+				}
+			}
+			t.composing = true
+		}
+		t.composition = ev
+	case gxui.CompositionEnd:
+		t.composing = false
+		t.composition = gxui.CompositionEvent{}
+		if ev.Text != "" {
+			t.controller.ReplaceAll(ev.Text)
+			t.controller.Deselect(false)
+		}
+	}
+	t.onRedrawLines.Fire()
+	return true
+}
+
+// compositionLine is the interface implemented by the lines of a TextBox
+// that display the pre-edit text of input methods.
+type compositionLine interface {
+	// compositionRect returns the rectangle of the caret in the pre-edit text
+	// displayed by the line, or false if the line displays no pre-edit text.
+	compositionRect() (r math.Rect, ok bool)
+}
+
+func (t *TextBox) CompositionRect() math.Rect {
+	var r math.Rect
+	line := gxui.FindControl(t.outer, func(c gxui.Control) bool {
+		l, ok := c.(compositionLine)
+		if ok {
+			r, ok = l.compositionRect()
+		}
+		return ok
+	})
+	if line == nil {
+		return t.textRect() // The line of the caret is scrolled out of view
+	}
+	return r.Offset(gxui.ChildToParent(math.ZeroPoint, line, t.outer))
+}
+
 func (t *TextBox) Click(ev gxui.MouseEvent) (consume bool) {
 	t.InputEventHandler.Click(ev)
 	return true
@@ -6,6 +6,7 @@ package mixins
 
 import (
 	"image"
+	"time"
 
 	"github.com/robertt-smg/gxui"
 	"github.com/robertt-smg/gxui/mixins/outer"
@@ -37,10 +38,14 @@ type Window struct {
 	windowedSize       math.Size
 	mouseController    *gxui.MouseController
 	keyboardController *gxui.KeyboardController
+	dragController     *gxui.DragController
 	focusController    *gxui.FocusController
 	layoutPending      bool
 	drawPending        bool
 	updatePending      bool
+	damage             []math.Rect // Regions to redraw with the next frame
+	damageAll          bool        // True if the next frame redraws the whole window
+	childBounds        map[gxui.Control]math.Rect
 	onClose            gxui.Event // Raised by viewport
 	onResize           gxui.Event // Raised by viewport
 	onMouseMove        gxui.Event // Raised by viewport
@@ -53,6 +58,8 @@ type Window struct {
 	onKeyUp            gxui.Event // Raised by viewport
 	onKeyRepeat        gxui.Event // Raised by viewport
 	onKeyStroke        gxui.Event // Raised by viewport
+	onComposition      gxui.Event // Raised by viewport
+	onFileDrop         gxui.Event // Raised by viewport
 
 	onClick       gxui.Event // Raised by MouseController
 	onDoubleClick gxui.Event // Raised by MouseController
@@ -83,7 +90,7 @@ func (w *Window) update() {
 	}
 	if w.drawPending {
 		w.drawPending = false
-		w.Draw()
+		w.draw()
 	}
 }
 
@@ -108,6 +115,8 @@ func (w *Window) Init(outer WindowOuter, driver gxui.Driver, width, height int,
 	w.onKeyUp = gxui.CreateEvent(func(gxui.KeyboardEvent) {})
 	w.onKeyRepeat = gxui.CreateEvent(func(gxui.KeyboardEvent) {})
 	w.onKeyStroke = gxui.CreateEvent(func(gxui.KeyStrokeEvent) {})
+	w.onComposition = gxui.CreateEvent(func(gxui.CompositionEvent) {})
+	w.onFileDrop = gxui.CreateEvent(func([]string, math.Point) {})
 
 	w.onClick = gxui.CreateEvent(func(gxui.MouseEvent) {})
 	w.onDoubleClick = gxui.CreateEvent(func(gxui.MouseEvent) {})
@@ -115,6 +124,7 @@ func (w *Window) Init(outer WindowOuter, driver gxui.Driver, width, height int,
 	w.focusController = gxui.CreateFocusController(outer)
 	w.mouseController = gxui.CreateMouseController(outer, w.focusController)
 	w.keyboardController = gxui.CreateKeyboardController(outer)
+	w.dragController = gxui.CreateDragController(outer, w.mouseController)
 
 	w.onResize.Listen(func() {
 		w.outer.LayoutChildren()
@@ -130,18 +140,54 @@ func (w *Window) Init(outer WindowOuter, driver gxui.Driver, width, height int,
 
 	// Interface compliance test
 	_ = gxui.Window(w)
+	_ = gxui.Damager(w)
 }
 
+// Draw paints the window and redraws the whole viewport.
 func (w *Window) Draw() gxui.Canvas {
-	if s := w.viewport.SizeDips(); s != math.ZeroSize {
-		c := w.driver.CreateCanvas(s)
-		w.outer.Paint(c)
-		c.Complete()
+	w.damageAll = true
+	return w.draw()
+}
+
+// draw paints the window and redraws the damaged regions of the viewport.
+// The canvases of the controls that have not been redrawn are reused.
+func (w *Window) draw() gxui.Canvas {
+	s := w.viewport.SizeDips()
+	if s == math.ZeroSize {
+		return nil
+	}
+	c := w.driver.CreateCanvas(s)
+	w.outer.Paint(c)
+	c.Complete()
+	w.damageMovedChildren()
+	if w.damageAll {
 		w.viewport.SetCanvas(c)
-		return c
 	} else {
-		return nil
+		w.viewport.SetCanvasDamaged(c, w.damage)
 	}
+	w.damage, w.damageAll = nil, false
+	return c
+}
+
+// damageMovedChildren damages the old and new bounds of the window's
+// children that have been added, removed or moved since the last frame.
+// Children of other containers are covered by the container redrawing when
+// it lays them out.
+func (w *Window) damageMovedChildren() {
+	bounds := make(map[gxui.Control]math.Rect)
+	for _, c := range w.outer.Children() {
+		b := c.Bounds()
+		bounds[c.Control] = b
+		if old, found := w.childBounds[c.Control]; !found || old != b {
+			w.addDamage(b)
+		}
+	}
+	for c, old := range w.childBounds {
+		if b, found := bounds[c]; !found || old != b {
+			w.addDamage(old)
+		}
+	}
+	w.childBounds = bounds
 }
 
 func (w *Window) Paint(c gxui.Canvas) {
@@ -341,16 +387,118 @@ func (w *Window) OnKeyStroke(f func(gxui.KeyStrokeEvent)) gxui.EventSubscription
 	return w.onKeyStroke.Listen(f)
 }
 
+func (w *Window) OnComposition(f func(gxui.CompositionEvent)) gxui.EventSubscription {
+	return w.onComposition.Listen(f)
+}
+
+func (w *Window) OnFileDrop(f func(paths []string, at math.Point)) gxui.EventSubscription {
+	return w.onFileDrop.Listen(f)
+}
+
+// gxui.InputInjector compliance
+func (w *Window) InjectMouseMove(ev gxui.MouseEvent) {
+	w.onMouseMove.Fire(ev)
+}
+
+func (w *Window) InjectMouseEnter(ev gxui.MouseEvent) {
+	w.onMouseEnter.Fire(ev)
+}
+
+func (w *Window) InjectMouseExit(ev gxui.MouseEvent) {
+	w.onMouseExit.Fire(ev)
+}
+
+func (w *Window) InjectMouseDown(ev gxui.MouseEvent) {
+	w.onMouseDown.Fire(ev)
+}
+
+func (w *Window) InjectMouseUp(ev gxui.MouseEvent) {
+	w.onMouseUp.Fire(ev)
+}
+
+func (w *Window) InjectMouseScroll(ev gxui.MouseEvent) {
+	w.onMouseScroll.Fire(ev)
+}
+
+func (w *Window) InjectKeyDown(ev gxui.KeyboardEvent) {
+	w.onKeyDown.Fire(ev)
+}
+
+func (w *Window) InjectKeyUp(ev gxui.KeyboardEvent) {
+	w.onKeyUp.Fire(ev)
+}
+
+func (w *Window) InjectKeyRepeat(ev gxui.KeyboardEvent) {
+	w.onKeyRepeat.Fire(ev)
+}
+
+func (w *Window) InjectKeyStroke(ev gxui.KeyStrokeEvent) {
+	w.onKeyStroke.Fire(ev)
+}
+
+func (w *Window) InjectComposition(ev gxui.CompositionEvent) {
+	w.onComposition.Fire(ev)
+}
+
+func (w *Window) InjectFileDrop(paths []string, at math.Point) {
+	w.onFileDrop.Fire(paths, at)
+}
+
+func (w *Window) SetInputClock(clock func() time.Time) {
+	w.mouseController.SetClock(clock)
+}
+
+// SetDragOverlay sets the overlay displaying the images of the drags started
+// in the window.
+func (w *Window) SetDragOverlay(overlay gxui.BubbleOverlay) {
+	w.dragController.SetOverlay(overlay)
+}
+
 func (w *Window) Relayout() {
 	w.layoutPending = true
 	w.requestUpdate()
 }
 
 func (w *Window) Redraw() {
+	w.damageAll = true
 	w.drawPending = true
 	w.requestUpdate()
 }
 
+// Invalidate requests a new frame, redrawing only the damaged regions of the
+// window.
+func (w *Window) Invalidate() {
+	w.drawPending = true
+	w.requestUpdate()
+}
+
+// gxui.Damager compliance
+func (w *Window) Damage(r math.Rect) {
+	if w.addDamage(r) {
+		w.drawPending = true
+		w.requestUpdate()
+	}
+}
+
+// addDamage adds the part of r inside the window to the regions to redraw
+// with the next frame, returning false if r was already damaged.
+func (w *Window) addDamage(r math.Rect) bool {
+	bounds := w.Size().Rect()
+	if r.W() <= 0 || r.H() <= 0 ||
+		r.Max.X <= bounds.Min.X || r.Max.Y <= bounds.Min.Y ||
+		r.Min.X >= bounds.Max.X || r.Min.Y >= bounds.Max.Y {
+		return false // Outside of the window
+	}
+	r = r.Intersect(bounds)
+	for _, d := range w.damage {
+		if d.Contains(r.Min) && d.Contains(r.Max.Sub(math.Point{X: 1, Y: 1})) {
+			return false
+		}
+	}
+	w.damage = append(w.damage, r)
+	return true
+}
+
 func (w *Window) Click(ev gxui.MouseEvent) {
 	w.onClick.Fire(ev)
 }
@@ -388,6 +536,8 @@ func (w *Window) setViewport(v gxui.Viewport) {
 		v.OnKeyUp(func(ev gxui.KeyboardEvent) { w.onKeyUp.Fire(ev) }),
 		v.OnKeyRepeat(func(ev gxui.KeyboardEvent) { w.onKeyRepeat.Fire(ev) }),
 		v.OnKeyStroke(func(ev gxui.KeyStrokeEvent) { w.onKeyStroke.Fire(ev) }),
+		v.OnComposition(func(ev gxui.CompositionEvent) { w.onComposition.Fire(ev) }),
+		v.OnFileDrop(func(paths []string, at math.Point) { w.onFileDrop.Fire(paths, at) }),
 	}
 	w.Relayout()
 }
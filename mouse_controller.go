@@ -6,6 +6,8 @@ package gxui
 
 import (
 	"time"
+
+	"github.com/robertt-smg/gxui/math"
 )
 
 var doubleClickTime = time.Millisecond * 300
@@ -16,6 +18,9 @@ type MouseController struct {
 	lastOver        ControlPointList
 	lastDown        map[MouseButton]ControlPointList
 	lastUpTime      map[MouseButton]time.Time
+	clock           func() time.Time
+	cursor          Cursor
+	drag            *DragController // Set by CreateDragController
 }
 
 func CreateMouseController(w Window, focusController *FocusController) *MouseController {
@@ -24,6 +29,7 @@ func CreateMouseController(w Window, focusController *FocusController) *MouseCon
 		focusController: focusController,
 		lastDown:        make(map[MouseButton]ControlPointList),
 		lastUpTime:      make(map[MouseButton]time.Time),
+		clock:           time.Now,
 	}
 	w.OnMouseMove(c.mouseMove)
 	w.OnMouseEnter(c.mouseMove)
@@ -31,9 +37,19 @@ func CreateMouseController(w Window, focusController *FocusController) *MouseCon
 	w.OnMouseDown(c.mouseDown)
 	w.OnMouseUp(c.mouseUp)
 	w.OnMouseScroll(c.mouseScroll)
+	w.OnFileDrop(c.fileDrop)
 	return c
 }
 
+// SetClock sets the function returning the current time, used to detect
+// double-clicks. A nil clock uses time.Now.
+func (m *MouseController) SetClock(clock func() time.Time) {
+	if clock == nil {
+		clock = time.Now
+	}
+	m.clock = clock
+}
+
 func (m *MouseController) updatePosition(ev MouseEvent) {
 	ValidateHierarchy(m.window)
 
@@ -56,6 +72,33 @@ func (m *MouseController) updatePosition(ev MouseEvent) {
 	}
 
 	m.lastOver = nowOver
+	m.updateCursor()
+}
+
+// updateCursor sets the cursor of the window's viewport to the cursor of the
+// controls under the mouse. While a button is held, the cursor of the
+// controls the button was pressed over is kept, so that dragging keeps the
+// cursor of the dragged control.
+func (m *MouseController) updateCursor() {
+	var c Cursor
+	if m.drag != nil && m.drag.Dragging() {
+		c = m.drag.cursor()
+	} else {
+		over := m.lastOver
+		for _, b := range []MouseButton{MouseButtonLeft, MouseButtonMiddle, MouseButtonRight} {
+			if down, found := m.lastDown[b]; found {
+				over = down
+				break
+			}
+		}
+		c = ResolveCursor(over)
+	}
+	if !c.Equal(m.cursor) {
+		m.cursor = c
+		if v := m.window.Viewport(); v != nil {
+			v.SetCursor(c)
+		}
+	}
 }
 
 func (m *MouseController) mouseMove(ev MouseEvent) {
@@ -65,6 +108,10 @@ func (m *MouseController) mouseMove(ev MouseEvent) {
 		e.Point = cp.P
 		cp.C.MouseMove(e)
 	}
+	if m.drag != nil {
+		m.drag.mouseMove(ev, m.lastDown[MouseButtonLeft])
+		m.updateCursor()
+	}
 }
 
 func (m *MouseController) mouseDown(ev MouseEvent) {
@@ -77,6 +124,10 @@ func (m *MouseController) mouseDown(ev MouseEvent) {
 	}
 
 	m.lastDown[ev.Button] = m.lastOver
+	if m.drag != nil {
+		m.drag.mouseDown(ev)
+	}
+	m.updateCursor()
 }
 
 func (m *MouseController) mouseUp(ev MouseEvent) {
@@ -88,9 +139,16 @@ func (m *MouseController) mouseUp(ev MouseEvent) {
 		cp.C.MouseUp(e)
 	}
 
+	if m.drag != nil && m.drag.mouseUp(ev) {
+		// The release ended a drag, and is not a click.
+		delete(m.lastDown, ev.Button)
+		m.updateCursor()
+		return
+	}
+
 	setFocusCount := m.focusController.SetFocusCount()
 
-	dblClick := time.Since(m.lastUpTime[ev.Button]) < doubleClickTime
+	dblClick := m.clock().Sub(m.lastUpTime[ev.Button]) < doubleClickTime
 	clickConsumed := false
 	for i := len(m.lastDown[ev.Button]) - 1; i >= 0; i-- {
 		cp := m.lastDown[ev.Button][i]
@@ -128,7 +186,8 @@ func (m *MouseController) mouseUp(ev MouseEvent) {
 	}
 
 	delete(m.lastDown, ev.Button)
-	m.lastUpTime[ev.Button] = time.Now()
+	m.lastUpTime[ev.Button] = m.clock()
+	m.updateCursor()
 }
 
 func (m *MouseController) mouseScroll(ev MouseEvent) {
@@ -141,3 +200,14 @@ func (m *MouseController) mouseScroll(ev MouseEvent) {
 		cp.C.MouseScroll(e)
 	}
 }
+
+func (m *MouseController) fileDrop(paths []string, at math.Point) {
+	ValidateHierarchy(m.window)
+
+	over := ControlsUnder(at, m.window)
+	for i := len(over) - 1; i >= 0; i-- {
+		if t, ok := over[i].C.(FileDropTarget); ok && t.FileDrop(paths, over[i].P) {
+			return
+		}
+	}
+}
@@ -0,0 +1,457 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	gomath "math"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/internal/shape"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// content builds the content stream of a page.
+type content struct {
+	buf bytes.Buffer
+	res *resources
+}
+
+// encodePage returns the content stream drawing the display list l.
+// The stream starts by flipping the PDF coordinate space so that, like gxui,
+// the origin is the top-left of the page and y increases downwards.
+func encodePage(l *displaylist.List, res *resources) []byte {
+	c := &content{res: res}
+	c.printf("1 0 0 -1 0 %d cm\n", l.Size.H)
+	c.list(l, l.Size.Rect())
+	return c.buf.Bytes()
+}
+
+func (c *content) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&c.buf, format, args...)
+}
+
+// list writes the ops of l. clip is the visible area in the list's
+// coordinates, which is used to skip ops that would not be seen. This keeps
+// text belonging to other pages out of the document when a page shows part of
+// a larger canvas.
+func (c *content) list(l *displaylist.List, clip math.Rect) {
+	stack := []math.Rect{clip}
+	for i := 0; i < len(l.Ops); i++ {
+		clip := stack[len(stack)-1]
+		switch op := l.Ops[i].(type) {
+		case displaylist.Push:
+			c.printf("q\n")
+			stack = append(stack, clip)
+		case displaylist.PushTransform:
+			m := op.Transform
+			c.printf("q %s %s %s %s %s %s cm\n", number(m[0]), number(m[1]), number(m[3]), number(m[4]), number(m[6]), number(m[7]))
+			stack = append(stack, untransform(m, clip))
+		case displaylist.PushLayer:
+			end := matchingPopLayer(l.Ops, i)
+			if clip.W() > 0 && clip.H() > 0 {
+				c.layer(op, &displaylist.List{Size: l.Size, Ops: l.Ops[i+1 : end]}, clip)
+			}
+			i = end
+		case displaylist.Pop:
+			if len(stack) > 1 {
+				c.printf("Q\n")
+				stack = stack[:len(stack)-1]
+			}
+		case displaylist.AddClip:
+			c.rect(op.Rect)
+			c.printf("W n\n")
+			stack[len(stack)-1] = intersect(clip, op.Rect)
+		case displaylist.Clear:
+			c.paint(op.Color, "rg", func() {
+				c.rect(clip)
+				c.printf("f\n")
+			})
+		case displaylist.DrawCanvas:
+			if !overlaps(op.Canvas.Size.Rect().Offset(op.Offset), clip) {
+				break
+			}
+			c.printf("q 1 0 0 1 %d %d cm\n", op.Offset.X, op.Offset.Y)
+			c.list(op.Canvas, clip.Offset(op.Offset.Neg()))
+			c.printf("Q\n")
+		case displaylist.DrawRect:
+			if overlaps(op.Rect, clip) {
+				c.fill(op.Brush, op.Rect, clip, rectPath(op.Rect))
+			}
+		case displaylist.DrawRoundedRect:
+			if overlaps(op.Rect, clip) {
+				r := op.Rect
+				c.polygon(gxui.Polygon{
+					gxui.PolygonVertex{Position: r.TL(), RoundedRadius: op.TL},
+					gxui.PolygonVertex{Position: r.TR(), RoundedRadius: op.TR},
+					gxui.PolygonVertex{Position: r.BR(), RoundedRadius: op.BR},
+					gxui.PolygonVertex{Position: r.BL(), RoundedRadius: op.BL},
+				}, op.Pen, op.Brush, clip)
+			}
+		case displaylist.DrawPolygon:
+			if overlaps(bounds(op.Polygon, 0), clip) {
+				c.polygon(op.Polygon, op.Pen, op.Brush, clip)
+			}
+		case displaylist.DrawLines:
+			if overlaps(bounds(op.Lines, op.Pen.Width), clip) {
+				c.lines(op.Lines, op.Pen)
+			}
+		case displaylist.DrawRunes:
+			c.runes(op, clip)
+		case displaylist.DrawTexture:
+			if op.Texture.Texture != nil && overlaps(op.Rect, clip) {
+				c.texture(op)
+			}
+		case displaylist.DrawShadow:
+			if op.Shadow.Color.A > 0 && overlaps(op.Shadow.Bounds(op.Rect), clip) {
+				c.shadow(op.Rect, op.Shadow)
+			}
+		}
+	}
+	for i := len(stack) - 1; i > 0; i-- {
+		c.printf("Q\n")
+	}
+}
+
+// layer writes the ops of l, which are between a PushLayer op and its
+// matching PopLayer, as a transparency group clipped to clip.
+func (c *content) layer(op displaylist.PushLayer, l *displaylist.List, clip math.Rect) {
+	group := &content{res: c.res}
+	group.list(l, clip)
+	name := c.res.group(group.buf.Bytes(), clip)
+	gs := c.res.state(graphicsState{alpha: math.Saturate(op.Opacity), blend: op.Blend})
+	c.printf("q /%s gs /%s Do Q\n", gs, name)
+}
+
+// matchingPopLayer returns the index of the PopLayer op matching the
+// PushLayer op at ops[i], or len(ops) if there is none.
+func matchingPopLayer(ops []displaylist.Op, i int) int {
+	depth := 0
+	for ; i < len(ops); i++ {
+		switch ops[i].(type) {
+		case displaylist.PushLayer:
+			depth++
+		case displaylist.PopLayer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(ops)
+}
+
+// paint calls draw inside a saved graphics state with the color col set for
+// the color operator op, which is either "rg" for fills or "RG" for strokes.
+// Nothing is drawn if col is fully transparent.
+func (c *content) paint(col gxui.Color, op string, draw func()) {
+	if col.A <= 0 {
+		return
+	}
+	col = col.Saturate()
+	c.printf("q ")
+	if col.A < 1 {
+		c.printf("/%s gs ", c.res.alpha(col.A))
+	}
+	c.printf("%s %s\n", rgb(col), op)
+	draw()
+	c.printf("Q\n")
+}
+
+func (c *content) rect(r math.Rect) {
+	c.printf("%s", rectPath(r))
+}
+
+func rectPath(r math.Rect) string {
+	return fmt.Sprintf("%d %d %d %d re\n", r.Min.X, r.Min.Y, r.W(), r.H())
+}
+
+// maxPatternTiles is the most tiles drawn to fill a shape with a pattern
+// brush, beyond which the brush's color is used instead.
+const maxPatternTiles = 4096
+
+// fill fills the path, which encloses bounds, with brush. Gradients are
+// written as shadings and patterns as tiled images, both clipped to the path.
+func (c *content) fill(brush gxui.Brush, bounds, clip math.Rect, path string) {
+	switch {
+	case brush.Gradient != nil && bounds.W() > 0 && bounds.H() > 0:
+		sh := c.res.shading(brush.Gradient)
+		c.paint(brush.Color, "rg", func() {
+			// Shading coordinates are in units of the shape's bounds.
+			c.printf("%sW n\n%d 0 0 %d %d %d cm /%s sh\n", path,
+				bounds.W(), bounds.H(), bounds.Min.X, bounds.Min.Y, sh)
+		})
+	case brush.Pattern != nil && brush.Pattern.Texture != nil:
+		t := brush.Pattern.Texture
+		tile := t.Size()
+		area := intersect(bounds, clip)
+		if tile.W > 0 && tile.H > 0 && (area.W()/tile.W+1)*(area.H()/tile.H+1) <= maxPatternTiles {
+			c.paint(gxui.White, "rg", func() {
+				c.printf("%sW n\n", path)
+				// Start at the first tile touching the visible area.
+				x0 := bounds.Min.X + (area.Min.X-bounds.Min.X)/tile.W*tile.W
+				y0 := bounds.Min.Y + (area.Min.Y-bounds.Min.Y)/tile.H*tile.H
+				for y := y0; y < area.Max.Y; y += tile.H {
+					for x := x0; x < area.Max.X; x += tile.W {
+						c.texture(displaylist.DrawTexture{
+							Texture: displaylist.CreateTextureRef(t),
+							Rect:    math.CreateRect(x, y, x+tile.W, y+tile.H),
+						})
+					}
+				}
+			})
+			return
+		}
+		fallthrough
+	default:
+		c.paint(brush.Color, "rg", func() {
+			c.printf("%sf\n", path)
+		})
+	}
+}
+
+func (c *content) polygon(p gxui.Polygon, pen gxui.Pen, brush gxui.Brush, clip math.Rect) {
+	path := polygonPath(p)
+	if path == "" {
+		return
+	}
+	c.fill(brush, p.Bounds(), clip, path)
+	if pen.Width > 0 {
+		// PDF strokes are centered on the path, whereas the drivers draw the
+		// pen inside the shape. Stroking at twice the width, clipped to the
+		// shape, produces the same result.
+		c.paint(pen.Color, "RG", func() {
+			c.printf("%sW n\n%s%s S\n", path, path, stroke(pen, pen.Width*2))
+		})
+	}
+}
+
+func (c *content) lines(p gxui.Polygon, pen gxui.Pen) {
+	if pen.Width <= 0 {
+		return
+	}
+	// The drivers extrude lines to the side of the line's tangent. Offset the
+	// centered PDF stroke by half the pen width to match.
+	points := shape.OffsetLine(p, pen.Width)
+	if len(points) < 2 {
+		return
+	}
+	c.paint(pen.Color, "RG", func() {
+		for i, pt := range points {
+			op := "l"
+			if i == 0 {
+				op = "m"
+			}
+			c.printf("%s %s %s\n", number(pt.X), number(pt.Y), op)
+		}
+		c.printf("%s S\n", stroke(pen, pen.Width))
+	})
+}
+
+// stroke returns the graphics state operators for drawing with pen at the
+// given line width.
+func stroke(pen gxui.Pen, width float32) string {
+	ops := &bytes.Buffer{}
+	fmt.Fprintf(ops, "%s w", number(width))
+	if pen.Cap != gxui.ButtCap {
+		fmt.Fprintf(ops, " %d J", pen.Cap) // PDF's cap styles match gxui.LineCap
+	}
+	if pen.Join != gxui.MiterJoin {
+		fmt.Fprintf(ops, " %d j", pen.Join) // and join styles gxui.LineJoin
+	}
+	if pen.IsDashed() {
+		ops.WriteString(" [")
+		for i, l := range *pen.Dashes {
+			if i > 0 {
+				ops.WriteString(" ")
+			}
+			ops.WriteString(number(math.Maxf(l, 0)))
+		}
+		fmt.Fprintf(ops, "] %s d", number(pen.DashOffset))
+	}
+	return ops.String()
+}
+
+func (c *content) runes(op displaylist.DrawRunes, clip math.Rect) {
+	f := c.res.font(op.Font.Font)
+	size := op.Font.Size
+	scale := float32(size) / glyphSpace
+
+	text := &bytes.Buffer{}
+	inRun := false
+	var runY int
+	var penX float32 // The position at which the next glyph of the run would be placed
+	endRun := func() {
+		if inRun {
+			text.WriteString("] TJ\n")
+			inRun = false
+		}
+	}
+	for i, r := range op.Runes {
+		if r == '\n' || i >= len(op.Points) {
+			endRun()
+			continue
+		}
+		p := op.Points[i]
+		if !overlaps(math.CreateRect(p.X, p.Y-size, p.X+size, p.Y+size/2), clip) {
+			endRun()
+			continue
+		}
+		code, advance := f.encode(r)
+		if !f.hasMetrics() || !inRun || p.Y != runY {
+			endRun()
+			fmt.Fprintf(text, "1 0 0 -1 %d %d Tm [", p.X, p.Y)
+			inRun, runY, penX = true, p.Y, float32(p.X)
+		} else if d := float32(p.X) - penX; d > 0.001 || d < -0.001 {
+			// TJ adjustments are subtracted from the position, in thousandths
+			// of the font size.
+			fmt.Fprintf(text, " %s ", number(-d/scale))
+		}
+		fmt.Fprintf(text, "<%s>", code)
+		penX = float32(p.X) + advance*scale
+	}
+	endRun()
+	if text.Len() == 0 {
+		return
+	}
+	c.paint(op.Color, "rg", func() {
+		c.printf("BT\n/%s %d Tf\n%sET\n", f.name, size, text)
+	})
+}
+
+func (c *content) texture(op displaylist.DrawTexture) {
+	name := c.res.image(op.Texture.Texture)
+	r := op.Rect
+	// Images are drawn into the unit square, with the first row at the top
+	// (y = 1). Map the square onto r, which is flipped by the page transform.
+	if op.Texture.FlipY {
+		c.printf("q %d 0 0 %d %d %d cm /%s Do Q\n", r.W(), r.H(), r.Min.X, r.Min.Y, name)
+	} else {
+		c.printf("q %d 0 0 %d %d %d cm /%s Do Q\n", r.W(), -r.H(), r.Min.X, r.Max.Y, name)
+	}
+}
+
+// shadowScale is the number of image pixels per DIP of rasterized shadows.
+const shadowScale = 2
+
+// shadow writes the shadow s cast by r. PDF has no blur operator, so the
+// shadow is rasterized into an image with a soft mask. The backdrop blur is
+// ignored.
+func (c *content) shadow(r math.Rect, s gxui.Shadow) {
+	b := s.Bounds(r)
+	img := image.NewNRGBA(image.Rect(0, 0, b.W()*shadowScale, b.H()*shadowScale))
+	col := s.Color.Saturate()
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			p := math.Vec2{
+				X: float32(b.Min.X) + (float32(x)+0.5)/shadowScale,
+				Y: float32(b.Min.Y) + (float32(y)+0.5)/shadowScale,
+			}
+			a := s.AlphaAt(p, r) * col.A
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(col.R*255 + 0.5),
+				G: uint8(col.G*255 + 0.5),
+				B: uint8(col.B*255 + 0.5),
+				A: uint8(a*255 + 0.5),
+			})
+		}
+	}
+	name := c.res.raster(img)
+	c.printf("q %d 0 0 %d %d %d cm /%s Do Q\n", b.W(), -b.H(), b.Min.X, b.Max.Y, name)
+}
+
+// polygonPath returns the PDF path construction operators for the closed
+// polygon p. Rounded vertices are written as Bézier approximations of the
+// drivers' arcs.
+func polygonPath(p gxui.Polygon) string {
+	p = shape.PruneDuplicates(p)
+	if len(p) < 3 {
+		return ""
+	}
+	d := &bytes.Buffer{}
+	op := "m"
+	for i, cnt := 0, len(p); i < cnt; i++ {
+		a := p[i].Position.Vec2()
+		b := p[(i+cnt-1)%cnt].Position.Vec2()
+		c := p[(i+1)%cnt].Position.Vec2()
+		if arc, rounded := shape.RoundCorner(p[i].RoundedRadius, a, b, c); !rounded {
+			fmt.Fprintf(d, "%s %s %s\n", number(a.X), number(a.Y), op)
+		} else {
+			fmt.Fprintf(d, "%s %s %s\n", number(arc.Start.X), number(arc.Start.Y), op)
+			arcPath(d, arc)
+		}
+		op = "l"
+	}
+	d.WriteString("h\n")
+	return d.String()
+}
+
+// arcPath writes the arc as cubic Bézier curves of at most 90 degrees each.
+func arcPath(d *bytes.Buffer, arc shape.Corner) {
+	cx, cy, r := float64(arc.Center.X), float64(arc.Center.Y), float64(arc.Radius)
+	a0 := gomath.Atan2(float64(arc.Start.Y)-cy, float64(arc.Start.X)-cx)
+	a1 := gomath.Atan2(float64(arc.End.Y)-cy, float64(arc.End.X)-cx)
+	sweep := a1 - a0
+	for sweep > gomath.Pi {
+		sweep -= 2 * gomath.Pi
+	}
+	for sweep < -gomath.Pi {
+		sweep += 2 * gomath.Pi
+	}
+	n := int(gomath.Ceil(gomath.Abs(sweep) / (gomath.Pi / 2)))
+	if n < 1 {
+		n = 1
+	}
+	step := sweep / float64(n)
+	k := 4.0 / 3.0 * gomath.Tan(step/4) * r
+	point := func(x, y float64) string {
+		return number(float32(x)) + " " + number(float32(y))
+	}
+	for i := 0; i < n; i++ {
+		s, e := a0+step*float64(i), a0+step*float64(i+1)
+		x0, y0 := cx+r*gomath.Cos(s), cy+r*gomath.Sin(s)
+		x3, y3 := cx+r*gomath.Cos(e), cy+r*gomath.Sin(e)
+		fmt.Fprintf(d, "%s %s %s c\n",
+			point(x0-k*gomath.Sin(s), y0+k*gomath.Cos(s)),
+			point(x3+k*gomath.Sin(e), y3-k*gomath.Cos(e)),
+			point(x3, y3))
+	}
+}
+
+// bounds returns the bounding rectangle of the polygon p, expanded by w.
+func bounds(p gxui.Polygon, w float32) math.Rect {
+	return p.Bounds().ExpandI(int(gomath.Ceil(float64(w))))
+}
+
+// overlaps returns true if a and b share any area.
+func overlaps(a, b math.Rect) bool {
+	return a.Min.X < b.Max.X && b.Min.X < a.Max.X && a.Min.Y < b.Max.Y && b.Min.Y < a.Max.Y
+}
+
+// untransform returns the bounding rectangle, in the coordinates before the
+// transform m, of the rectangle r. A singular m produces an empty rectangle.
+func untransform(m math.Mat3, r math.Rect) math.Rect {
+	if m[0]*m[4]-m[1]*m[3] == 0 {
+		return math.Rect{}
+	}
+	return m.Invert().TransformRect(r)
+}
+
+// intersect returns the intersection of a and b. Unlike math.Rect.Intersect,
+// non-overlapping rectangles produce an empty rectangle.
+func intersect(a, b math.Rect) math.Rect {
+	r := math.CreateRect(
+		math.Max(a.Min.X, b.Min.X), math.Max(a.Min.Y, b.Min.Y),
+		math.Min(a.Max.X, b.Max.X), math.Min(a.Max.Y, b.Max.Y))
+	if r.Min.X > r.Max.X || r.Min.Y > r.Max.Y {
+		return math.Rect{}
+	}
+	return r
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/math/fixed"
+)
+
+// font is a font resource of the document. TrueType fonts are embedded as
+// Type0 fonts with 2-byte glyph index codes and a ToUnicode map, so that the
+// text remains searchable and selectable.
+type font struct {
+	name   string
+	ttf    *truetype.Font // nil for the Helvetica fallback
+	data   []byte
+	glyphs map[uint16]rune // Glyphs used, mapped to the rune they represent
+}
+
+// glyphSpace is the number of text space units per em, as used by PDF font
+// widths and TJ adjustments.
+const glyphSpace = 1000
+
+func newTrueTypeFont(name string, data []byte) *font {
+	ttf, err := truetype.Parse(data)
+	if err != nil {
+		return nil
+	}
+	return &font{name: name, ttf: ttf, data: data, glyphs: make(map[uint16]rune)}
+}
+
+func newFallbackFont(name string) *font {
+	return &font{name: name}
+}
+
+// hasMetrics returns true if the font's glyph advances are known, allowing
+// runs of glyphs to be positioned with a single TJ operator.
+func (f *font) hasMetrics() bool {
+	return f.ttf != nil
+}
+
+// encode returns the hex encoded character code for the rune r, and its
+// advance in glyph space units. The advance is zero if the font has no
+// metrics.
+func (f *font) encode(r rune) (code string, advance float32) {
+	if f.ttf == nil {
+		c := byte('?')
+		if r >= ' ' && r < 0x100 {
+			c = byte(r)
+		}
+		return fmt.Sprintf("%.2X", c), 0
+	}
+	idx := uint16(f.ttf.Index(r))
+	if _, found := f.glyphs[idx]; !found {
+		f.glyphs[idx] = r
+	}
+	return fmt.Sprintf("%.4X", idx), f.advance(idx)
+}
+
+func (f *font) advance(idx uint16) float32 {
+	hm := f.ttf.HMetric(fixed.I(glyphSpace), truetype.Index(idx))
+	return float32(hm.AdvanceWidth) / 64
+}
+
+// baseFont returns the PostScript name of the font.
+func (f *font) baseFont() string {
+	n := f.ttf.Name(truetype.NameIDPostscriptName)
+	if n == "" {
+		n = strings.Replace(f.ttf.Name(truetype.NameIDFontFamily), " ", "", -1)
+	}
+	if n == "" {
+		n = f.name
+	}
+	return n
+}
+
+// write writes the font and its descendant objects to file, returning the
+// object number of the font dictionary.
+func (f *font) write(file *file) int {
+	id := file.alloc()
+	if f.ttf == nil {
+		file.object(id, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+		return id
+	}
+
+	baseFont := name(f.baseFont())
+	fontFile, descriptor, cidFont, toUnicode := file.alloc(), file.alloc(), file.alloc(), file.alloc()
+
+	file.stream(fontFile, fmt.Sprintf("/Length1 %d ", len(f.data)), f.data)
+
+	b := f.ttf.Bounds(fixed.I(glyphSpace))
+	minX, minY, maxX, maxY := int(b.Min.X)>>6, int(b.Min.Y)>>6, int(b.Max.X)>>6, int(b.Max.Y)>>6
+	file.object(descriptor, "<< /Type /FontDescriptor /FontName %s /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		baseFont, minX, minY, maxX, maxY, maxY, minY, maxY, fontFile)
+
+	glyphs := f.sortedGlyphs()
+	widths := &bytes.Buffer{}
+	for _, idx := range glyphs {
+		fmt.Fprintf(widths, "%d [%s] ", idx, number(f.advance(idx)))
+	}
+	file.object(cidFont, "<< /Type /Font /Subtype /CIDFontType2 /BaseFont %s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [ %s] >>",
+		baseFont, descriptor, widths)
+
+	file.stream(toUnicode, "", f.toUnicode(glyphs))
+
+	file.object(id, "<< /Type /Font /Subtype /Type0 /BaseFont %s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", baseFont, cidFont, toUnicode)
+	return id
+}
+
+// toUnicode returns the CMap mapping each used glyph back to its rune.
+func (f *font) toUnicode(glyphs []uint16) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("/CIDInit /ProcSet findresource begin\n" +
+		"12 dict begin\n" +
+		"begincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n" +
+		"/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// bfchar sections are limited to 100 entries each.
+	for len(glyphs) > 0 {
+		n := len(glyphs)
+		if n > 100 {
+			n = 100
+		}
+		fmt.Fprintf(b, "%d beginbfchar\n", n)
+		for _, idx := range glyphs[:n] {
+			fmt.Fprintf(b, "<%.4X> <", idx)
+			for _, u := range utf16.Encode([]rune{f.glyphs[idx]}) {
+				fmt.Fprintf(b, "%.4X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+		glyphs = glyphs[n:]
+	}
+	b.WriteString("endcmap\n" +
+		"CMapName currentdict /CMap defineresource pop\n" +
+		"end\n" +
+		"end\n")
+	return b.Bytes()
+}
+
+func (f *font) sortedGlyphs() []uint16 {
+	glyphs := make([]uint16, 0, len(f.glyphs))
+	for idx := range f.glyphs {
+		glyphs = append(glyphs, idx)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
@@ -0,0 +1,204 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pdf writes gxui.Canvases as the pages of a PDF document.
+//
+// Each canvas becomes one page, with one DIP mapping to one PDF point (1/72
+// inch). Shapes are written as vector paths with the same geometry as the
+// drivers, and runes are written as real, selectable text. Fonts that
+// implement gxui.TrueTypeFont, such as those created from the gxfont package,
+// are embedded in the document; other fonts fall back to Helvetica.
+//
+// The package is written in pure Go and does not require a driver, so
+// documents can be produced from the display lists of any recording canvas.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	gomath "math"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+)
+
+// Document is a PDF document under construction.
+type Document struct {
+	// Title, if not empty, is written to the document information dictionary.
+	Title string
+
+	pages []*displaylist.List
+}
+
+// CreateDocument returns a new, empty Document.
+func CreateDocument() *Document {
+	return &Document{}
+}
+
+// AddPage appends a page to the document, drawn from the display list l.
+// The page size, in points, is the size of the list.
+func (d *Document) AddPage(l *displaylist.List) {
+	d.pages = append(d.pages, l)
+}
+
+// AddCanvas appends a page to the document, drawn from the completed canvas
+// c. c must have been created by a Driver whose canvases implement
+// displaylist.Recorder.
+func (d *Document) AddCanvas(c gxui.Canvas) {
+	if !c.IsComplete() {
+		panic(fmt.Errorf("Canvas must be completed before adding as a PDF page"))
+	}
+	d.AddPage(displaylist.FromCanvas(c))
+}
+
+// PageCount returns the number of pages added to the document.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// WriteTo writes the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, errors.New("PDF document has no pages")
+	}
+
+	res := newResources()
+	contents := make([][]byte, len(d.pages))
+	for i, l := range d.pages {
+		contents[i] = encodePage(l, res)
+	}
+
+	f := &file{}
+	catalog, pages, resources := f.alloc(), f.alloc(), f.alloc()
+	pageIDs := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageIDs[i] = f.alloc()
+	}
+	var info int
+	if d.Title != "" {
+		info = f.alloc()
+	}
+
+	f.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	f.object(catalog, "<< /Type /Catalog /Pages %d 0 R >>", pages)
+
+	kids := &bytes.Buffer{}
+	for _, id := range pageIDs {
+		fmt.Fprintf(kids, "%d 0 R ", id)
+	}
+	f.object(pages, "<< /Type /Pages /Count %d /Kids [ %s] >>", len(pageIDs), kids)
+
+	for i, l := range d.pages {
+		content := f.alloc()
+		f.object(pageIDs[i], "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources %d 0 R /Contents %d 0 R >>",
+			pages, l.Size.W, l.Size.H, resources, content)
+		f.stream(content, "", contents[i])
+	}
+
+	f.object(resources, "%s", res.write(f, resources))
+
+	if info != 0 {
+		f.object(info, "<< /Title %s /Producer (gxui) >>", textString(d.Title))
+	}
+
+	xref := f.buf.Len()
+	fmt.Fprintf(&f.buf, "xref\n0 %d\n0000000000 65535 f \n", len(f.offsets)+1)
+	for _, offset := range f.offsets {
+		fmt.Fprintf(&f.buf, "%.10d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&f.buf, "trailer\n<< /Size %d /Root %d 0 R", len(f.offsets)+1, catalog)
+	if info != 0 {
+		fmt.Fprintf(&f.buf, " /Info %d 0 R", info)
+	}
+	fmt.Fprintf(&f.buf, " >>\nstartxref\n%d\n%%%%EOF\n", xref)
+
+	return f.buf.WriteTo(w)
+}
+
+// Write writes the completed canvases as the pages of a PDF document to w.
+func Write(w io.Writer, pages ...gxui.Canvas) error {
+	d := CreateDocument()
+	for _, c := range pages {
+		if !c.IsComplete() {
+			return errors.New("Canvas must be completed before writing as PDF")
+		}
+		d.AddPage(displaylist.FromCanvas(c))
+	}
+	_, err := d.WriteTo(w)
+	return err
+}
+
+// file accumulates the numbered objects of a PDF file.
+type file struct {
+	buf     bytes.Buffer
+	offsets []int // Byte offset of each object, indexed by object number - 1
+}
+
+// alloc reserves and returns a new object number.
+func (f *file) alloc() int {
+	f.offsets = append(f.offsets, 0)
+	return len(f.offsets)
+}
+
+func (f *file) object(id int, format string, args ...interface{}) {
+	f.offsets[id-1] = f.buf.Len()
+	fmt.Fprintf(&f.buf, "%d 0 obj\n", id)
+	fmt.Fprintf(&f.buf, format, args...)
+	f.buf.WriteString("\nendobj\n")
+}
+
+// stream writes a Flate compressed stream object. dict holds any entries to
+// add to the stream dictionary.
+func (f *file) stream(id int, dict string, data []byte) {
+	compressed := &bytes.Buffer{}
+	z := zlib.NewWriter(compressed)
+	z.Write(data)
+	z.Close()
+	f.offsets[id-1] = f.buf.Len()
+	fmt.Fprintf(&f.buf, "%d 0 obj\n<< %s/Length %d /Filter /FlateDecode >>\nstream\n", id, dict, compressed.Len())
+	compressed.WriteTo(&f.buf)
+	f.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// number formats v rounded to 3 decimal places, hiding float32 noise.
+func number(v float32) string {
+	r := gomath.Round(float64(v)*1000) / 1000
+	if r == 0 {
+		r = 0 // Avoid writing -0
+	}
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+// name returns s as a PDF name object, escaping any delimiter, whitespace or
+// non-ASCII bytes.
+func name(s string) string {
+	b := &bytes.Buffer{}
+	b.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c <= ' ' || c >= 0x7f || bytes.IndexByte([]byte("()<>[]{}/%#"), c) >= 0:
+			fmt.Fprintf(b, "#%.2X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// textString returns s as a PDF text string, encoded as UTF-16BE.
+func textString(s string) string {
+	b := &bytes.Buffer{}
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(b, "%.4X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,295 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/gxfont"
+
+	"github.com/robertt-smg/gxui/math"
+	test "github.com/robertt-smg/gxui/testing"
+
+	"github.com/golang/freetype/truetype"
+)
+
+// testFont provides the TrueType data of the default gxfont. Only
+// TrueTypeData is called by the encoder.
+type testFont struct{ gxui.Font }
+
+func (testFont) TrueTypeData() []byte { return gxfont.Default }
+
+type testTexture struct{ img image.Image }
+
+func (t testTexture) Image() image.Image    { return t.img }
+func (t testTexture) Size() math.Size       { return t.SizePixels() }
+func (t testTexture) SizePixels() math.Size { return math.Size{W: 2, H: 2} }
+func (t testTexture) FlipY() bool           { return false }
+func (t testTexture) SetFlipY(bool)         {}
+
+func runes(s string, x, y, advance int) displaylist.DrawRunes {
+	op := displaylist.DrawRunes{
+		Font:  displaylist.FontRef{Family: "Roboto", Size: 10, Font: testFont{}},
+		Runes: []rune(s),
+		Color: gxui.Black,
+	}
+	for i := range op.Runes {
+		op.Points = append(op.Points, math.Point{X: x + i*advance, Y: y})
+	}
+	return op
+}
+
+func writeTestDocument(t *testing.T, pages ...*displaylist.List) string {
+	d := CreateDocument()
+	d.Title = "Report"
+	for _, p := range pages {
+		d.AddPage(p)
+	}
+	buf := &bytes.Buffer{}
+	if _, err := d.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// contentStreams returns the decompressed contents of every stream in doc.
+func contentStreams(t *testing.T, doc string) string {
+	streams := &bytes.Buffer{}
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllStringSubmatch(doc, -1) {
+		r, err := zlib.NewReader(strings.NewReader(m[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		streams.Write(b)
+	}
+	return streams.String()
+}
+
+func TestWriteStructure(t *testing.T) {
+	page := &displaylist.List{Size: math.Size{W: 200, H: 100}, Ops: []displaylist.Op{
+		displaylist.DrawRect{Rect: math.CreateRect(0, 0, 10, 10), Brush: gxui.CreateBrush(gxui.Red)},
+	}}
+	doc := writeTestDocument(t, page, page)
+
+	test.AssertEquals(t, true, strings.HasPrefix(doc, "%PDF-1.4\n"))
+	test.AssertEquals(t, true, strings.HasSuffix(doc, "%%EOF\n"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/Type /Pages /Count 2"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/MediaBox [0 0 200 100]"))
+
+	// Every cross-reference entry must point at its object.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(doc)
+	xref, _ := strconv.Atoi(startxref[1])
+	test.AssertEquals(t, true, strings.HasPrefix(doc[xref:], "xref\n"))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(doc[xref:], -1)
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(e[1])
+		expected := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !strings.HasPrefix(doc[offset:], expected) {
+			t.Errorf("xref entry %d points at %q", i+1, doc[offset:offset+10])
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	ttf, err := truetype.Parse(gxfont.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := &displaylist.List{Size: math.Size{W: 200, H: 100}, Ops: []displaylist.Op{
+		runes("Hi", 10, 20, 50),
+	}}
+	doc := writeTestDocument(t, page)
+	for _, expected := range []string{
+		"/Subtype /Type0",
+		"/Encoding /Identity-H",
+		"/Subtype /CIDFontType2",
+		"/FontFile2",
+		"/ToUnicode",
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %s", expected)
+		}
+	}
+
+	h, i := ttf.Index('H'), ttf.Index('i')
+	streams := contentStreams(t, doc)
+	for _, expected := range []string{
+		"/F1 10 Tf",
+		"1 0 0 -1 10 20 Tm",
+		fmt.Sprintf("<%.4X>", h),
+		fmt.Sprintf("<%.4X> <0048>", h), // ToUnicode
+		fmt.Sprintf("<%.4X> <0069>", i),
+	} {
+		if !strings.Contains(streams, expected) {
+			t.Errorf("Expected streams to contain %s\n%s", expected, streams)
+		}
+	}
+}
+
+func TestWriteCullsHiddenContent(t *testing.T) {
+	content := &displaylist.List{Size: math.Size{W: 100, H: 200}, Ops: []displaylist.Op{
+		runes("AAA", 0, 20, 10),
+		runes("ZZZ", 0, 150, 10),
+		displaylist.DrawRect{Rect: math.CreateRect(0, 160, 10, 170), Brush: gxui.CreateBrush(gxui.Blue)},
+	}}
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.Push{},
+		displaylist.AddClip{Rect: math.CreateRect(0, 0, 100, 100)},
+		displaylist.DrawCanvas{Canvas: content},
+		displaylist.Pop{},
+	}}
+	ttf, err := truetype.Parse(gxfont.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	streams := contentStreams(t, writeTestDocument(t, page))
+	test.AssertEquals(t, true, strings.Contains(streams, fmt.Sprintf("<%.4X>", ttf.Index('A'))))
+	test.AssertEquals(t, false, strings.Contains(streams, fmt.Sprintf("<%.4X>", ttf.Index('Z'))))
+	test.AssertEquals(t, false, strings.Contains(streams, "0 160 10 10 re"))
+}
+
+func TestWriteTransforms(t *testing.T) {
+	content := &displaylist.List{Size: math.Size{W: 100, H: 200}, Ops: []displaylist.Op{
+		displaylist.DrawRect{Rect: math.CreateRect(0, 0, 10, 10), Brush: gxui.CreateBrush(gxui.Red)},
+		displaylist.DrawRect{Rect: math.CreateRect(0, 60, 10, 70), Brush: gxui.CreateBrush(gxui.Blue)},
+	}}
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.PushTransform{Transform: math.CreateMat3Scale(math.Vec2{X: 2, Y: 2})},
+		displaylist.DrawCanvas{Canvas: content},
+		displaylist.Pop{},
+	}}
+	streams := contentStreams(t, writeTestDocument(t, page))
+	test.AssertEquals(t, true, strings.Contains(streams, "q 2 0 0 2 0 0 cm"))
+	test.AssertEquals(t, true, strings.Contains(streams, "0 0 10 10 re"))
+	// The second rect is scaled off the bottom of the page.
+	test.AssertEquals(t, false, strings.Contains(streams, "0 60 10 10 re"))
+}
+
+func TestWriteLayers(t *testing.T) {
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.Push{},
+		displaylist.AddClip{Rect: math.CreateRect(0, 0, 50, 50)},
+		displaylist.PushLayer{Opacity: 0.5, Blend: gxui.MultiplyBlend},
+		displaylist.DrawRect{Rect: math.CreateRect(0, 0, 20, 10), Brush: gxui.CreateBrush(gxui.Red)},
+		displaylist.PopLayer{},
+		displaylist.Pop{},
+	}}
+	doc := writeTestDocument(t, page)
+	streams := contentStreams(t, doc)
+	test.AssertEquals(t, true, strings.Contains(streams, "q /GS1 gs /Fm1 Do Q"))
+	test.AssertEquals(t, true, strings.Contains(streams, "0 0 20 10 re"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/Fm1 "))
+	test.AssertEquals(t, true, strings.Contains(doc, "/Subtype /Form /BBox [0 0 50 50] /Group << /S /Transparency >>"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/GS1 << /Type /ExtGState /ca 0.5 /CA 0.5 /BM /Multiply >>"))
+}
+
+func TestWriteShadow(t *testing.T) {
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.DrawShadow{
+			Rect:   math.CreateRect(10, 10, 30, 20),
+			Shadow: gxui.Shadow{Color: gxui.Black, Blur: 4},
+		},
+	}}
+	doc := writeTestDocument(t, page)
+	streams := contentStreams(t, doc)
+	test.AssertEquals(t, true, strings.Contains(streams, "q 32 0 0 -22 4 26 cm /Im1 Do Q"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/Subtype /Image /Width 64 /Height 44 /ColorSpace /DeviceGray"))
+}
+
+func TestWriteShapes(t *testing.T) {
+	dashed := gxui.CreateDashedPen(2, gxui.White, 3, 1)
+	dashed.DashOffset, dashed.Cap, dashed.Join = 1, gxui.RoundCap, gxui.RoundJoin
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.DrawRoundedRect{
+			Rect: math.CreateRect(0, 0, 20, 10), TL: 2, TR: 2, BL: 2, BR: 2,
+			Pen: gxui.CreatePen(1, gxui.Gray40), Brush: gxui.CreateBrush(gxui.Color{R: 1, A: 0.5}),
+		},
+		displaylist.DrawLines{
+			Lines: gxui.Polygon{{Position: math.Point{X: 0, Y: 50}}, {Position: math.Point{X: 40, Y: 50}}},
+			Pen:   gxui.CreatePen(2, gxui.White),
+		},
+		displaylist.DrawLines{
+			Lines: gxui.Polygon{{Position: math.Point{X: 0, Y: 60}}, {Position: math.Point{X: 40, Y: 60}}},
+			Pen:   dashed,
+		},
+		displaylist.DrawTexture{
+			Texture: displaylist.TextureRef{Texture: testTexture{image.NewRGBA(image.Rect(0, 0, 2, 2))}},
+			Rect:    math.CreateRect(30, 0, 40, 10),
+		},
+	}}
+	doc := writeTestDocument(t, page)
+	streams := contentStreams(t, doc)
+	for _, expected := range []string{
+		"/GS1 gs 1 0 0 rg",
+		"0 2 m\n0 0.895 0.895 0 2 0 c\n18 0 l\n",
+		"2 w S",
+		"0 61 m\n40 61 l\n2 w 1 J 1 j [3 1] 1 d S",
+		"0 51 m\n40 51 l\n2 w S",
+		"q 10 0 0 -10 30 10 cm /Im1 Do Q",
+	} {
+		if !strings.Contains(streams, expected) {
+			t.Errorf("Expected streams to contain %q\n%s", expected, streams)
+		}
+	}
+	test.AssertEquals(t, true, strings.Contains(doc, "/ExtGState << /GS1 << /Type /ExtGState /ca 0.5 /CA 0.5 >>"))
+	test.AssertEquals(t, true, strings.Contains(doc, "/SMask"))
+}
+
+func TestWriteBrushes(t *testing.T) {
+	pattern := gxui.CreatePatternBrush(testTexture{image.NewRGBA(image.Rect(0, 0, 2, 2))})
+	page := &displaylist.List{Size: math.Size{W: 100, H: 100}, Ops: []displaylist.Op{
+		displaylist.DrawRect{
+			Rect: math.CreateRect(0, 0, 20, 10),
+			Brush: gxui.CreateLinearGradientBrush(math.Vec2{}, math.Vec2{Y: 1},
+				gxui.GradientStop{Offset: 0, Color: gxui.Red},
+				gxui.GradientStop{Offset: 0.5, Color: gxui.Green},
+				gxui.GradientStop{Offset: 1, Color: gxui.Blue}),
+		},
+		displaylist.DrawRect{
+			Rect:  math.CreateRect(0, 20, 30, 40),
+			Brush: gxui.CreateRadialGradientBrush(math.Vec2{X: 0.5, Y: 0.5}, 0.5, gxui.GradientStop{Offset: 0.2, Color: gxui.White}),
+		},
+		displaylist.DrawRect{Rect: math.CreateRect(50, 50, 54, 53), Brush: pattern},
+	}}
+	doc := writeTestDocument(t, page)
+	streams := contentStreams(t, doc)
+	for _, expected := range []string{
+		"0 0 20 10 re\nW n\n20 0 0 10 0 0 cm /Sh1 sh",
+		"0 20 30 20 re\nW n\n30 0 0 20 0 20 cm /Sh2 sh",
+		"50 50 4 3 re\nW n\n",
+		"q 2 0 0 -2 52 54 cm /Im1 Do Q",
+	} {
+		if !strings.Contains(streams, expected) {
+			t.Errorf("Expected streams to contain %q\n%s", expected, streams)
+		}
+	}
+	// The pattern covers the rect with 2x2 tiles.
+	test.AssertEquals(t, 4, strings.Count(streams, "/Im1 Do"))
+	for _, expected := range []string{
+		"/ShadingType 2 /ColorSpace /DeviceRGB /Coords [0 0 0 1]",
+		"/C0 [1 0 0] /C1 [0 1 0]",
+		"/Bounds [ 0.5 ]",
+		"/ShadingType 3 /ColorSpace /DeviceRGB /Coords [0.5 0.5 0 0.5 0.5 0.5]",
+		"/Domain [0 1] /C0 [1 1 1] /C1 [1 1 1]",
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %q", expected)
+		}
+	}
+}
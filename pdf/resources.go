@@ -0,0 +1,283 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// resources holds the fonts, images, groups and graphics states referenced by
+// the page content streams. A single resource dictionary is shared by all
+// pages and groups.
+type resources struct {
+	fonts       []*font
+	fontsByData map[*byte]*font
+	fallback    *font
+	images      []*xobject
+	imagesByTex map[gxui.Texture]*xobject
+	groups      []*group
+	states      []graphicsState
+	stateNames  map[graphicsState]string
+	shadings    []*gxui.Gradient
+}
+
+// graphicsState is the fill and stroke alpha and blend mode set by an
+// ExtGState resource.
+type graphicsState struct {
+	alpha float32
+	blend gxui.BlendMode
+}
+
+func newResources() *resources {
+	return &resources{
+		fontsByData: make(map[*byte]*font),
+		imagesByTex: make(map[gxui.Texture]*xobject),
+		stateNames:  make(map[graphicsState]string),
+	}
+}
+
+// font returns the document font for f, embedding its TrueType data if
+// available.
+func (r *resources) font(f gxui.Font) *font {
+	if t, ok := f.(gxui.TrueTypeFont); ok {
+		if data := t.TrueTypeData(); len(data) > 0 {
+			if pf, found := r.fontsByData[&data[0]]; found {
+				return pf
+			}
+			if pf := newTrueTypeFont(fmt.Sprintf("F%d", len(r.fonts)+1), data); pf != nil {
+				r.fontsByData[&data[0]] = pf
+				r.fonts = append(r.fonts, pf)
+				return pf
+			}
+		}
+	}
+	if r.fallback == nil {
+		r.fallback = newFallbackFont(fmt.Sprintf("F%d", len(r.fonts)+1))
+		r.fonts = append(r.fonts, r.fallback)
+	}
+	return r.fallback
+}
+
+// image returns the name of the image XObject for the texture t.
+func (r *resources) image(t gxui.Texture) string {
+	if x, found := r.imagesByTex[t]; found {
+		return x.name
+	}
+	x := &xobject{name: fmt.Sprintf("Im%d", len(r.images)+1), img: t.Image()}
+	r.imagesByTex[t] = x
+	r.images = append(r.images, x)
+	return x.name
+}
+
+// raster returns the name of a new image XObject for img, which is not
+// shared with any other op.
+func (r *resources) raster(img image.Image) string {
+	x := &xobject{name: fmt.Sprintf("Im%d", len(r.images)+1), img: img}
+	r.images = append(r.images, x)
+	return x.name
+}
+
+// alpha returns the name of the graphics state that sets both the fill and
+// stroke alpha to a.
+func (r *resources) alpha(a float32) string {
+	return r.state(graphicsState{alpha: a})
+}
+
+// state returns the name of the graphics state s.
+func (r *resources) state(s graphicsState) string {
+	if n, found := r.stateNames[s]; found {
+		return n
+	}
+	n := fmt.Sprintf("GS%d", len(r.states)+1)
+	r.stateNames[s] = n
+	r.states = append(r.states, s)
+	return n
+}
+
+// group returns the name of a new transparency group XObject drawing the
+// content stream data, which is clipped to bbox.
+func (r *resources) group(data []byte, bbox math.Rect) string {
+	g := &group{name: fmt.Sprintf("Fm%d", len(r.groups)+1), data: data, bbox: bbox}
+	r.groups = append(r.groups, g)
+	return g.name
+}
+
+// shading returns the name of the shading that paints the gradient g, in
+// units of the filled shape's bounds.
+func (r *resources) shading(g *gxui.Gradient) string {
+	for i, s := range r.shadings {
+		if s == g {
+			return fmt.Sprintf("Sh%d", i+1)
+		}
+	}
+	r.shadings = append(r.shadings, g)
+	return fmt.Sprintf("Sh%d", len(r.shadings))
+}
+
+// write writes the objects for each resource to f, returning the resource
+// dictionary. id is the object number of the resource dictionary, which is
+// also used by groups.
+func (r *resources) write(f *file, id int) string {
+	dict := &bytes.Buffer{}
+	dict.WriteString("<< /ProcSet [/PDF /Text /ImageB /ImageC]")
+	if len(r.fonts) > 0 {
+		dict.WriteString(" /Font <<")
+		for _, pf := range r.fonts {
+			fmt.Fprintf(dict, " /%s %d 0 R", pf.name, pf.write(f))
+		}
+		dict.WriteString(" >>")
+	}
+	if len(r.images) > 0 || len(r.groups) > 0 {
+		dict.WriteString(" /XObject <<")
+		for _, x := range r.images {
+			fmt.Fprintf(dict, " /%s %d 0 R", x.name, x.write(f))
+		}
+		for _, g := range r.groups {
+			fmt.Fprintf(dict, " /%s %d 0 R", g.name, g.write(f, id))
+		}
+		dict.WriteString(" >>")
+	}
+	if len(r.states) > 0 {
+		dict.WriteString(" /ExtGState <<")
+		for _, s := range r.states {
+			fmt.Fprintf(dict, " /%s << /Type /ExtGState /ca %s /CA %s", r.stateNames[s], number(s.alpha), number(s.alpha))
+			if bm := blendName(s.blend); bm != "" {
+				fmt.Fprintf(dict, " /BM /%s", bm)
+			}
+			dict.WriteString(" >>")
+		}
+		dict.WriteString(" >>")
+	}
+	if len(r.shadings) > 0 {
+		dict.WriteString(" /Shading <<")
+		for i, g := range r.shadings {
+			fmt.Fprintf(dict, " /Sh%d %s", i+1, shadingDict(g))
+		}
+		dict.WriteString(" >>")
+	}
+	dict.WriteString(" >>")
+	return dict.String()
+}
+
+// blendName returns the PDF name of the blend mode b, or an empty string for
+// gxui.NormalBlend. PDF has no additive blend mode, so AdditiveBlend uses the
+// similar Screen mode.
+func blendName(b gxui.BlendMode) string {
+	switch b {
+	case gxui.MultiplyBlend:
+		return "Multiply"
+	case gxui.ScreenBlend, gxui.AdditiveBlend:
+		return "Screen"
+	default:
+		return ""
+	}
+}
+
+// group is a transparency group: content drawn as a whole with an opacity
+// and blend mode.
+type group struct {
+	name string
+	data []byte
+	bbox math.Rect
+}
+
+// write writes the group as a form XObject using the resource dictionary
+// with object number res, returning the object number of the form.
+func (g *group) write(f *file, res int) int {
+	id := f.alloc()
+	b := g.bbox
+	f.stream(id, fmt.Sprintf("/Type /XObject /Subtype /Form /BBox [%d %d %d %d] "+
+		"/Group << /S /Transparency >> /Resources %d 0 R ", b.Min.X, b.Min.Y, b.Max.X, b.Max.Y, res), g.data)
+	return id
+}
+
+// xobject is an image embedded in the document.
+type xobject struct {
+	name string
+	img  image.Image
+}
+
+// write writes the image as an RGB image XObject with a soft mask holding
+// its alpha, returning the object number of the image.
+func (x *xobject) write(f *file) int {
+	b := x.img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), x.img, b.Min, draw.Src)
+
+	rgb := make([]byte, 0, b.Dx()*b.Dy()*3)
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		rgb = append(rgb, nrgba.Pix[i:i+3]...)
+		alpha = append(alpha, nrgba.Pix[i+3])
+		opaque = opaque && nrgba.Pix[i+3] == 0xff
+	}
+
+	id := f.alloc()
+	smask := ""
+	if !opaque {
+		mask := f.alloc()
+		f.stream(mask, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d "+
+			"/ColorSpace /DeviceGray /BitsPerComponent 8 ", b.Dx(), b.Dy()), alpha)
+		smask = fmt.Sprintf("/SMask %d 0 R ", mask)
+	}
+	f.stream(id, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d "+
+		"/ColorSpace /DeviceRGB /BitsPerComponent 8 %s", b.Dx(), b.Dy(), smask), rgb)
+	return id
+}
+
+// shadingDict returns an axial or radial shading dictionary for the gradient
+// g. The color of the gradient is a stitching function of linear segments
+// between each pair of stops. Stop alphas are not part of a shading, and are
+// approximated by the alpha of the brush.
+func shadingDict(g *gxui.Gradient) string {
+	stops := g.Stops
+	if len(stops) == 0 {
+		stops = []gxui.GradientStop{{Offset: 0, Color: gxui.Transparent}}
+	}
+	// The stitching function's domain is [0, 1], so pad to the ends.
+	if first := stops[0]; first.Offset > 0 {
+		stops = append([]gxui.GradientStop{{Offset: 0, Color: first.Color}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.Offset < 1 || len(stops) == 1 {
+		stops = append(stops, gxui.GradientStop{Offset: 1, Color: last.Color})
+	}
+
+	fn := &bytes.Buffer{}
+	fn.WriteString("<< /FunctionType 3 /Domain [0 1] /Functions [")
+	for i := 1; i < len(stops); i++ {
+		fmt.Fprintf(fn, " << /FunctionType 2 /Domain [0 1] /C0 [%s] /C1 [%s] /N 1 >>",
+			rgb(stops[i-1].Color), rgb(stops[i].Color))
+	}
+	fn.WriteString(" ] /Bounds [")
+	for _, s := range stops[1 : len(stops)-1] {
+		fmt.Fprintf(fn, " %s", number(s.Offset))
+	}
+	fn.WriteString(" ] /Encode [")
+	for i := 1; i < len(stops); i++ {
+		fn.WriteString(" 0 1")
+	}
+	fn.WriteString(" ] >>")
+
+	switch g.Kind {
+	case gxui.RadialGradient:
+		return fmt.Sprintf("<< /ShadingType 3 /ColorSpace /DeviceRGB /Coords [%s %s 0 %s %s %s] /Function %s /Extend [true true] >>",
+			number(g.Start.X), number(g.Start.Y), number(g.Start.X), number(g.Start.Y), number(g.Radius), fn)
+	default:
+		return fmt.Sprintf("<< /ShadingType 2 /ColorSpace /DeviceRGB /Coords [%s %s %s %s] /Function %s /Extend [true true] >>",
+			number(g.Start.X), number(g.Start.Y), number(g.End.X), number(g.End.Y), fn)
+	}
+}
+
+func rgb(c gxui.Color) string {
+	return fmt.Sprintf("%s %s %s", number(c.R), number(c.G), number(c.B))
+}
@@ -11,8 +11,92 @@ var WhitePen Pen = CreatePen(1.0, White)
 type Pen struct {
 	Width float32
 	Color Color
+
+	// Dashes, if not nil, breaks the stroke into dashes.
+	Dashes *DashPattern `json:",omitempty"`
+
+	// DashOffset is the distance in DIPs into the dash pattern at which the
+	// stroke starts. Animating the offset produces "marching ants".
+	DashOffset float32 `json:",omitempty"`
+
+	// Cap is the shape of the ends of open lines and of each dash.
+	Cap LineCap `json:",omitempty"`
+
+	// Join is the shape of the stroke at sharp vertices.
+	Join LineJoin `json:",omitempty"`
 }
 
 func CreatePen(width float32, color Color) Pen {
-	return Pen{width, color}
+	return Pen{Width: width, Color: color}
+}
+
+// CreateDashedPen returns a Pen that strokes with a dash pattern of
+// alternating on and off lengths, in DIPs. An odd number of lengths is
+// repeated to make the pattern even, so CreateDashedPen(1, c, 2) draws 2 DIP
+// dashes separated by 2 DIP gaps.
+func CreateDashedPen(width float32, color Color, lengths ...float32) Pen {
+	dashes := DashPattern(append([]float32{}, lengths...))
+	if len(dashes)%2 == 1 {
+		dashes = append(dashes, dashes...)
+	}
+	return Pen{Width: width, Color: color, Dashes: &dashes}
+}
+
+// IsDashed returns true if the pen has a dash pattern with at least one gap.
+func (p Pen) IsDashed() bool {
+	return p.Dashes != nil && p.Dashes.Length() > 0 && p.Dashes.hasGap()
+}
+
+// DashPattern is a list of alternating on and off lengths, in DIPs.
+type DashPattern []float32
+
+// Length returns the total length of one repetition of the pattern.
+func (d DashPattern) Length() float32 {
+	l := float32(0)
+	for _, v := range d {
+		if v > 0 {
+			l += v
+		}
+	}
+	return l
 }
+
+func (d DashPattern) hasGap() bool {
+	for i := 1; i < len(d); i += 2 {
+		if d[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LineCap is the shape of the ends of a stroke.
+type LineCap int
+
+const (
+	// ButtCap ends the stroke at the end point.
+	ButtCap LineCap = iota
+
+	// RoundCap ends the stroke with a semicircle of diameter equal to the
+	// pen width.
+	RoundCap
+
+	// SquareCap extends the stroke beyond the end point by half the pen
+	// width.
+	SquareCap
+)
+
+// LineJoin is the shape of the stroke at vertices that are not rounded.
+type LineJoin int
+
+const (
+	// MiterJoin extends the edges of the stroke to meet at a point.
+	MiterJoin LineJoin = iota
+
+	// RoundJoin rounds the corner of the stroke with a radius equal to the
+	// pen width.
+	RoundJoin
+
+	// BevelJoin cuts the corner of the stroke off with a straight edge.
+	BevelJoin
+)
@@ -0,0 +1,32 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"time"
+)
+
+// PerfOverlay is a Control that displays the live rendering statistics of
+// the Driver, as returned by Driver.Stats, in a corner of its parent.
+// Adding a PerfOverlay to a Window shows the statistics over the window's
+// content. The overlay does not respond to the mouse.
+type PerfOverlay interface {
+	Control
+
+	// HorizontalAlignment returns the corner of the parent the statistics
+	// are displayed in.
+	HorizontalAlignment() HorizontalAlignment
+	SetHorizontalAlignment(HorizontalAlignment)
+
+	// VerticalAlignment returns the corner of the parent the statistics are
+	// displayed in.
+	VerticalAlignment() VerticalAlignment
+	SetVerticalAlignment(VerticalAlignment)
+
+	// RefreshInterval returns the time between updates of the displayed
+	// statistics.
+	RefreshInterval() time.Duration
+	SetRefreshInterval(time.Duration)
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// TreeIndent is the number of points each level of a tree is indented by in
+// the control returned by TreeContents.
+const TreeIndent = 16
+
+// ListContents returns a control holding the item control of every entry of
+// the adapter, stacked top to bottom, for passing to Paginate. Unlike a List,
+// which only creates the items that are visible, every item is created.
+func ListContents(theme gxui.Theme, adapter gxui.ListAdapter) gxui.Control {
+	layout := theme.CreateLinearLayout()
+	layout.SetDirection(gxui.TopToBottom)
+	for i, count := 0, adapter.Count(); i < count; i++ {
+		layout.AddChild(adapter.Create(theme, i))
+	}
+	return layout
+}
+
+// TreeContents returns a control holding the item control of every node of
+// the adapter, fully expanded, for passing to Paginate. Nodes are listed
+// depth-first, with each level indented by TreeIndent.
+func TreeContents(theme gxui.Theme, adapter gxui.TreeAdapter) gxui.Control {
+	layout := theme.CreateLinearLayout()
+	layout.SetDirection(gxui.TopToBottom)
+	addTreeNodes(theme, layout, adapter, 0)
+	return layout
+}
+
+func addTreeNodes(theme gxui.Theme, layout gxui.LinearLayout, n gxui.TreeNodeContainer, depth int) {
+	for i, count := 0, n.Count(); i < count; i++ {
+		node := n.NodeAt(i)
+		item := node.Create(theme)
+		item.SetMargin(math.Spacing{L: depth * TreeIndent})
+		layout.AddChild(item)
+		addTreeNodes(theme, layout, node, depth+1)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"fmt"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// CreatePreview returns a scrollable control showing each of the pages, as
+// returned by Paginate, at their printed size. Each page is drawn on white
+// paper with a border, and captioned with its page number.
+func CreatePreview(theme gxui.Theme, pages []gxui.Canvas) gxui.Control {
+	layout := theme.CreateLinearLayout()
+	layout.SetDirection(gxui.TopToBottom)
+	layout.SetHorizontalAlignment(gxui.AlignCenter)
+	layout.SetPadding(math.CreateSpacing(16))
+
+	for i, page := range pages {
+		image := theme.CreateImage()
+		image.SetCanvas(page)
+		image.SetBackgroundBrush(gxui.WhiteBrush)
+		image.SetBorderPen(gxui.CreatePen(1, gxui.Gray50))
+		layout.AddChild(image)
+
+		caption := theme.CreateLabel()
+		caption.SetText(fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+		caption.SetMargin(math.Spacing{T: 4, B: 16})
+		layout.AddChild(caption)
+	}
+
+	scroll := theme.CreateScrollLayout()
+	scroll.SetScrollAxis(true, true)
+	scroll.SetChild(layout)
+	return scroll
+}
@@ -0,0 +1,176 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package printing paginates gxui controls into pages of a fixed paper size,
+// writes the pages as PDF documents and previews them on screen.
+//
+// Pages are measured in points (1/72 inch), with one point drawn as one DIP,
+// so a control printed on a page appears at the same physical size as it
+// would on a screen at 72 DIPs per inch. Controls are printed with the colors
+// of the theme they were created with, so reports intended for paper are
+// usually best built with a light theme.
+package printing
+
+import (
+	"io"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/pdf"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Standard paper sizes, in points.
+var (
+	A3     = math.Size{W: 842, H: 1191}
+	A4     = math.Size{W: 595, H: 842}
+	A5     = math.Size{W: 420, H: 595}
+	Letter = math.Size{W: 612, H: 792}
+	Legal  = math.Size{W: 612, H: 1008}
+)
+
+// PageSetup describes the pages that controls are paginated into.
+type PageSetup struct {
+	// PaperSize is the size of each page, in points.
+	PaperSize math.Size
+
+	// Margins is the unprinted border around the content of each page.
+	Margins math.Spacing
+
+	// Background, if not transparent, is painted across each page before the
+	// content.
+	Background gxui.Color
+}
+
+// DefaultPageSetup returns a PageSetup for the paper size with half inch
+// margins and a white background.
+func DefaultPageSetup(paper math.Size) PageSetup {
+	return PageSetup{
+		PaperSize:  paper,
+		Margins:    math.CreateSpacing(36),
+		Background: gxui.White,
+	}
+}
+
+// ContentSize returns the size of the printable area of each page.
+func (s PageSetup) ContentSize() math.Size {
+	return s.PaperSize.Contract(s.Margins)
+}
+
+// Paginate lays out the control c to the width of the page content area,
+// draws it and splits the result across as many pages as are needed.
+//
+// Page breaks are placed between controls: a break never passes through a
+// control that has no children, unless that control is taller than a page.
+// A TableLayout or LinearLayout of Labels and Images is therefore split
+// between its cells. The control does not need to be attached to a Window;
+// if c is not attached then it is temporarily attached for the duration of
+// the call.
+//
+// Paginate must be called on the UI go-routine.
+func Paginate(theme gxui.Theme, c gxui.Control, setup PageSetup) []gxui.Canvas {
+	driver := theme.Driver()
+	driver.AssertUIGoroutine()
+
+	content := setup.ContentSize()
+	if content.W <= 0 || content.H <= 0 {
+		return nil
+	}
+
+	if !c.Attached() {
+		c.Attach()
+		defer c.Detach()
+	}
+
+	oldSize := c.Size()
+	size := c.DesiredSize(
+		math.Size{W: content.W},
+		math.Size{W: content.W, H: math.MaxSize.H})
+	c.SetSize(size)
+	defer c.SetSize(oldSize)
+
+	canvas := c.Draw()
+
+	pages := []gxui.Canvas{}
+	top := 0
+	for _, bottom := range pageBreaks(leafSpans(c, 0, nil), size.H, content.H) {
+		page := driver.CreateCanvas(setup.PaperSize)
+		if setup.Background.A > 0 {
+			page.Clear(setup.Background)
+		}
+		if canvas != nil {
+			page.Push()
+			page.AddClip(math.CreateRect(
+				setup.Margins.L, setup.Margins.T,
+				setup.Margins.L+content.W, setup.Margins.T+bottom-top))
+			page.DrawCanvas(canvas, math.Point{X: setup.Margins.L, Y: setup.Margins.T - top})
+			page.Pop()
+		}
+		page.Complete()
+		pages = append(pages, page)
+		top = bottom
+	}
+	return pages
+}
+
+// WritePDF writes the pages as a PDF document to w.
+func WritePDF(w io.Writer, pages []gxui.Canvas) error {
+	return pdf.Write(w, pages...)
+}
+
+// span is the vertical extent of a control, in the coordinates of the control
+// being paginated.
+type span struct {
+	top, bottom int
+}
+
+// leafSpans appends the span of every descendant of c that has no children.
+func leafSpans(c gxui.Control, offset int, spans []span) []span {
+	if p, ok := c.(gxui.Parent); ok && len(p.Children()) > 0 {
+		for _, child := range p.Children() {
+			spans = leafSpans(child.Control, offset+child.Offset.Y, spans)
+		}
+		return spans
+	}
+	return append(spans, span{offset, offset + c.Size().H})
+}
+
+// pageBreaks returns the bottom of each page when content of the given height
+// is split into pages of pageHeight, avoiding breaking through any of the
+// leaves. There is always at least one page.
+func pageBreaks(leaves []span, height, pageHeight int) []int {
+	breaks := []int{}
+	top := 0
+	for {
+		bottom := top + pageHeight
+		if bottom >= height {
+			return append(breaks, height)
+		}
+		// Move the break above any leaf it would cut, until none are cut.
+		b := bottom
+		for moved := true; moved && b > top; {
+			moved = false
+			for _, l := range leaves {
+				if l.top < b && b < l.bottom && l.top > top {
+					b, moved = l.top, true
+				}
+			}
+		}
+		if b <= top || cuts(leaves, b) {
+			b = bottom // Nothing fits whole on the page
+		}
+		breaks = append(breaks, b)
+		top = b
+	}
+}
+
+// cuts returns true if y passes through any of the leaves.
+func cuts(leaves []span, y int) bool {
+	for _, l := range leaves {
+		if l.top < y && y < l.bottom {
+			return true
+		}
+	}
+	return false
+}
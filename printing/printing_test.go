@@ -0,0 +1,98 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+	test "github.com/robertt-smg/gxui/testing"
+)
+
+func TestPageBreaks(t *testing.T) {
+	leaves := []span{{0, 30}, {30, 60}, {60, 90}, {90, 120}}
+	test.AssertEquals(t, []int{60, 120}, pageBreaks(leaves, 120, 80))
+	test.AssertEquals(t, []int{50}, pageBreaks(leaves, 50, 80))
+
+	// A leaf taller than a page is cut.
+	test.AssertEquals(t, []int{80, 160, 200}, pageBreaks([]span{{0, 200}}, 200, 80))
+	test.AssertEquals(t, []int{10, 90, 170, 200}, pageBreaks([]span{{0, 10}, {10, 200}}, 200, 80))
+}
+
+func TestPaginate(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver)
+		layout := theme.CreateLinearLayout()
+		layout.SetDirection(gxui.TopToBottom)
+		for i := 0; i < 10; i++ {
+			label := theme.CreateLabel()
+			label.SetText(fmt.Sprintf("Row %d", i))
+			label.SetMargin(math.Spacing{})
+			layout.AddChild(label)
+		}
+
+		setup := PageSetup{PaperSize: math.Size{W: 200, H: 100}, Margins: math.CreateSpacing(10)}
+		rowH := theme.DefaultFont().GlyphMaxSize().H
+		rowsPerPage := setup.ContentSize().H / rowH
+		pages := Paginate(theme, layout, setup)
+		test.AssertEquals(t, (10+rowsPerPage-1)/rowsPerPage, len(pages))
+		test.AssertEquals(t, false, layout.Attached())
+
+		for i, page := range pages {
+			test.AssertEquals(t, setup.PaperSize, page.Size())
+			l := displaylist.FromCanvas(page)
+			clip := l.Ops[1].(displaylist.AddClip).Rect
+			rows := rowsPerPage
+			if i == len(pages)-1 {
+				rows = 10 - i*rowsPerPage
+			}
+			test.AssertEquals(t, math.CreateRect(10, 10, 190, 10+rows*rowH), clip)
+			offset := l.Ops[2].(displaylist.DrawCanvas).Offset
+			test.AssertEquals(t, math.Point{X: 10, Y: 10 - i*rowsPerPage*rowH}, offset)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := WritePDF(buf, pages); err != nil {
+			t.Fatal(err)
+		}
+		test.AssertEquals(t, true, strings.Contains(buf.String(), fmt.Sprintf("/Count %d", len(pages))))
+		test.AssertEquals(t, true, strings.Contains(buf.String(), "/FontFile2"))
+	})
+}
+
+func TestListContents(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver)
+		adapter := gxui.CreateDefaultAdapter()
+		adapter.SetItems([]string{"a", "b", "c"})
+		contents := ListContents(theme, adapter).(gxui.LinearLayout)
+		test.AssertEquals(t, 3, len(contents.Children()))
+	})
+}
+
+func TestCreatePreview(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver)
+		page := driver.CreateCanvas(math.Size{W: 50, H: 80})
+		page.Complete()
+		preview := CreatePreview(theme, []gxui.Canvas{page, page})
+		img := gxui.RenderToImage(theme, preview, math.Size{W: 200, H: 400}, 1)
+		test.AssertEquals(t, true, img.Bounds().Dy() > 160)
+	})
+}
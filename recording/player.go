@@ -0,0 +1,130 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recording
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// replayEpoch is the time of the start of a replayed recording, as seen by
+// the window's input clock.
+var replayEpoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Player replays a Recording into a window.
+type Player struct {
+	driver    gxui.Driver
+	window    gxui.Window
+	recording *Recording
+	index     int
+	now       time.Time
+	stopped   bool
+	onFinish  gxui.Event
+}
+
+// Play replays the events of rec into the window w. Before the first event,
+// the window is resized to the recorded size and scale. Each event is
+// injected once all the calls queued on driver have run, with the window's
+// input clock reporting the recorded time of the event. The events are
+// replayed as fast as the window handles them, regardless of the recorded
+// times. Play must be called on the UI go-routine.
+func Play(driver gxui.Driver, w gxui.Window, rec *Recording) *Player {
+	p := &Player{
+		driver:    driver,
+		window:    w,
+		recording: rec,
+		now:       replayEpoch,
+		onFinish:  gxui.CreateEvent(func() {}),
+	}
+	w.SetInputClock(func() time.Time { return p.now })
+	p.resize(rec.Size, rec.Scale)
+	driver.CallWhenIdle(p.step)
+	return p
+}
+
+// OnFinish subscribes f to be called once all the events have been replayed,
+// and the calls they queued have run. f is not called if the player is
+// stopped.
+func (p *Player) OnFinish(f func()) gxui.EventSubscription {
+	return p.onFinish.Listen(f)
+}
+
+// Playing returns true until the player has replayed all of the events or
+// has been stopped.
+func (p *Player) Playing() bool {
+	return !p.stopped
+}
+
+// Stop stops replaying events, and restores the window's input clock.
+func (p *Player) Stop() {
+	if !p.stopped {
+		p.stopped = true
+		p.window.SetInputClock(nil)
+	}
+}
+
+func (p *Player) step() {
+	if p.stopped {
+		return
+	}
+	if p.index == len(p.recording.Events) {
+		p.Stop()
+		p.onFinish.Fire()
+		return
+	}
+	e := p.recording.Events[p.index]
+	p.index++
+	p.now = replayEpoch.Add(e.Time)
+	p.inject(e)
+	p.driver.CallWhenIdle(p.step)
+}
+
+func (p *Player) inject(e Event) {
+	w := p.window
+	switch e.Type {
+	case MouseMove:
+		w.InjectMouseMove(e.MouseEvent())
+	case MouseEnter:
+		w.InjectMouseEnter(e.MouseEvent())
+	case MouseExit:
+		w.InjectMouseExit(e.MouseEvent())
+	case MouseDown:
+		w.InjectMouseDown(e.MouseEvent())
+	case MouseUp:
+		w.InjectMouseUp(e.MouseEvent())
+	case MouseScroll:
+		w.InjectMouseScroll(e.MouseEvent())
+	case KeyDown:
+		w.InjectKeyDown(e.KeyboardEvent())
+	case KeyUp:
+		w.InjectKeyUp(e.KeyboardEvent())
+	case KeyRepeat:
+		w.InjectKeyRepeat(e.KeyboardEvent())
+	case KeyStroke:
+		w.InjectKeyStroke(e.KeyStrokeEvent())
+	case Resize:
+		p.resize(e.Size, e.Scale)
+	case Composition:
+		w.InjectComposition(e.CompositionEvent())
+	case FileDrop:
+		w.InjectFileDrop(e.Paths, e.Point)
+	default:
+		panic(fmt.Errorf("Unknown recorded event type %q", e.Type))
+	}
+}
+
+// resize changes the scale and size of the window, if they differ.
+func (p *Player) resize(size math.Size, scale float32) {
+	if scale > 0 && p.window.Scale() != scale {
+		p.window.SetScale(scale)
+	}
+	if size.Area() > 0 && p.window.Size() != size {
+		p.window.SetSize(size)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recording
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Recorder records the input events of a window.
+type Recorder struct {
+	window        gxui.Window
+	start         time.Time
+	recording     Recording
+	subscriptions []gxui.EventSubscription
+}
+
+// Record starts recording the input events of the window w, until Stop is
+// called. Record must be called on the UI go-routine.
+func Record(w gxui.Window) *Recorder {
+	r := &Recorder{
+		window: w,
+		start:  time.Now(),
+		recording: Recording{
+			Version: Version,
+			Size:    w.Size(),
+			Scale:   w.Scale(),
+		},
+	}
+	mouse := func(t EventType) func(gxui.MouseEvent) {
+		return func(ev gxui.MouseEvent) {
+			r.add(Event{
+				Type:     t,
+				Point:    ev.WindowPoint,
+				Button:   ev.Button,
+				State:    ev.State,
+				ScrollX:  ev.ScrollX,
+				ScrollY:  ev.ScrollY,
+				Modifier: ev.Modifier,
+			})
+		}
+	}
+	key := func(t EventType) func(gxui.KeyboardEvent) {
+		return func(ev gxui.KeyboardEvent) {
+			r.add(Event{Type: t, Key: ev.Key, Modifier: ev.Modifier})
+		}
+	}
+	r.subscriptions = []gxui.EventSubscription{
+		w.OnMouseMove(mouse(MouseMove)),
+		w.OnMouseEnter(mouse(MouseEnter)),
+		w.OnMouseExit(mouse(MouseExit)),
+		w.OnMouseDown(mouse(MouseDown)),
+		w.OnMouseUp(mouse(MouseUp)),
+		w.OnMouseScroll(mouse(MouseScroll)),
+		w.OnKeyDown(key(KeyDown)),
+		w.OnKeyUp(key(KeyUp)),
+		w.OnKeyRepeat(key(KeyRepeat)),
+		w.OnKeyStroke(func(ev gxui.KeyStrokeEvent) {
+			r.add(Event{Type: KeyStroke, Character: ev.Character, Modifier: ev.Modifier})
+		}),
+		w.OnResize(func() {
+			r.add(Event{Type: Resize, Size: w.Size(), Scale: w.Scale()})
+		}),
+		w.OnComposition(func(ev gxui.CompositionEvent) {
+			r.add(Event{
+				Type:        Composition,
+				Composition: ev.Type,
+				Text:        ev.Text,
+				Cursor:      ev.Cursor,
+				Attributes:  append([]gxui.CompositionAttribute(nil), ev.Attributes...),
+			})
+		}),
+		w.OnFileDrop(func(paths []string, at math.Point) {
+			r.add(Event{Type: FileDrop, Paths: append([]string{}, paths...), Point: at})
+		}),
+	}
+	return r
+}
+
+func (r *Recorder) add(e Event) {
+	e.Time = time.Since(r.start)
+	r.recording.Events = append(r.recording.Events, e)
+}
+
+// Recording returns a copy of the events recorded so far.
+func (r *Recorder) Recording() *Recording {
+	rec := r.recording
+	rec.Events = append([]Event{}, r.recording.Events...)
+	return &rec
+}
+
+// Stop stops recording, returning the recorded events.
+func (r *Recorder) Stop() *Recording {
+	for _, s := range r.subscriptions {
+		s.Unlisten()
+	}
+	r.subscriptions = nil
+	return r.Recording()
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package recording captures the input events of a gxui.Window into a
+// portable file, and replays them into a Window.
+//
+// A Recorder listens to the input events raised by a window's viewport, and
+// timestamps them relative to the start of the recording. A Player injects
+// the events of a Recording back into a window with the gxui.InputInjector
+// methods, which dispatch them exactly as the viewport's events. Replays are
+// deterministic: the window's input clock is set to the recorded timestamps,
+// and every call queued on the driver is drained before the next event, so a
+// recording of a bug can be replayed as a regression test.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Version is the version of the file format written by Write.
+const Version = 3
+
+// EventType identifies the kind of a recorded Event.
+type EventType string
+
+const (
+	MouseMove   EventType = "MouseMove"
+	MouseEnter  EventType = "MouseEnter"
+	MouseExit   EventType = "MouseExit"
+	MouseDown   EventType = "MouseDown"
+	MouseUp     EventType = "MouseUp"
+	MouseScroll EventType = "MouseScroll"
+	KeyDown     EventType = "KeyDown"
+	KeyUp       EventType = "KeyUp"
+	KeyRepeat   EventType = "KeyRepeat"
+	KeyStroke   EventType = "KeyStroke"
+
+	// Resize is a change of the window's size or display scaling.
+	Resize EventType = "Resize"
+
+	// FileDrop is a drop of files from the operating system. Added in
+	// version 2.
+	FileDrop EventType = "FileDrop"
+
+	// Composition is an input method composition event. Added in version 3.
+	Composition EventType = "Composition"
+)
+
+// Event is a recorded input event. Only the fields used by the event's Type
+// are set.
+type Event struct {
+	// Time is the time of the event since the start of the recording.
+	Time time.Duration
+	Type EventType
+
+	// Mouse events, with Point in window coordinates.
+	Point            math.Point            `json:",omitempty"`
+	Button           gxui.MouseButton      `json:",omitempty"`
+	State            gxui.MouseState       `json:",omitempty"`
+	ScrollX, ScrollY int                   `json:",omitempty"`
+	Modifier         gxui.KeyboardModifier `json:",omitempty"`
+
+	// Keyboard and key-stroke events, which also use Modifier.
+	Key       gxui.KeyboardKey `json:",omitempty"`
+	Character rune             `json:",omitempty"`
+
+	// Resize events, with Size in DIPs.
+	Size  math.Size `json:",omitempty"`
+	Scale float32   `json:",omitempty"`
+
+	// File drop events, which also use Point.
+	Paths []string `json:",omitempty"`
+
+	// Composition events.
+	Composition gxui.CompositionEventType   `json:",omitempty"`
+	Text        string                      `json:",omitempty"`
+	Cursor      int                         `json:",omitempty"`
+	Attributes  []gxui.CompositionAttribute `json:",omitempty"`
+}
+
+// MouseEvent returns the mouse event recorded by e.
+func (e Event) MouseEvent() gxui.MouseEvent {
+	return gxui.MouseEvent{
+		Button:      e.Button,
+		State:       e.State,
+		Point:       e.Point,
+		WindowPoint: e.Point,
+		ScrollX:     e.ScrollX,
+		ScrollY:     e.ScrollY,
+		Modifier:    e.Modifier,
+	}
+}
+
+// KeyboardEvent returns the keyboard event recorded by e.
+func (e Event) KeyboardEvent() gxui.KeyboardEvent {
+	return gxui.KeyboardEvent{Key: e.Key, Modifier: e.Modifier}
+}
+
+// KeyStrokeEvent returns the key-stroke event recorded by e.
+func (e Event) KeyStrokeEvent() gxui.KeyStrokeEvent {
+	return gxui.KeyStrokeEvent{Character: e.Character, Modifier: e.Modifier}
+}
+
+// CompositionEvent returns the composition event recorded by e.
+func (e Event) CompositionEvent() gxui.CompositionEvent {
+	return gxui.CompositionEvent{
+		Type:       e.Composition,
+		Text:       e.Text,
+		Cursor:     e.Cursor,
+		Attributes: e.Attributes,
+	}
+}
+
+// Recording is a sequence of input events recorded from a window.
+type Recording struct {
+	Version int
+
+	// Size and Scale are the size, in DIPs, and the display scaling of the
+	// window when the recording started.
+	Size  math.Size
+	Scale float32
+
+	Events []Event
+}
+
+// Write writes the recording r to w as JSON, with one event per line.
+func Write(w io.Writer, r *Recording) error {
+	header, err := json.Marshal(struct {
+		Version int
+		Size    math.Size
+		Scale   float32
+	}{Version, r.Size, r.Scale})
+	if err != nil {
+		return err
+	}
+	b := bufio.NewWriter(w)
+	// Splice the events array into the header object.
+	b.Write(header[:len(header)-1])
+	b.WriteString(`,"Events":[`)
+	for i, e := range r.Events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+		b.Write(data)
+	}
+	b.WriteString("\n]}\n")
+	return b.Flush()
+}
+
+// Read reads a recording written by Write from r.
+func Read(r io.Reader) (*Recording, error) {
+	rec := &Recording{}
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, err
+	}
+	if rec.Version < 1 || rec.Version > Version {
+		return nil, fmt.Errorf("Unsupported recording version %d", rec.Version)
+	}
+	return rec, nil
+}
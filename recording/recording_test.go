@@ -0,0 +1,198 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recording_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/recording"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// createForm returns a window holding a text box.
+func createForm(theme gxui.Theme) (gxui.Window, gxui.TextBox) {
+	window := theme.CreateWindow(200, 100, "test")
+	textBox := theme.CreateTextBox()
+	textBox.SetDesiredWidth(150)
+	window.AddChild(textBox)
+	return window, textBox
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	fake.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window, textBox := createForm(theme)
+
+		var rec *recording.Recording
+		driver.Call(func() {
+			recorder := recording.Record(window)
+			gxui.ClickControl(textBox)
+			gxui.TypeText(window, "abc")
+			gxui.PressKey(window, gxui.KeyBackspace, 0)
+			window.SetSize(math.Size{W: 300, H: 120})
+			driver.CallWhenIdle(func() {
+				rec = recorder.Stop()
+				window.Close()
+				replay(t, driver, theme, rec)
+			})
+		})
+	})
+}
+
+func replay(t *testing.T, driver gxui.Driver, theme gxui.Theme, rec *recording.Recording) {
+	// 3 mouse events, 3 key-strokes, 2 key events and a resize.
+	if got, expected := len(rec.Events), 9; got != expected {
+		t.Errorf("Recorded %d events, expected %d:\n%+v", got, expected, rec.Events)
+	}
+	if last := rec.Events[len(rec.Events)-1]; last.Type != recording.Resize || last.Size != (math.Size{W: 300, H: 120}) {
+		t.Errorf("Last event was %+v, expected a resize to 300x120", last)
+	}
+
+	// The recording survives a round trip through the file format.
+	buffer := &bytes.Buffer{}
+	if err := recording.Write(buffer, rec); err != nil {
+		t.Fatalf("Write returned %v", err)
+	}
+	read, err := recording.Read(buffer)
+	if err != nil {
+		t.Fatalf("Read returned %v", err)
+	}
+	if !reflect.DeepEqual(read, rec) {
+		t.Errorf("Read returned %+v, expected %+v", read, rec)
+	}
+
+	// The replayed window ends in the same state.
+	window, textBox := createForm(theme)
+	player := recording.Play(driver, window, read)
+	player.OnFinish(func() {
+		defer driver.Terminate()
+		if got, expected := textBox.Text(), "ab"; got != expected {
+			t.Errorf("Text was %q, expected %q", got, expected)
+		}
+		if window.Focus() != textBox {
+			t.Errorf("Focus was %v, expected the text box", window.Focus())
+		}
+		if got, expected := window.Size(), (math.Size{W: 300, H: 120}); got != expected {
+			t.Errorf("Window size was %v, expected %v", got, expected)
+		}
+	})
+}
+
+func TestRecordAndReplayFileDrop(t *testing.T) {
+	fake.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window, textBox := createForm(theme)
+		paths := []string{"/tmp/a.txt", "/tmp/b.txt"}
+		at := math.Point{X: 10, Y: 5}
+
+		driver.Call(func() {
+			_, windowAt := gxui.ControlToWindow(textBox, at)
+			recorder := recording.Record(window)
+			gxui.DropFiles(textBox, at, paths)
+			rec := recorder.Stop()
+			window.Close()
+
+			buffer := &bytes.Buffer{}
+			if err := recording.Write(buffer, rec); err != nil {
+				t.Fatalf("Write returned %v", err)
+			}
+			read, err := recording.Read(buffer)
+			if err != nil {
+				t.Fatalf("Read returned %v", err)
+			}
+
+			window = theme.CreateWindow(200, 100, "test")
+			var dropped []string
+			var droppedAt math.Point
+			window.OnFileDrop(func(p []string, at math.Point) { dropped, droppedAt = p, at })
+			recording.Play(driver, window, read).OnFinish(func() {
+				defer driver.Terminate()
+				if !reflect.DeepEqual(dropped, paths) || droppedAt != windowAt {
+					t.Errorf("Dropped %v at %v, expected %v at %v", dropped, droppedAt, paths, windowAt)
+				}
+			})
+		})
+	})
+}
+
+func TestRecordAndReplayComposition(t *testing.T) {
+	fake.StartDriver(func(driver gxui.Driver) {
+		theme := dark.CreateTheme(driver)
+		window, textBox := createForm(theme)
+
+		driver.Call(func() {
+			recorder := recording.Record(window)
+			gxui.ClickControl(textBox)
+			gxui.ComposeText(window, "日本", "に", "にほ", "にほん")
+			driver.CallWhenIdle(func() {
+				rec := recorder.Stop()
+				window.Close()
+
+				buffer := &bytes.Buffer{}
+				if err := recording.Write(buffer, rec); err != nil {
+					t.Fatalf("Write returned %v", err)
+				}
+				read, err := recording.Read(buffer)
+				if err != nil {
+					t.Fatalf("Read returned %v", err)
+				}
+				if !reflect.DeepEqual(read, rec) {
+					t.Errorf("Read returned %+v, expected %+v", read, rec)
+				}
+
+				window, textBox := createForm(theme)
+				recording.Play(driver, window, read).OnFinish(func() {
+					defer driver.Terminate()
+					if got, expected := textBox.Text(), "日本"; got != expected {
+						t.Errorf("Text was %q, expected %q", got, expected)
+					}
+				})
+			})
+		})
+	})
+}
+
+func TestReplayUsesRecordedTimes(t *testing.T) {
+	for _, test := range []struct {
+		gap          time.Duration
+		doubleClicks int
+	}{
+		{gap: 100 * time.Millisecond, doubleClicks: 1},
+		{gap: time.Second, doubleClicks: 0},
+	} {
+		fake.StartDriver(func(driver gxui.Driver) {
+			theme := dark.CreateTheme(driver)
+			window := theme.CreateWindow(200, 100, "test")
+			button := theme.CreateButton()
+			button.SetText("Button")
+			window.AddChild(button)
+			doubleClicks := 0
+			button.OnDoubleClick(func(gxui.MouseEvent) { doubleClicks++ })
+
+			p := math.Point{X: 5, Y: 5}
+			rec := &recording.Recording{Version: recording.Version, Size: window.Size(), Scale: 1}
+			for _, at := range []time.Duration{0, test.gap} {
+				rec.Events = append(rec.Events,
+					recording.Event{Time: at, Type: recording.MouseDown, Point: p, Button: gxui.MouseButtonLeft},
+					recording.Event{Time: at, Type: recording.MouseUp, Point: p, Button: gxui.MouseButtonLeft},
+				)
+			}
+			recording.Play(driver, window, rec).OnFinish(func() {
+				defer driver.Terminate()
+				if doubleClicks != test.doubleClicks {
+					t.Errorf("Clicks %v apart made %d double-clicks, expected %d",
+						test.gap, doubleClicks, test.doubleClicks)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// CanvasRasterizer is the interface implemented by Drivers that can rasterize
+// a completed Canvas into an image without presenting it in a Viewport.
+type CanvasRasterizer interface {
+	// RasterizeCanvas renders the completed Canvas c into a new image with
+	// pixelsPerDip pixels for each device-independent pixel of the canvas.
+	// The returned image has pre-multiplied alpha and is transparent wherever
+	// the canvas was not drawn.
+	RasterizeCanvas(c Canvas, pixelsPerDip float32) *image.RGBA
+}
+
+// RenderToImage lays out the control c no larger than maxSize, draws it and
+// returns the rasterized result. The control does not need to be attached to
+// a Window; if c is not attached then it is temporarily attached for the
+// duration of the call. scale is the ratio of pixels to DIPs, as used by
+// Window.Scale(), so a scale of 2 produces a @2x image.
+//
+// The theme's Driver must implement CanvasRasterizer and RenderToImage must
+// be called on the UI go-routine.
+func RenderToImage(theme Theme, c Control, maxSize math.Size, scale float32) *image.RGBA {
+	driver := theme.Driver()
+	driver.AssertUIGoroutine()
+
+	rasterizer, ok := driver.(CanvasRasterizer)
+	if !ok {
+		panic(fmt.Errorf("Driver %T does not implement CanvasRasterizer", driver))
+	}
+	if scale <= 0 {
+		panic(fmt.Errorf("RenderToImage scale must be positive. Got %v", scale))
+	}
+
+	if !c.Attached() {
+		c.Attach()
+		defer c.Detach()
+	}
+
+	oldSize := c.Size()
+	size := c.DesiredSize(math.ZeroSize, maxSize)
+	c.SetSize(size)
+	defer c.SetSize(oldSize)
+
+	canvas := c.Draw()
+	if canvas == nil {
+		// No area to draw in
+		w, h := size.ScaleS(scale).WH()
+		return image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+	return rasterizer.RasterizeCanvas(canvas, scale)
+}
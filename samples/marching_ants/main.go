@@ -0,0 +1,61 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/gl"
+	"github.com/robertt-smg/gxui/samples/flags"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+var selection = math.CreateRect(40, 40, 360, 260)
+
+// drawSelection draws a dashed selection marquee, with the dashes moved along
+// the outline by offset.
+func drawSelection(driver gxui.Driver, offset float32) gxui.Canvas {
+	canvas := driver.CreateCanvas(math.Size{W: 400, H: 300})
+	canvas.Clear(gxui.Gray90)
+
+	// The white pen fills the gaps between the black dashes.
+	canvas.DrawRect(selection, gxui.CreateBrush(gxui.Color{R: 0.3, G: 0.5, B: 1, A: 0.2}))
+	canvas.DrawRoundedRect(selection, 0, 0, 0, 0, gxui.WhitePen, gxui.TransparentBrush)
+	ants := gxui.CreateDashedPen(1, gxui.Black, 4)
+	ants.DashOffset = offset
+	canvas.DrawRoundedRect(selection, 0, 0, 0, 0, ants, gxui.TransparentBrush)
+
+	canvas.Complete()
+	return canvas
+}
+
+func appMain(driver gxui.Driver) {
+	theme := flags.CreateTheme(driver)
+
+	image := theme.CreateImage()
+	image.SetCanvas(drawSelection(driver, 0))
+
+	window := theme.CreateWindow(400, 300, "Marching ants")
+	window.SetScale(flags.DefaultScaleFactor)
+	window.AddChild(image)
+	window.OnClose(driver.Terminate)
+
+	offset := float32(0)
+	pause := time.Millisecond * 50
+	var timer *time.Timer
+	timer = time.AfterFunc(pause, func() {
+		driver.Call(func() {
+			offset++
+			image.SetCanvas(drawSelection(driver, offset))
+			timer.Reset(pause)
+		})
+	})
+}
+
+func main() {
+	gl.StartDriver(appMain)
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Shadow describes the drop shadow cast by a rectangle, and an optional blur
+// of the content behind it. Like a CSS box-shadow, the shadow is only drawn
+// outside of the rectangle, so it can be combined with translucent brushes.
+type Shadow struct {
+	// Color is the color of the shadow where it is not blurred.
+	Color Color
+
+	// Offset moves the shadow relative to the rectangle, in DIPs.
+	Offset math.Point
+
+	// Spread grows the shadow beyond the rectangle on all sides, in DIPs.
+	Spread float32
+
+	// Blur is the distance in DIPs over which the edge of the shadow fades.
+	// The shadow is blurred by a Gaussian with a standard deviation of half
+	// of Blur.
+	Blur float32
+
+	// CornerRadius is the radius of the rectangle's rounded corners.
+	CornerRadius float32
+
+	// BackdropBlur, if greater than zero, blurs the content behind the
+	// rectangle with a Gaussian of this standard deviation, in DIPs.
+	BackdropBlur float32
+}
+
+// ShadowCaster is the interface implemented by Controls that cast a Shadow
+// onto their parent. The parent draws the shadow beneath the control.
+type ShadowCaster interface {
+	Shadow() Shadow
+	SetShadow(Shadow)
+}
+
+// IsVisible returns true if drawing the shadow changes any pixels.
+func (s Shadow) IsVisible() bool {
+	return s.Color.A > 0 || s.BackdropBlur > 0
+}
+
+// Bounds returns the rectangle that holds the shadow cast by r.
+func (s Shadow) Bounds(r math.Rect) math.Rect {
+	return r.Offset(s.Offset).ExpandI(int(math.Ceilf(s.Spread + s.Blur*1.5)))
+}
+
+// AlphaAt returns the opacity, from 0 to 1, of the shadow cast by r at the
+// point p. Points and rectangles are in DIPs. AlphaAt is intended for
+// software Canvas implementations.
+func (s Shadow) AlphaAt(p math.Vec2, r math.Rect) float32 {
+	if roundedRectDistance(p, r, s.CornerRadius) < 0 {
+		return 0 // Inside the rectangle
+	}
+	radius := s.CornerRadius
+	if radius > 0 {
+		radius += s.Spread
+	}
+	d := roundedRectDistance(p.Sub(s.Offset.Vec2()), r, radius) - s.Spread
+	if s.Blur <= 0 {
+		if d <= 0 {
+			return 1
+		}
+		return 0
+	}
+	// The integral of a Gaussian with a standard deviation of Blur / 2.
+	sigma := s.Blur / 2
+	return 0.5 * math.Erfcf(d/(sigma*math.Sqrt2))
+}
+
+// roundedRectDistance returns the signed distance from p to the edge of the
+// rectangle r with corners rounded by radius, negative inside.
+func roundedRectDistance(p math.Vec2, r math.Rect, radius float32) float32 {
+	min, max := r.Min.Vec2(), r.Max.Vec2()
+	center := min.Add(max).MulS(0.5)
+	half := max.Sub(min).MulS(0.5)
+	radius = math.Clampf(radius, 0, math.Minf(half.X, half.Y))
+	q := p.Sub(center)
+	q = math.Vec2{X: math.Absf(q.X), Y: math.Absf(q.Y)}.Sub(half).Add(math.Vec2{X: radius, Y: radius})
+	outside := math.Vec2{X: math.Maxf(q.X, 0), Y: math.Maxf(q.Y, 0)}.Len()
+	inside := math.Minf(math.Maxf(q.X, q.Y), 0)
+	return outside + inside - radius
+}
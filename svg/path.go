@@ -0,0 +1,44 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/internal/shape"
+)
+
+// polygonPath returns the SVG path data for the closed polygon p.
+// Rounded vertices are written as arcs, using the same geometry as the
+// drivers' polygon tessellation.
+func polygonPath(p gxui.Polygon) string {
+	p = shape.PruneDuplicates(p)
+	if len(p) < 3 {
+		return ""
+	}
+	d := &bytes.Buffer{}
+	cmd := "M"
+	for i, cnt := 0, len(p); i < cnt; i++ {
+		a := p[i].Position.Vec2()
+		b := p[(i+cnt-1)%cnt].Position.Vec2()
+		c := p[(i+1)%cnt].Position.Vec2()
+		if arc, rounded := shape.RoundCorner(p[i].RoundedRadius, a, b, c); !rounded {
+			fmt.Fprintf(d, "%s%s %s ", cmd, number(a.X), number(a.Y))
+		} else {
+			sweep := 0
+			if arc.Clockwise {
+				sweep = 1
+			}
+			fmt.Fprintf(d, "%s%s %s A%s %s 0 0 %d %s %s ", cmd,
+				number(arc.Start.X), number(arc.Start.Y), number(arc.Radius), number(arc.Radius),
+				sweep, number(arc.End.X), number(arc.End.Y))
+		}
+		cmd = "L"
+	}
+	d.WriteString("Z")
+	return d.String()
+}
@@ -0,0 +1,407 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package svg writes the contents of a gxui.Canvas as a standalone SVG
+// document.
+//
+// Rectangles, rounded rectangles and polygons are written as paths with the
+// same corner rounding as the drivers, and Pens are stroked on the inside of
+// the shape, as the drivers draw them. Runes are written as <text> elements
+// using the font family reported by gxui.NamedFont, and textures are embedded
+// as PNG data URIs.
+package svg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	gomath "math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/internal/shape"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Write writes the completed canvas c to w as an SVG document.
+// c must have been created by a Driver whose canvases implement
+// displaylist.Recorder.
+func Write(w io.Writer, c gxui.Canvas) error {
+	if !c.IsComplete() {
+		return errors.New("Canvas must be completed before writing as SVG")
+	}
+	return WriteList(w, displaylist.FromCanvas(c))
+}
+
+// WriteList writes the display list l to w as an SVG document.
+// DrawTexture ops are only written if the TextureRef holds the Texture.
+func WriteList(w io.Writer, l *displaylist.List) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	width, height := l.Size.WH()
+	e.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	e.printf(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" `+
+		`version="1.1" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	e.list(l, l.Size.Rect())
+	e.printf("</svg>\n")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w      *bufio.Writer
+	err    error
+	nextID int
+}
+
+// state is the equivalent of the drivers' drawState, in the canvas's
+// coordinates.
+type state struct {
+	clip   math.Rect
+	groups int // Number of <g> elements opened by AddClip, PushTransform and PushLayer
+}
+
+func (e *encoder) printf(format string, args ...interface{}) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
+
+func (e *encoder) newID(prefix string) string {
+	e.nextID++
+	return fmt.Sprintf("%s%d", prefix, e.nextID)
+}
+
+func (e *encoder) closeGroups(n int) {
+	for i := 0; i < n; i++ {
+		e.printf("</g>\n")
+	}
+}
+
+func (e *encoder) list(l *displaylist.List, clip math.Rect) {
+	stack := []state{{clip: clip}}
+	for _, op := range l.Ops {
+		head := &stack[len(stack)-1]
+		switch op := op.(type) {
+		case displaylist.Push:
+			stack = append(stack, state{clip: head.clip})
+		case displaylist.PushTransform:
+			m := op.Transform
+			e.printf(`<g transform="matrix(%s %s %s %s %s %s)">`+"\n", number(m[0]), number(m[1]), number(m[3]), number(m[4]), number(m[6]), number(m[7]))
+			stack = append(stack, state{clip: untransform(m, head.clip), groups: 1})
+		case displaylist.PushLayer:
+			e.printf(`<g opacity="%s"%s>`+"\n", number(math.Saturate(op.Opacity)), blendStyle(op.Blend))
+			stack = append(stack, state{clip: head.clip, groups: 1})
+		case displaylist.Pop, displaylist.PopLayer:
+			e.closeGroups(head.groups)
+			stack = stack[:len(stack)-1]
+		case displaylist.AddClip:
+			id := e.newID("clip")
+			e.printf(`<clipPath id="%s">%s</clipPath>`+"\n", id, rectElement(op.Rect, ""))
+			e.printf(`<g clip-path="url(#%s)">`+"\n", id)
+			head.groups++
+			head.clip = intersect(head.clip, op.Rect)
+		case displaylist.Clear:
+			e.printf("%s\n", rectElement(head.clip, paint("fill", op.Color)))
+		case displaylist.DrawCanvas:
+			e.printf(`<g transform="translate(%d %d)">`+"\n", op.Offset.X, op.Offset.Y)
+			e.list(op.Canvas, head.clip.Offset(op.Offset.Neg()))
+			e.printf("</g>\n")
+		case displaylist.DrawRect:
+			if op.Brush.Color.A > 0 {
+				e.printf("%s\n", rectElement(op.Rect, e.fill(op.Brush, op.Rect)))
+			}
+		case displaylist.DrawRoundedRect:
+			r := op.Rect
+			if op.TL == 0 && op.TR == 0 && op.BL == 0 && op.BR == 0 && op.Pen.Color.A == 0 {
+				if op.Brush.Color.A > 0 {
+					e.printf("%s\n", rectElement(r, e.fill(op.Brush, r)))
+				}
+				break
+			}
+			p := gxui.Polygon{
+				gxui.PolygonVertex{Position: r.TL(), RoundedRadius: op.TL},
+				gxui.PolygonVertex{Position: r.TR(), RoundedRadius: op.TR},
+				gxui.PolygonVertex{Position: r.BR(), RoundedRadius: op.BR},
+				gxui.PolygonVertex{Position: r.BL(), RoundedRadius: op.BL},
+			}
+			e.polygon(p, op.Pen, op.Brush)
+		case displaylist.DrawPolygon:
+			e.polygon(op.Polygon, op.Pen, op.Brush)
+		case displaylist.DrawLines:
+			e.lines(op.Lines, op.Pen)
+		case displaylist.DrawRunes:
+			e.runes(op)
+		case displaylist.DrawTexture:
+			e.texture(op)
+		case displaylist.DrawShadow:
+			e.shadow(op.Rect, op.Shadow)
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		e.closeGroups(stack[i].groups)
+	}
+}
+
+// shadow writes the shadow s cast by r as a blurred rectangle, clipped to
+// outside of r. SVG has no equivalent of the backdrop blur, which is ignored.
+func (e *encoder) shadow(r math.Rect, s gxui.Shadow) {
+	if s.Color.A == 0 {
+		return
+	}
+	b := s.Bounds(r)
+	radius := float32(0)
+	if s.CornerRadius > 0 {
+		radius = s.CornerRadius + s.Spread
+	}
+	hole := polygonPath(gxui.Polygon{
+		gxui.PolygonVertex{Position: r.TL(), RoundedRadius: s.CornerRadius},
+		gxui.PolygonVertex{Position: r.TR(), RoundedRadius: s.CornerRadius},
+		gxui.PolygonVertex{Position: r.BR(), RoundedRadius: s.CornerRadius},
+		gxui.PolygonVertex{Position: r.BL(), RoundedRadius: s.CornerRadius},
+	})
+	clip := e.newID("clip")
+	e.printf(`<clipPath id="%s"><path d="M%d %dH%dV%dH%dZ %s" clip-rule="evenodd"/></clipPath>`+"\n",
+		clip, b.Min.X, b.Min.Y, b.Max.X, b.Max.Y, b.Min.X, hole)
+	filter := ""
+	if s.Blur > 0 {
+		id := e.newID("shadow")
+		e.printf(`<filter id="%s" filterUnits="userSpaceOnUse" x="%d" y="%d" width="%d" height="%d">`+
+			`<feGaussianBlur stdDeviation="%s"/></filter>`+"\n",
+			id, b.Min.X, b.Min.Y, b.W(), b.H(), number(s.Blur/2))
+		filter = fmt.Sprintf(` filter="url(#%s)"`, id)
+	}
+	e.printf(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s" %s%s clip-path="url(#%s)"/>`+"\n",
+		number(float32(r.Min.X+s.Offset.X)-s.Spread), number(float32(r.Min.Y+s.Offset.Y)-s.Spread),
+		number(float32(r.W())+s.Spread*2), number(float32(r.H())+s.Spread*2), number(radius),
+		paint("fill", s.Color), filter, clip)
+}
+
+func (e *encoder) polygon(p gxui.Polygon, pen gxui.Pen, brush gxui.Brush) {
+	d := polygonPath(p)
+	if d == "" {
+		return
+	}
+	if brush.Color.A > 0 {
+		e.printf(`<path d="%s" %s/>`+"\n", d, e.fill(brush, p.Bounds()))
+	}
+	if pen.Width > 0 && pen.Color.A > 0 {
+		// SVG strokes are centered on the path, whereas the drivers draw the
+		// pen inside the shape. Stroking at twice the width, clipped to the
+		// shape, produces the same result.
+		id := e.newID("clip")
+		e.printf(`<clipPath id="%s"><path d="%s"/></clipPath>`+"\n", id, d)
+		e.printf(`<path d="%s" fill="none" %s clip-path="url(#%s)"/>`+"\n",
+			d, stroke(pen, pen.Width*2), id)
+	}
+}
+
+func (e *encoder) lines(p gxui.Polygon, pen gxui.Pen) {
+	if pen.Width <= 0 || pen.Color.A == 0 {
+		return
+	}
+	// The drivers extrude lines to the side of the line's tangent. Offset the
+	// centered SVG stroke by half the pen width to match.
+	offset := shape.OffsetLine(p, pen.Width)
+	if len(offset) < 2 {
+		return
+	}
+	points := make([]string, len(offset))
+	for i, pt := range offset {
+		points[i] = number(pt.X) + "," + number(pt.Y)
+	}
+	e.printf(`<polyline points="%s" fill="none" %s/>`+"\n",
+		strings.Join(points, " "), stroke(pen, pen.Width))
+}
+
+// stroke returns the stroke attributes for drawing with pen at the given
+// stroke width.
+func stroke(pen gxui.Pen, width float32) string {
+	attrs := fmt.Sprintf(`%s stroke-width="%s"`, paint("stroke", pen.Color), number(width))
+	switch pen.Cap {
+	case gxui.RoundCap:
+		attrs += ` stroke-linecap="round"`
+	case gxui.SquareCap:
+		attrs += ` stroke-linecap="square"`
+	}
+	switch pen.Join {
+	case gxui.RoundJoin:
+		attrs += ` stroke-linejoin="round"`
+	case gxui.BevelJoin:
+		attrs += ` stroke-linejoin="bevel"`
+	}
+	if pen.IsDashed() {
+		dashes := make([]string, len(*pen.Dashes))
+		for i, l := range *pen.Dashes {
+			dashes[i] = number(math.Maxf(l, 0))
+		}
+		attrs += fmt.Sprintf(` stroke-dasharray="%s"`, strings.Join(dashes, ","))
+		if pen.DashOffset != 0 {
+			attrs += fmt.Sprintf(` stroke-dashoffset="%s"`, number(pen.DashOffset))
+		}
+	}
+	return attrs
+}
+
+func (e *encoder) runes(op displaylist.DrawRunes) {
+	if op.Color.A == 0 {
+		return
+	}
+	text := &bytes.Buffer{}
+	xs, ys := []string{}, []string{}
+	for i, r := range op.Runes {
+		if unicode.IsSpace(r) || i >= len(op.Points) {
+			continue // The drivers do not draw spaces
+		}
+		xml.EscapeText(text, []byte(string(r)))
+		xs = append(xs, strconv.Itoa(op.Points[i].X))
+		ys = append(ys, strconv.Itoa(op.Points[i].Y))
+	}
+	if len(xs) == 0 {
+		return
+	}
+	family := &bytes.Buffer{}
+	if op.Font.Family != "" {
+		fmt.Fprint(family, ` font-family="`)
+		xml.EscapeText(family, []byte(op.Font.Family))
+		fmt.Fprint(family, `"`)
+	}
+	e.printf(`<text x="%s" y="%s"%s font-size="%d" %s>%s</text>`+"\n",
+		strings.Join(xs, " "), strings.Join(ys, " "), family, op.Font.Size, paint("fill", op.Color), text)
+}
+
+func (e *encoder) texture(op displaylist.DrawTexture) {
+	t := op.Texture.Texture
+	if t == nil {
+		e.printf("<!-- DrawTexture: texture unavailable -->\n")
+		return
+	}
+	e.printf("%s\n", e.image(t, op.Rect, op.Texture.FlipY))
+}
+
+// image returns an <image> element drawing the texture t into r.
+func (e *encoder) image(t gxui.Texture, r math.Rect, flipY bool) string {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, t.Image()); err != nil {
+		if e.err == nil {
+			e.err = err
+		}
+		return ""
+	}
+	transform := ""
+	if flipY {
+		transform = fmt.Sprintf(` transform="matrix(1 0 0 -1 0 %d)"`, r.Min.Y+r.Max.Y)
+	}
+	return fmt.Sprintf(`<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="none"%s `+
+		`xlink:href="data:image/png;base64,%s"/>`,
+		r.Min.X, r.Min.Y, r.W(), r.H(), transform, base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// fill returns the SVG attributes for filling a shape with the given bounds
+// with brush. Gradients and patterns are written as elements referenced by
+// the returned attribute.
+func (e *encoder) fill(brush gxui.Brush, bounds math.Rect) string {
+	switch {
+	case brush.Gradient != nil:
+		g := brush.Gradient
+		id := e.newID("gradient")
+		// Gradient positions are relative to the shape's bounds, as are SVG's
+		// default objectBoundingBox gradient units.
+		if g.Kind == gxui.RadialGradient {
+			e.printf(`<radialGradient id="%s" cx="%s" cy="%s" r="%s">`, id,
+				number(g.Start.X), number(g.Start.Y), number(g.Radius))
+		} else {
+			e.printf(`<linearGradient id="%s" x1="%s" y1="%s" x2="%s" y2="%s">`, id,
+				number(g.Start.X), number(g.Start.Y), number(g.End.X), number(g.End.Y))
+		}
+		for _, s := range g.Stops {
+			e.printf(`<stop offset="%s" %s/>`, number(s.Offset), paint("stop-color", s.Color))
+		}
+		if g.Kind == gxui.RadialGradient {
+			e.printf("</radialGradient>\n")
+		} else {
+			e.printf("</linearGradient>\n")
+		}
+		return fmt.Sprintf(`fill="url(#%s)"`, id)
+	case brush.Pattern != nil && brush.Pattern.Texture != nil:
+		t := brush.Pattern.Texture
+		id := e.newID("pattern")
+		tile := t.Size().Rect()
+		e.printf(`<pattern id="%s" patternUnits="userSpaceOnUse" x="%d" y="%d" width="%d" height="%d">%s</pattern>`+"\n",
+			id, bounds.Min.X, bounds.Min.Y, tile.W(), tile.H(), e.image(t, tile, t.FlipY()))
+		return fmt.Sprintf(`fill="url(#%s)"`, id)
+	default:
+		return paint("fill", brush.Color)
+	}
+}
+
+func rectElement(r math.Rect, attrs string) string {
+	if attrs != "" {
+		attrs = " " + attrs
+	}
+	return fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d"%s/>`, r.Min.X, r.Min.Y, r.W(), r.H(), attrs)
+}
+
+// paint returns the SVG attributes for painting with the color c, where
+// property is "fill", "stroke" or "stop-color".
+func paint(property string, c gxui.Color) string {
+	c = c.Saturate()
+	s := fmt.Sprintf(`%s="#%.2x%.2x%.2x"`, property,
+		uint8(c.R*255+0.5), uint8(c.G*255+0.5), uint8(c.B*255+0.5))
+	if c.A < 1 {
+		s += fmt.Sprintf(` %s-opacity="%s"`, strings.TrimSuffix(property, "-color"), number(c.A))
+	}
+	return s
+}
+
+// number formats v rounded to 3 decimal places, hiding float32 noise.
+func number(v float32) string {
+	return strconv.FormatFloat(gomath.Round(float64(v)*1000)/1000, 'f', -1, 64)
+}
+
+// intersect returns the intersection of a and b. Unlike math.Rect.Intersect,
+// non-overlapping rectangles produce an empty rectangle.
+func intersect(a, b math.Rect) math.Rect {
+	r := math.CreateRect(
+		math.Max(a.Min.X, b.Min.X), math.Max(a.Min.Y, b.Min.Y),
+		math.Min(a.Max.X, b.Max.X), math.Min(a.Max.Y, b.Max.Y))
+	if r.Min.X > r.Max.X || r.Min.Y > r.Max.Y {
+		return math.Rect{}
+	}
+	return r
+}
+
+// blendStyle returns the style attribute for the blend mode b, or an empty
+// string for gxui.NormalBlend.
+func blendStyle(b gxui.BlendMode) string {
+	switch b {
+	case gxui.MultiplyBlend:
+		return ` style="mix-blend-mode:multiply"`
+	case gxui.ScreenBlend:
+		return ` style="mix-blend-mode:screen"`
+	case gxui.AdditiveBlend:
+		return ` style="mix-blend-mode:plus-lighter"`
+	default:
+		return ""
+	}
+}
+
+// untransform returns the bounding rectangle, in the coordinates before the
+// transform m, of the rectangle r. A singular m produces an empty rectangle.
+func untransform(m math.Mat3, r math.Rect) math.Rect {
+	if m[0]*m[4]-m[1]*m[3] == 0 {
+		return math.Rect{}
+	}
+	return m.Invert().TransformRect(r)
+}
@@ -0,0 +1,214 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type testTexture struct{ img image.Image }
+
+func (t testTexture) Image() image.Image    { return t.img }
+func (t testTexture) Size() math.Size       { return t.SizePixels() }
+func (t testTexture) SizePixels() math.Size { return math.Size{W: 2, H: 2} }
+func (t testTexture) FlipY() bool           { return false }
+func (t testTexture) SetFlipY(bool)         {}
+
+func writeTestCanvas(t *testing.T) string {
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.DrawRect(math.CreateRect(0, 0, 10, 10), gxui.CreateBrush(gxui.Red))
+	c.Push()
+	c.AddClip(math.CreateRect(0, 0, 20, 20))
+	c.DrawRoundedRect(math.CreateRect(0, 0, 20, 10), 2, 2, 2, 2, gxui.CreatePen(1, gxui.Gray40), gxui.TransparentBrush)
+	c.Pop()
+	c.DrawLines(gxui.Polygon{
+		{Position: math.Point{X: 0, Y: 15}},
+		{Position: math.Point{X: 40, Y: 15}},
+	}, gxui.CreatePen(2, gxui.White))
+	c.DrawTexture(testTexture{image.NewRGBA(image.Rect(0, 0, 2, 2))}, math.CreateRect(30, 0, 40, 10))
+	c.DisplayList().Ops = append(c.DisplayList().Ops, displaylist.DrawRunes{
+		Font:   displaylist.FontRef{Family: "Roboto", Size: 12},
+		Runes:  []rune("a <b"),
+		Points: []math.Point{{X: 1, Y: 18}, {X: 5, Y: 18}, {X: 9, Y: 18}, {X: 13, Y: 18}},
+		Color:  gxui.Color{R: 1, G: 1, B: 1, A: 0.5},
+	})
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWriteWellFormed(t *testing.T) {
+	doc := writeTestCanvas(t)
+	d := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Invalid XML: %v\n%s", err, doc)
+		}
+	}
+}
+
+func TestWriteElements(t *testing.T) {
+	doc := writeTestCanvas(t)
+	for _, expected := range []string{
+		`width="40" height="20" viewBox="0 0 40 20"`,
+		`<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>`,
+		`<clipPath id="clip1"><rect x="0" y="0" width="20" height="20"/></clipPath>`,
+		`<g clip-path="url(#clip1)">`,
+		`M0 2 A2 2 0 0 1 2 0 L18 0 A2 2 0 0 1 20 2 `,
+		`stroke="#666666" stroke-width="2"`,
+		`<polyline points="0,16 40,16" fill="none" stroke="#ffffff" stroke-width="2"`,
+		`xlink:href="data:image/png;base64,`,
+		`<text x="1 9 13" y="18 18 18" font-family="Roboto" font-size="12" fill="#ffffff" fill-opacity="0.5">a&lt;b</text>`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %s\n%s", expected, doc)
+		}
+	}
+}
+
+func TestWriteGradients(t *testing.T) {
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.DrawRect(math.CreateRect(0, 0, 10, 10), gxui.CreateLinearGradientBrush(
+		math.Vec2{X: 0, Y: 0}, math.Vec2{X: 0, Y: 1},
+		gxui.GradientStop{Offset: 0, Color: gxui.Red},
+		gxui.GradientStop{Offset: 1, Color: gxui.Color{B: 1, A: 0.5}},
+	))
+	c.DrawRoundedRect(math.CreateRect(10, 0, 20, 10), 2, 2, 2, 2, gxui.TransparentPen,
+		gxui.CreateRadialGradientBrush(math.Vec2{X: 0.5, Y: 0.5}, 0.5,
+			gxui.GradientStop{Offset: 0, Color: gxui.White},
+			gxui.GradientStop{Offset: 1, Color: gxui.Black},
+		))
+	c.DrawRect(math.CreateRect(20, 0, 30, 10), gxui.CreatePatternBrush(testTexture{image.NewRGBA(image.Rect(0, 0, 2, 2))}))
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+	for _, expected := range []string{
+		`<linearGradient id="gradient1" x1="0" y1="0" x2="0" y2="1">` +
+			`<stop offset="0" stop-color="#ff0000"/><stop offset="1" stop-color="#0000ff" stop-opacity="0.5"/></linearGradient>`,
+		`<rect x="0" y="0" width="10" height="10" fill="url(#gradient1)"/>`,
+		`<radialGradient id="gradient2" cx="0.5" cy="0.5" r="0.5">`,
+		`fill="url(#gradient2)"`,
+		`<pattern id="pattern3" patternUnits="userSpaceOnUse" x="20" y="0" width="2" height="2"><image `,
+		`<rect x="20" y="0" width="10" height="10" fill="url(#pattern3)"/>`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %s\n%s", expected, doc)
+		}
+	}
+}
+
+func TestWritePenStyles(t *testing.T) {
+	dashed := gxui.CreateDashedPen(1, gxui.Black, 4, 2)
+	dashed.DashOffset = 3
+	dashed.Join = gxui.BevelJoin
+	rounded := gxui.CreatePen(2, gxui.Black)
+	rounded.Cap, rounded.Join = gxui.RoundCap, gxui.RoundJoin
+
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.DrawRoundedRect(math.CreateRect(0, 0, 10, 10), 0, 0, 0, 0, dashed, gxui.TransparentBrush)
+	c.DrawLines(gxui.Polygon{
+		{Position: math.Point{X: 0, Y: 15}},
+		{Position: math.Point{X: 40, Y: 15}},
+	}, rounded)
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+	for _, expected := range []string{
+		`stroke="#000000" stroke-width="2" stroke-linejoin="bevel" stroke-dasharray="4,2" stroke-dashoffset="3"`,
+		`stroke="#000000" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %s\n%s", expected, doc)
+		}
+	}
+}
+
+func TestWriteTransforms(t *testing.T) {
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.PushTransform(math.CreateMat3Scale(math.Vec2{X: 2, Y: 2}).Mul(math.CreateMat3Translate(math.Vec2{X: 5})))
+	c.DrawRect(math.CreateRect(0, 0, 10, 10), gxui.CreateBrush(gxui.Red))
+	c.Pop()
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+	expected := `<g transform="matrix(2 0 0 2 5 0)">` + "\n" +
+		`<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>` + "\n" +
+		`</g>`
+	if !strings.Contains(doc, expected) {
+		t.Errorf("Expected document to contain %s\n%s", expected, doc)
+	}
+}
+
+func TestWriteLayers(t *testing.T) {
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.PushLayer(0.25, gxui.MultiplyBlend)
+	c.DrawRect(math.CreateRect(0, 0, 10, 10), gxui.CreateBrush(gxui.Red))
+	c.PopLayer()
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+	expected := `<g opacity="0.25" style="mix-blend-mode:multiply">` + "\n" +
+		`<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>` + "\n" +
+		`</g>`
+	if !strings.Contains(doc, expected) {
+		t.Errorf("Expected document to contain %s\n%s", expected, doc)
+	}
+}
+
+func TestWriteShadow(t *testing.T) {
+	c := displaylist.NewCanvas(math.Size{W: 40, H: 20})
+	c.DrawShadow(math.CreateRect(10, 5, 30, 15), gxui.Shadow{Color: gxui.Black, Offset: math.Point{Y: 2}, Blur: 4})
+	c.Complete()
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+	for _, expected := range []string{
+		`<path d="M4 1H36V23H4Z M10 5`,
+		`clip-rule="evenodd"`,
+		`<filter id="shadow2" filterUnits="userSpaceOnUse" x="4" y="1" width="32" height="22"><feGaussianBlur stdDeviation="2"/></filter>`,
+		`<rect x="10" y="7" width="20" height="10" rx="0" fill="#000000" filter="url(#shadow2)" clip-path="url(#clip1)"/>`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("Expected document to contain %s\n%s", expected, doc)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+// Testable is the optional interface implemented by controls that can be
+// tagged with an identifier, so that UI tests can find them without depending
+// on the layout or displayed text. The identifier has no effect on the
+// behaviour or appearance of the control.
+type Testable interface {
+	// TestID returns the identifier set with SetTestID, or an empty string if
+	// no identifier has been set.
+	TestID() string
+
+	// SetTestID sets the identifier of the control.
+	SetTestID(id string)
+}
+
+// Texter is the interface implemented by controls that display a text, such
+// as Labels, Buttons and TextBoxes. UI tests and automation clients find and
+// inspect controls by their text.
+type Texter interface {
+	Text() string
+}
+
+// ItemSelector is the interface implemented by controls that select an
+// item, such as Lists, DropDownLists and Trees.
+type ItemSelector interface {
+	Selected() AdapterItem
+}
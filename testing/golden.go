@@ -0,0 +1,208 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldensEnv is the environment variable that, when set to a value
+// other than "" or "0", makes AssertGolden write the actual image to the
+// golden file instead of comparing against it:
+//
+//	GXUI_UPDATE_GOLDENS=1 go test ./themes/...
+const UpdateGoldensEnv = "GXUI_UPDATE_GOLDENS"
+
+func updateGoldens() bool {
+	v := os.Getenv(UpdateGoldensEnv)
+	return v != "" && v != "0"
+}
+
+// GoldenOptions controls how closely an image must match a golden image.
+type GoldenOptions struct {
+	// Tolerance is the largest difference allowed in any 8-bit color or alpha
+	// channel of a pixel before the pixel is counted as different.
+	Tolerance uint8
+
+	// PerceptualThreshold, if greater than zero, allows a pixel to exceed
+	// Tolerance if its perceptual difference is no more than the threshold.
+	// The perceptual difference is a weighted distance in the YIQ color space,
+	// see PerceptualDifference.
+	PerceptualThreshold float64
+
+	// MaxDiffPixels is the number of different pixels allowed before the
+	// images are considered not to match.
+	MaxDiffPixels int
+}
+
+// CompareImages compares the images expected and actual, which must be of the
+// same size, returning the number of different pixels and an image
+// highlighting them. Different pixels are red in the diff image, pixels that
+// differ but are within the tolerance of opts are yellow, and all other pixels
+// are a faded gray version of expected.
+func CompareImages(expected, actual image.Image, opts GoldenOptions) (diff *image.RGBA, count int) {
+	eb, ab := expected.Bounds(), actual.Bounds()
+	if eb.Size() != ab.Size() {
+		panic(fmt.Errorf("CompareImages requires images of the same size. Got %v and %v", eb.Size(), ab.Size()))
+	}
+	diff = image.NewRGBA(image.Rect(0, 0, eb.Dx(), eb.Dy()))
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			e := color.NRGBAModel.Convert(expected.At(eb.Min.X+x, eb.Min.Y+y)).(color.NRGBA)
+			a := color.NRGBAModel.Convert(actual.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			switch {
+			case e == a:
+				diff.Set(x, y, faded(e))
+			case channelDifference(e, a) <= opts.Tolerance,
+				opts.PerceptualThreshold > 0 && PerceptualDifference(e, a) <= opts.PerceptualThreshold:
+				diff.Set(x, y, color.RGBA{R: 255, G: 255, A: 255})
+			default:
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				count++
+			}
+		}
+	}
+	return diff, count
+}
+
+// PerceptualDifference returns the perceptual difference between the colors
+// a and b, from 0 for identical colors to 1 for the most different colors,
+// with black and white about 0.97 apart. Both colors are blended onto white
+// before being compared, so transparent colors are similar to white.
+func PerceptualDifference(a, b color.Color) float64 {
+	ya, ia, qa := yiq(a)
+	yb, ib, qb := yiq(b)
+	dy, di, dq := ya-yb, ia-ib, qa-qb
+	// Weights from "Measuring perceived color difference using YIQ NTSC
+	// transmission color space in mobile applications" by Y. Kotsarenko and
+	// F. Ramos. maxDelta is the largest weighted distance between two colors.
+	const maxDelta = 35215
+	delta := 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+	return math.Min(math.Sqrt(delta/maxDelta), 1)
+}
+
+// AssertGolden fails the test if the image actual does not match the PNG
+// image stored at path, within the tolerances of opts. On failure, the actual
+// image and the diff image returned by CompareImages are written next to the
+// golden image, with the extensions ".actual.png" and ".diff.png".
+//
+// When the UpdateGoldensEnv environment variable is set, AssertGolden instead
+// writes actual to path, creating or replacing the golden image.
+func AssertGolden(t *testing.T, path string, actual image.Image, opts GoldenOptions) {
+	t.Helper()
+	if updateGoldens() {
+		if err := writePNG(path, actual); err != nil {
+			t.Fatalf("Failed to update golden image: %v", err)
+		}
+		t.Logf("Updated golden image %s", path)
+		return
+	}
+
+	if err := compareGolden(path, actual, opts); err != nil {
+		t.Error(err)
+	}
+}
+
+// compareGolden returns an error if actual does not match the golden image at
+// path, writing the actual and diff images as described by AssertGolden.
+func compareGolden(path string, actual image.Image, opts GoldenOptions) error {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	actualPath, diffPath := base+".actual.png", base+".diff.png"
+
+	expected, err := readPNG(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read golden image: %v\nRun the test with %s=1 to create it.", err, UpdateGoldensEnv)
+	}
+	if es, as := expected.Bounds().Size(), actual.Bounds().Size(); es != as {
+		writePNG(actualPath, actual)
+		return fmt.Errorf("Image size was %v, golden image %s has size %v. Actual image written to %s",
+			as, path, es, actualPath)
+	}
+
+	diff, count := CompareImages(expected, actual, opts)
+	if count > opts.MaxDiffPixels {
+		writePNG(actualPath, actual)
+		writePNG(diffPath, diff)
+		return fmt.Errorf("%d pixels differ from golden image %s, expected no more than %d. Actual image written to %s, diff written to %s",
+			count, path, opts.MaxDiffPixels, actualPath, diffPath)
+	}
+
+	// Remove the output of any previous failure.
+	os.Remove(actualPath)
+	os.Remove(diffPath)
+	return nil
+}
+
+func channelDifference(a, b color.NRGBA) uint8 {
+	d := absDiff(a.R, b.R)
+	if v := absDiff(a.G, b.G); v > d {
+		d = v
+	}
+	if v := absDiff(a.B, b.B); v > d {
+		d = v
+	}
+	if v := absDiff(a.A, b.A); v > d {
+		d = v
+	}
+	return d
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// yiq returns the YIQ components of c blended onto white, with 8-bit ranges.
+func yiq(c color.Color) (y, i, q float64) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	alpha := float64(n.A) / 255
+	blend := func(v uint8) float64 { return 255 + (float64(v)-255)*alpha }
+	r, g, b := blend(n.R), blend(n.G), blend(n.B)
+	y = 0.29889531*r + 0.58662247*g + 0.11448223*b
+	i = 0.59597799*r - 0.27417610*g - 0.32180189*b
+	q = 0.21147017*r - 0.52261711*g + 0.31114694*b
+	return y, i, q
+}
+
+// faded returns c as a light gray, used for the unchanged pixels of a diff.
+func faded(c color.NRGBA) color.RGBA {
+	y, _, _ := yiq(c)
+	v := uint8(255 + (y-255)*0.1)
+	return color.RGBA{R: v, G: v, B: v, A: 255}
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	gray := color.NRGBA{R: 100, G: 100, B: 100, A: 255}
+	expected := solidImage(4, 4, gray)
+	actual := solidImage(4, 4, gray)
+	actual.Set(0, 0, color.NRGBA{R: 103, G: 100, B: 100, A: 255}) // Slightly off
+	actual.Set(1, 0, color.NRGBA{R: 255, G: 0, B: 0, A: 255})     // Very different
+
+	for _, test := range []struct {
+		name     string
+		opts     GoldenOptions
+		expected int
+	}{
+		{"exact", GoldenOptions{}, 2},
+		{"tolerance", GoldenOptions{Tolerance: 3}, 1},
+		{"perceptual", GoldenOptions{PerceptualThreshold: 0.05}, 1},
+		{"loose perceptual", GoldenOptions{PerceptualThreshold: 1}, 0},
+	} {
+		diff, count := CompareImages(expected, actual, test.opts)
+		if count != test.expected {
+			t.Errorf("%s: count was %d, expected %d", test.name, count, test.expected)
+		}
+		if got := diff.RGBAAt(1, 0); count > 0 && got != (color.RGBA{R: 255, A: 255}) {
+			t.Errorf("%s: diff of the different pixel was %v, expected red", test.name, got)
+		}
+	}
+}
+
+func TestPerceptualDifference(t *testing.T) {
+	black, white := color.Black, color.White
+	if d := PerceptualDifference(black, black); d != 0 {
+		t.Errorf("Difference between identical colors was %v, expected 0", d)
+	}
+	if d := PerceptualDifference(black, white); d < 0.9 || d > 1 {
+		t.Errorf("Difference between black and white was %v, expected about 0.97", d)
+	}
+	if d := PerceptualDifference(color.Transparent, white); d != 0 {
+		t.Errorf("Difference between transparent and white was %v, expected 0", d)
+	}
+}
+
+func TestCompareGoldenWritesDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "golden.png")
+	if err := writePNG(path, solidImage(2, 2, color.White)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compareGolden(path, solidImage(2, 2, color.Black), GoldenOptions{}); err == nil {
+		t.Error("Expected the comparison to fail")
+	}
+	for _, name := range []string{"golden.actual.png", "golden.diff.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+
+	if err := compareGolden(path, solidImage(2, 2, color.White), GoldenOptions{}); err != nil {
+		t.Error(err)
+	}
+	for _, name := range []string{"golden.actual.png", "golden.diff.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed after a match", name)
+		}
+	}
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.png")
+	t.Setenv(UpdateGoldensEnv, "1")
+	AssertGolden(t, path, solidImage(2, 2, color.Black), GoldenOptions{})
+
+	t.Setenv(UpdateGoldensEnv, "0")
+	if err := compareGolden(path, solidImage(2, 2, color.Black), GoldenOptions{}); err != nil {
+		t.Errorf("Expected the updated golden image to match: %v", err)
+	}
+}
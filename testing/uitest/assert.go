@@ -0,0 +1,94 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uitest
+
+import (
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+)
+
+// AssertText fails the test if the text of c, which must have a Text method
+// such as a Label, Button or TextBox, is not expected.
+func (h *Harness) AssertText(c gxui.Control, expected string) {
+	h.T.Helper()
+	h.checkNotNil(c, "AssertText")
+	t, ok := c.(gxui.Texter)
+	if !ok {
+		h.T.Fatalf("AssertText: %T has no Text method", c)
+	}
+	var got string
+	h.Do(func() { got = t.Text() })
+	if got != expected {
+		h.T.Errorf("Text of %s was %q, expected %q", gxui.Path(c), got, expected)
+	}
+}
+
+// AssertSelected fails the test if the selected item of c, which must have a
+// Selected method such as a List, DropDownList or Tree, is not expected.
+func (h *Harness) AssertSelected(c gxui.Control, expected gxui.AdapterItem) {
+	h.T.Helper()
+	h.checkNotNil(c, "AssertSelected")
+	s, ok := c.(gxui.ItemSelector)
+	if !ok {
+		h.T.Fatalf("AssertSelected: %T has no Selected method", c)
+	}
+	var got gxui.AdapterItem
+	h.Do(func() { got = s.Selected() })
+	if got != expected {
+		h.T.Errorf("Selected item of %s was %v, expected %v", gxui.Path(c), got, expected)
+	}
+}
+
+// AssertVisible fails the test if the visibility of c is not expected. A
+// control is visible if it is attached to the window and neither it nor any of
+// its ancestors have been hidden with SetVisible.
+func (h *Harness) AssertVisible(c gxui.Control, expected bool) {
+	h.T.Helper()
+	h.checkNotNil(c, "AssertVisible")
+	var got bool
+	h.Do(func() { got = isVisible(c) })
+	if got != expected {
+		h.T.Errorf("Visibility of %s was %v, expected %v", gxui.Path(c), got, expected)
+	}
+}
+
+// AssertFocused fails the test if c does not have the window's focus. If c is
+// nil then AssertFocused fails if any control has focus.
+func (h *Harness) AssertFocused(c gxui.Control) {
+	h.T.Helper()
+	var focus gxui.Focusable
+	h.Do(func() { focus = h.Window.Focus() })
+	switch {
+	case c == nil && focus != nil:
+		h.T.Errorf("Focus was %s, expected no focus", gxui.Path(focus))
+	case c != nil && focus == nil:
+		h.T.Errorf("Focus was nil, expected %s", gxui.Path(c))
+	case c != nil && gxui.Control(focus) != c:
+		h.T.Errorf("Focus was %s, expected %s", gxui.Path(focus), gxui.Path(c))
+	}
+}
+
+// AssertCursor fails the test if the shape of the mouse cursor displayed by
+// the window is not expected.
+func (h *Harness) AssertCursor(expected gxui.CursorShape) {
+	h.T.Helper()
+	got := h.Window.Viewport().(fake.Viewport).Cursor().Shape
+	if got != expected {
+		h.T.Errorf("Cursor was %v, expected %v", got, expected)
+	}
+}
+
+func isVisible(c gxui.Control) bool {
+	for {
+		if !c.Attached() || !c.IsVisible() {
+			return false
+		}
+		parent, ok := c.Parent().(gxui.Control)
+		if !ok {
+			return true
+		}
+		c = parent
+	}
+}
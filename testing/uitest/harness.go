@@ -0,0 +1,209 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package uitest provides a harness for testing gxui user interfaces.
+//
+// A Harness runs a window on the fake driver, letting the test build controls,
+// find them by type, text or test identifier, simulate clicks and typing, and
+// assert on the resulting state:
+//
+//	h := uitest.Start(t, 400, 300)
+//	defer h.Close()
+//	h.Do(func() {
+//		button := h.Theme.CreateButton()
+//		button.SetText("OK")
+//		button.(gxui.Testable).SetTestID("ok")
+//		h.Window.AddChild(button)
+//	})
+//	h.Click(h.FindByTestID("ok"))
+//
+// All harness methods are called from the test go-routine. They run their
+// work on the driver's UI go-routine with Driver.CallSync, and then wait for
+// the driver's call queue to drain before returning, so the effects of every
+// queued call, such as relayouts, are visible to the next method.
+//
+// uitest is separate from the gxui/testing package as the latter is used by
+// the tests of the gxui package itself.
+package uitest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/themes/dark"
+)
+
+// Harness holds a window running on a fake driver.
+type Harness struct {
+	T      testing.TB
+	Driver gxui.Driver
+	Theme  gxui.Theme
+	Window gxui.Window
+
+	done  chan struct{}
+	clock *gxui.InputClock // The window's input clock, only used on the UI go-routine
+}
+
+// epoch is the time of the window's input clock when the harness starts.
+var epoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Start starts a fake driver and creates a window of the given size using
+// the dark theme. Close must be called to terminate the driver once the test
+// is done with the harness.
+func Start(t testing.TB, width, height int) *Harness {
+	h := &Harness{T: t, done: make(chan struct{})}
+	ready := make(chan struct{})
+	go func() {
+		defer close(h.done)
+		fake.StartDriver(func(driver gxui.Driver) {
+			h.Driver = driver
+			h.Theme = dark.CreateTheme(driver)
+			h.Window = h.Theme.CreateWindow(width, height, t.Name())
+			h.clock = gxui.CreateInputClock(func() time.Time { return epoch })
+			h.Window.SetInputClock(h.clock.Now)
+			close(ready)
+		})
+	}()
+	<-ready
+	h.Drain()
+	return h
+}
+
+// Close terminates the driver, blocking until it has stopped.
+func (h *Harness) Close() {
+	h.Driver.Terminate()
+	<-h.done
+}
+
+// Do calls f on the UI go-routine, then waits for the call queue to drain.
+func (h *Harness) Do(f func()) {
+	if !h.Driver.CallSync(f) {
+		h.T.Fatal("The driver has been terminated")
+	}
+	h.Drain()
+}
+
+// Drain blocks until the driver has run all of its queued calls, including
+// any calls queued by those calls.
+func (h *Harness) Drain() {
+	done := make(chan struct{})
+	if h.Driver.CallWhenIdle(func() { close(done) }) {
+		<-done
+	}
+}
+
+// Find returns the first control in the window, in depth-first order, for
+// which test returns true, or nil if there is no such control.
+func (h *Harness) Find(test func(gxui.Control) bool) gxui.Control {
+	var found gxui.Control
+	h.Do(func() { found = gxui.FindControl(h.Window, test) })
+	return found
+}
+
+// FindByType finds the first control in the window that is assignable to the
+// value pointed to by target, and stores it in target. FindByType returns
+// false if there is no such control. target must be a non-nil pointer to a
+// variable of an interface or concrete control type, for example:
+//
+//	var list gxui.List
+//	if !h.FindByType(&list) { ... }
+func (h *Harness) FindByType(target interface{}) bool {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		h.T.Fatalf("FindByType target must be a non-nil pointer, got %T", target)
+	}
+	ty := v.Type().Elem()
+	c := h.Find(func(c gxui.Control) bool {
+		return reflect.TypeOf(c).AssignableTo(ty)
+	})
+	if c == nil {
+		return false
+	}
+	v.Elem().Set(reflect.ValueOf(c))
+	return true
+}
+
+// FindByText returns the first control in the window with a Text method that
+// returns text, such as a Label, Button or TextBox, or nil if there is no such
+// control.
+func (h *Harness) FindByText(text string) gxui.Control {
+	return h.Find(func(c gxui.Control) bool {
+		t, ok := c.(gxui.Texter)
+		return ok && t.Text() == text
+	})
+}
+
+// FindByTestID returns the first control in the window with the test
+// identifier id, or nil if there is no such control.
+func (h *Harness) FindByTestID(id string) gxui.Control {
+	var found gxui.Control
+	h.Do(func() { found = gxui.FindControlByTestID(h.Window, id) })
+	return found
+}
+
+// Click simulates a left mouse button click at the center of c.
+func (h *Harness) Click(c gxui.Control) {
+	h.T.Helper()
+	h.checkNotNil(c, "Click")
+	h.Do(func() {
+		h.clock.Click()
+		gxui.ClickControl(c)
+	})
+}
+
+// DoubleClick simulates a left mouse button double-click at the center of c.
+func (h *Harness) DoubleClick(c gxui.Control) {
+	h.T.Helper()
+	h.checkNotNil(c, "DoubleClick")
+	h.Do(func() {
+		h.clock.Click()
+		gxui.DoubleClickControl(c)
+	})
+}
+
+// Drag simulates dragging with the left mouse button from the center of the
+// control from to the center of the control to.
+func (h *Harness) Drag(from, to gxui.Control) {
+	h.T.Helper()
+	h.checkNotNil(from, "Drag")
+	h.checkNotNil(to, "Drag")
+	h.Do(func() {
+		h.clock.Click()
+		p := gxui.TransformCoordinate(to.Size().Rect().Mid(), to, from)
+		gxui.DragControl(from, from.Size().Rect().Mid(), p)
+	})
+}
+
+// MoveMouse simulates moving the mouse over the center of c.
+func (h *Harness) MoveMouse(c gxui.Control) {
+	h.T.Helper()
+	h.checkNotNil(c, "MoveMouse")
+	h.Do(func() { gxui.MoveMouseTo(c, c.Size().Rect().Mid()) })
+}
+
+// Type simulates typing text into the control with focus.
+func (h *Harness) Type(text string) {
+	h.Do(func() { gxui.TypeText(h.Window, text) })
+}
+
+// Compose simulates an input method composing text in the control with
+// focus, displaying each of preedit in turn before committing commit.
+func (h *Harness) Compose(commit string, preedit ...string) {
+	h.Do(func() { gxui.ComposeText(h.Window, commit, preedit...) })
+}
+
+// PressKey simulates pressing and releasing key with the control with focus.
+func (h *Harness) PressKey(key gxui.KeyboardKey, modifier gxui.KeyboardModifier) {
+	h.Do(func() { gxui.PressKey(h.Window, key, modifier) })
+}
+
+func (h *Harness) checkNotNil(c gxui.Control, op string) {
+	h.T.Helper()
+	if c == nil {
+		h.T.Fatalf("%s: control was nil", op)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uitest
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+)
+
+// createForm adds a name text box, a greet button, a greeting label and a
+// list of colors to the harness window.
+func createForm(h *Harness) {
+	h.Do(func() {
+		name := h.Theme.CreateTextBox()
+		name.SetDesiredWidth(100)
+		name.(gxui.Testable).SetTestID("name")
+
+		greeting := h.Theme.CreateLabel()
+		greeting.(gxui.Testable).SetTestID("greeting")
+
+		greet := h.Theme.CreateButton()
+		greet.SetText("Greet")
+		greet.OnClick(func(gxui.MouseEvent) {
+			greeting.SetText("Hello " + name.Text())
+		})
+
+		adapter := gxui.CreateDefaultAdapter()
+		adapter.SetItems([]string{"red", "green", "blue"})
+		colors := h.Theme.CreateList()
+		colors.SetAdapter(adapter)
+
+		layout := h.Theme.CreateLinearLayout()
+		layout.AddChild(name)
+		layout.AddChild(greet)
+		layout.AddChild(greeting)
+		layout.AddChild(colors)
+		h.Window.AddChild(layout)
+	})
+}
+
+func TestFind(t *testing.T) {
+	h := Start(t, 400, 300)
+	defer h.Close()
+	createForm(h)
+
+	var list gxui.List
+	if !h.FindByType(&list) {
+		t.Fatal("Expected to find a List")
+	}
+	var button gxui.Button
+	if !h.FindByType(&button) {
+		t.Fatal("Expected to find a Button")
+	}
+	if c := h.FindByText("Greet"); c != button {
+		t.Errorf("FindByText returned %v, expected the button", c)
+	}
+	if _, ok := h.FindByTestID("name").(gxui.TextBox); !ok {
+		t.Errorf("FindByTestID returned %T, expected a TextBox", h.FindByTestID("name"))
+	}
+	if c := h.FindByTestID("missing"); c != nil {
+		t.Errorf("FindByTestID returned %v, expected nil", c)
+	}
+	var progress gxui.ProgressBar
+	if h.FindByType(&progress) {
+		t.Error("Did not expect to find a ProgressBar")
+	}
+}
+
+func TestClickAndType(t *testing.T) {
+	h := Start(t, 400, 300)
+	defer h.Close()
+	createForm(h)
+
+	name := h.FindByTestID("name")
+	h.AssertFocused(nil)
+	h.Click(name)
+	h.AssertFocused(name)
+	h.Type("world")
+	h.PressKey(gxui.KeyEnd, gxui.ModNone)
+	h.Type("!")
+	h.AssertText(name, "world!")
+
+	h.Click(h.FindByText("Greet"))
+	h.AssertText(h.FindByTestID("greeting"), "Hello world!")
+}
+
+func TestSelectedAndVisible(t *testing.T) {
+	h := Start(t, 400, 300)
+	defer h.Close()
+	createForm(h)
+
+	var list gxui.List
+	h.FindByType(&list)
+	h.AssertSelected(list, nil)
+	h.Click(h.FindByText("green"))
+	h.AssertSelected(list, "green")
+
+	greeting := h.FindByTestID("greeting")
+	h.AssertVisible(greeting, true)
+	h.Do(func() { greeting.Parent().(gxui.Control).SetVisible(false) })
+	h.AssertVisible(greeting, false)
+	h.Do(func() { greeting.Parent().(gxui.Control).SetVisible(true) })
+	h.AssertVisible(greeting, true)
+	h.Do(func() { greeting.Parent().(gxui.Container).RemoveChild(greeting) })
+	h.AssertVisible(greeting, false)
+}
+
+func TestDoubleClick(t *testing.T) {
+	h := Start(t, 400, 300)
+	defer h.Close()
+
+	clicks, doubleClicks := 0, 0
+	h.Do(func() {
+		button := h.Theme.CreateButton()
+		button.SetText("Press")
+		button.OnClick(func(gxui.MouseEvent) { clicks++ })
+		button.OnDoubleClick(func(gxui.MouseEvent) { doubleClicks++ })
+		h.Window.AddChild(button)
+	})
+	button := h.FindByText("Press")
+	h.Click(button)
+	h.Click(button)
+	h.DoubleClick(button)
+	if clicks != 3 || doubleClicks != 1 {
+		t.Errorf("Got %d clicks and %d double-clicks, expected 3 and 1", clicks, doubleClicks)
+	}
+}
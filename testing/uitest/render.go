@@ -0,0 +1,85 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uitest
+
+import (
+	"image"
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	test "github.com/robertt-smg/gxui/testing"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+// Render starts the software driver, creates a theme with createTheme and
+// returns the image of the control returned by create, as rendered by
+// gxui.RenderToImage. The control is laid out no larger than maxSize, and
+// scale is the ratio of pixels to DIPs.
+//
+// Render does not require a GPU, so its output can be compared against golden
+// images with AssertGolden from the gxui/testing package on any machine:
+//
+//	img := uitest.Render(dark.CreateTheme, func(theme gxui.Theme) gxui.Control {
+//		label := theme.CreateLabel()
+//		label.SetText("Hello")
+//		return label
+//	}, math.MaxSize, 1)
+//	test.AssertGolden(t, "testdata/label.png", img, test.GoldenOptions{})
+func Render(createTheme func(gxui.Driver) gxui.Theme, create func(theme gxui.Theme) gxui.Control, maxSize math.Size, scale float32) *image.RGBA {
+	var img *image.RGBA
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+		theme := createTheme(driver)
+		img = gxui.RenderToImage(theme, create(theme), maxSize, scale)
+	})
+	return img
+}
+
+// CreateForm returns a layout holding one of each of the commonly used
+// controls, with a fixed state. It is the fixture of AssertFormGolden.
+func CreateForm(theme gxui.Theme) gxui.Control {
+	label := theme.CreateLabel()
+	label.SetText("Label")
+
+	button := theme.CreateButton()
+	button.SetText("Button")
+
+	checked := theme.CreateButton()
+	checked.SetType(gxui.ToggleButton)
+	checked.SetChecked(true)
+	checked.SetText("Checked")
+
+	textBox := theme.CreateTextBox()
+	textBox.SetDesiredWidth(100)
+	textBox.SetText("Text box")
+
+	progress := theme.CreateProgressBar()
+	progress.SetDesiredSize(math.Size{W: 100, H: 10})
+	progress.SetTarget(100)
+	progress.SetProgress(40)
+
+	layout := theme.CreateLinearLayout()
+	layout.AddChild(label)
+	layout.AddChild(button)
+	layout.AddChild(checked)
+	layout.AddChild(textBox)
+	layout.AddChild(progress)
+	return layout
+}
+
+// AssertFormGolden renders the form returned by CreateForm with the theme
+// created by createTheme, and fails the test if the image does not match the
+// golden image at path. It is used by the snapshot tests of the themes:
+//
+//	func TestGoldenForm(t *testing.T) {
+//		uitest.AssertFormGolden(t, dark.CreateTheme, "testdata/form.png")
+//	}
+func AssertFormGolden(t *testing.T, createTheme func(gxui.Driver) gxui.Theme, path string) {
+	t.Helper()
+	img := Render(createTheme, CreateForm, math.Size{W: 200, H: 200}, 1)
+	test.AssertGolden(t, path, img, test.GoldenOptions{Tolerance: 2})
+}
@@ -62,27 +62,20 @@ func (t *TextBoxController) updateSelectionsForEdits(edits []TextBoxEdit) {
 	var selections []TextSelection
 	for _, selection := range t.selections() {
 		for _, e := range edits {
-			start := e.At
-			if e.Delta < 0 {
-				start -= e.Delta
+			// Text inserted at the start of a selection is not included in
+			// it, and positions inside deleted text collapse to its start.
+			if selection.start > e.At {
+				selection.start = math.Max(selection.start+e.Delta, e.At)
 			}
-			delta := e.Delta
-			if selection.start >= start {
-				selection.start += delta
+			if selection.end >= e.At {
+				selection.end = math.Max(selection.end+e.Delta, e.At)
 			}
-			if selection.end >= start {
-				selection.end += delta
-			}
-		}
-		if selection.end < selection.start {
-			selection.end = selection.start
 		}
 		selection.start = math.Clamp(selection.start, min, max)
 		selection.end = math.Clamp(selection.end, min, max)
-		selection = selection.Store()
-		selections = append(selections, selection)
+		selections = append(selections, selection.Store())
 	}
-	t.setSelections(selections)
+	t.setSelections(mergeSelections(selections))
 }
 
 func (t *TextBoxController) SetTextRunesNoEvent(text []rune) {
@@ -341,11 +334,12 @@ func (t *TextBoxController) IndexWordLeft(sel TextSelection) TextSelection {
 }
 
 func (t *TextBoxController) indexWordLeft(i int) int {
+	text := t.text()
 	i--
 	if i <= 0 {
 		return 0
 	}
-	for ; i > 0 && t.RuneInWord(t.text()[i]); i-- {
+	for ; i > 0 && t.RuneInWord(text[i]) && t.RuneInWord(text[i-1]); i-- {
 	}
 	return i
 }
@@ -362,7 +356,7 @@ func (t *TextBoxController) indexWordRight(i int) int {
 	if i >= len(text) {
 		return len(text)
 	}
-	for ; i < len(text) && t.RuneInWord(text[i]); i++ {
+	for ; i < len(text) && t.RuneInWord(text[i-1]) && t.RuneInWord(text[i]); i++ {
 	}
 	return i
 }
@@ -412,11 +406,15 @@ func (t *TextBoxController) IndexHome(sel TextSelection) TextSelection {
 }
 
 func (t *TextBoxController) indexHome(i int) int {
+	text := t.text()
 	line := t.LineIndex(i)
-	start := t.LineStart(line)
-	indent := t.LineIndent(line)
-	if start+indent < i {
-		return start + indent
+	start, end := t.LineStart(line), t.LineEnd(line)
+	indent := start
+	for indent < end && unicode.IsSpace(text[indent]) {
+		indent++
+	}
+	if indent < i {
+		return indent
 	}
 	return start
 }
@@ -512,24 +510,11 @@ func (t *TextBoxController) RestoreNextSelections() {
 
 func (t *TextBoxController) AddCarets(transform SelectionTransform) {
 	t.setStoreCaretLocationsNextEdit(true)
-	add := t.selections()
-	for i, s := range add {
-		add[i] = transform(s)
-	}
 	sel := t.selections()
-	sel = append(sel, add...)
-	var j int
-	for i := 0; i < len(sel); i++ {
-		if sel[i].Start() > add[j].Start() {
-			copy(sel[i+1:], sel[i:len(sel)-1])
-			sel[i] = add[j]
-			j++
-			if j == len(add) {
-				break
-			}
-		}
+	for _, s := range t.selections() {
+		sel = append(sel, transform(s))
 	}
-	t.setSelections(sel)
+	t.setSelections(mergeSelections(sel))
 	t.selectionChanged()
 }
 
@@ -553,7 +538,7 @@ func (t *TextBoxController) GrowSelections(transform SelectionTransform) {
 	for i, s := range sel {
 		sel[i] = t.growSelection(s, transform(s))
 	}
-	t.setSelections(sel)
+	t.setSelections(mergeSelections(sel))
 	t.selectionChanged()
 }
 
@@ -563,10 +548,32 @@ func (t *TextBoxController) MoveSelections(transform SelectionTransform) {
 	for i, s := range sel {
 		sel[i] = transform(s)
 	}
-	t.setSelections(sel)
+	t.setSelections(mergeSelections(sel))
 	t.selectionChanged()
 }
 
+// mergeSelections returns sel sorted by start, with overlapping selections
+// and coincident carets merged. Adjacent selections are kept apart.
+func mergeSelections(sel []TextSelection) []TextSelection {
+	sort.SliceStable(sel, func(i, j int) bool { return sel[i].start < sel[j].start })
+	var merged []TextSelection
+	for _, s := range sel {
+		if n := len(merged); n > 0 {
+			p := &merged[n-1]
+			if s.start < p.end || s.start == p.start || (s.start == s.end && s.start == p.end) {
+				if s.end > p.end {
+					p.end = s.end
+					p.caretAtStart = s.caretAtStart
+				}
+				*p = p.Store()
+				continue
+			}
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
 func (t *TextBoxController) AddCaretsUp()       { t.AddCarets(t.IndexUp) }
 func (t *TextBoxController) AddCaretsDown()     { t.AddCarets(t.IndexDown) }
 func (t *TextBoxController) SelectFirst()       { t.GrowSelections(t.IndexFirst) }
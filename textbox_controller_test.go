@@ -13,14 +13,14 @@ import (
 
 func parseTBC(markup string) *TextBoxController {
 	tbc := CreateTextBoxController()
-	tbc.selections = TextSelectionList{}
 	runes := make([]rune, 0, 32)
+	sels := []TextSelection{}
 	sel := TextSelection{}
 	for _, c := range markup {
 		i := len(runes)
 		switch c {
 		case '|':
-			tbc.AddCaret(i)
+			sels = append(sels, CreateTextSelection(i, i, false))
 		case '{':
 			sel.start = i
 			sel.caretAtStart = false
@@ -32,29 +32,42 @@ func parseTBC(markup string) *TextBoxController {
 			if sel.CaretAtStart() {
 				panic("Carat should be at end")
 			}
-			tbc.AddSelection(sel)
+			sels = append(sels, sel)
 		case '}':
 			sel.end = i
 			if !sel.CaretAtStart() {
 				panic("Carat should be at start")
 			}
-			tbc.AddSelection(sel)
+			sels = append(sels, sel)
 		default:
 			runes = append(runes, c)
 		}
 	}
+	// The selections are stored by SetTextRunes.
+	tbc.setSelections(sels)
 	tbc.SetTextRunes(runes)
 	return tbc
 }
 
+// markupSelections returns the selections of c without their stored
+// positions, which are not expressed by the markup.
+func markupSelections(c *TextBoxController) []TextSelection {
+	sels := c.selections()
+	for i, s := range sels {
+		sels[i] = CreateTextSelection(s.start, s.end, s.caretAtStart)
+	}
+	return sels
+}
+
 func assertTBCTextAndSelectionsEqual(t *testing.T, markup string, c *TextBoxController) {
 	expected := parseTBC(markup)
 	test.AssertEquals(t, expected.Text(), c.Text())
-	test.AssertEquals(t, expected.selections, c.selections)
+	test.AssertEquals(t, markupSelections(expected), markupSelections(c))
 }
 
 func TestTBCLineIndent(t *testing.T) {
 	c := parseTBC("  ÀÁ\n    BB\nĆ\n      D\n   EE")
+	c.SetIndent(" ") // Count the indentation in spaces
 	test.AssertEquals(t, 2, c.LineIndent(0))
 	test.AssertEquals(t, 4, c.LineIndent(1))
 	test.AssertEquals(t, 0, c.LineIndent(2))
@@ -268,12 +281,14 @@ func TestTBCUnicode(t *testing.T) {
 
 func TestTBCIndentSelection(t *testing.T) {
 	c := parseTBC("a{aa\n  b]bb|bb\n    [cc}\nddd\ne{e][e}e\n")
-	c.IndentSelection(2)
+	c.SetIndent("  ")
+	c.IndentSelection()
 	assertTBCTextAndSelectionsEqual(t, "  a{aa\n    b]bb|bb\n      [cc}\nddd\n  e{e][e}e\n", c)
 }
 
 func TestTBCUnindentSelection(t *testing.T) {
 	c := parseTBC("  a{aa\n    b]bb|bb\n      [cc}\nddd\n  e{e][e}e\n")
-	c.UnindentSelection(2)
+	c.SetIndent("  ")
+	c.UnindentSelection()
 	assertTBCTextAndSelectionsEqual(t, "a{aa\n  b]bb|bb\n    [cc}\nddd\ne{e][e}e\n", c)
 }
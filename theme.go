@@ -19,6 +19,7 @@ type Theme interface {
 	CreateLinearLayout() LinearLayout
 	CreateList() List
 	CreatePanelHolder() PanelHolder
+	CreatePerfOverlay() PerfOverlay
 	CreateProgressBar() ProgressBar
 	CreateScrollBar() ScrollBar
 	CreateScrollLayout() ScrollLayout
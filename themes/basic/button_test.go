@@ -0,0 +1,43 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package basic_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/basic"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestButtonPaint(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver).(*basic.Theme)
+		button := theme.CreateButton()
+		button.Attach()
+		defer button.Detach()
+		button.SetSize(math.Size{W: 40, H: 20})
+
+		style := theme.ButtonDefaultStyle
+		r := math.CreateRect(0, 0, 40, 20)
+		expected := &displaylist.List{
+			Size: math.Size{W: 40, H: 20},
+			Ops: []displaylist.Op{
+				displaylist.DrawRoundedRect{Rect: r, TL: 2, TR: 2, BL: 2, BR: 2, Pen: gxui.TransparentPen, Brush: style.Brush},
+				displaylist.DrawRoundedRect{Rect: r, TL: 2, TR: 2, BL: 2, BR: 2, Pen: style.Pen, Brush: gxui.TransparentBrush},
+			},
+		}
+		got := displaylist.FromCanvas(button.Draw())
+		for _, d := range displaylist.Diff(got, expected) {
+			t.Error(d)
+		}
+	})
+}
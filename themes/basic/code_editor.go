@@ -42,5 +42,8 @@ func (t *CodeEditor) CreateSuggestionList() gxui.List {
 	l := t.theme.CreateList()
 	l.SetBackgroundBrush(t.theme.CodeSuggestionListStyle.Brush)
 	l.SetBorderPen(t.theme.CodeSuggestionListStyle.Pen)
+	if s, ok := l.(gxui.ShadowCaster); ok {
+		s.SetShadow(t.theme.CodeSuggestionListStyle.Shadow)
+	}
 	return l
 }
@@ -0,0 +1,51 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package basic_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/displaylist"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/basic"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestControlOpacityPaintsLayer(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver).(*basic.Theme)
+		layout := theme.CreateLinearLayout()
+		button := theme.CreateButton()
+		layout.AddChild(button)
+		layout.Attach()
+		defer layout.Detach()
+		layout.SetSize(math.Size{W: 100, H: 100})
+
+		button.SetOpacity(2)
+		if got, expected := button.Opacity(), float32(1); got != expected {
+			t.Errorf("Opacity was %v, expected %v", got, expected)
+		}
+		button.SetOpacity(0.5)
+		if got, expected := button.Opacity(), float32(0.5); got != expected {
+			t.Errorf("Opacity was %v, expected %v", got, expected)
+		}
+
+		layers := []displaylist.PushLayer{}
+		for _, op := range displaylist.FromCanvas(layout.Draw()).Ops {
+			if l, ok := op.(displaylist.PushLayer); ok {
+				layers = append(layers, l)
+			}
+		}
+		expected := []displaylist.PushLayer{{Opacity: 0.5, Blend: gxui.NormalBlend}}
+		if len(layers) != 1 || layers[0] != expected[0] {
+			t.Errorf("Layers were %+v, expected %+v", layers, expected)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package basic
+
+import (
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/mixins"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+type PerfOverlay struct {
+	mixins.PerfOverlay
+	theme *Theme
+}
+
+func CreatePerfOverlay(theme *Theme) gxui.PerfOverlay {
+	o := &PerfOverlay{}
+	o.Init(o, theme)
+	o.SetPadding(math.Spacing{L: 5, T: 5, R: 5, B: 5})
+	o.SetBackgroundBrush(gxui.CreateBrush(gxui.Color{R: 0, G: 0, B: 0, A: 0.6}))
+	o.SetBorderPen(gxui.TransparentPen)
+	label := o.Label()
+	label.SetMargin(math.Spacing{L: 4, T: 2, R: 4, B: 2})
+	if font := theme.DefaultMonospaceFont(); font != nil {
+		label.SetFont(font)
+	}
+	label.SetColor(gxui.White)
+	o.theme = theme
+	return o
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package basic_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/soft"
+	"github.com/robertt-smg/gxui/themes/basic"
+	"github.com/robertt-smg/gxui/themes/dark"
+
+	"github.com/robertt-smg/gxui/math"
+)
+
+func TestPerfOverlayLayout(t *testing.T) {
+	soft.StartDriver(func(driver gxui.Driver) {
+		defer driver.Terminate()
+
+		theme := dark.CreateTheme(driver).(*basic.Theme)
+		overlay := theme.CreatePerfOverlay().(*basic.PerfOverlay)
+		overlay.Attach()
+		defer overlay.Detach()
+		size := math.Size{W: 1000, H: 600}
+		overlay.SetSize(size)
+
+		label := overlay.Label()
+		if label.Text() == "" {
+			t.Error("Expected the overlay to display the driver statistics")
+		}
+		child := overlay.Children().Find(label)
+		if overlay.ContainsPoint(child.Bounds().Mid()) {
+			t.Error("Expected the overlay to let mouse events through")
+		}
+
+		// The statistics are displayed in the top-right corner by default.
+		b := child.Bounds().Expand(label.Margin())
+		padding := overlay.Padding()
+		if b.Max.X != size.W-padding.R || b.Min.Y != padding.T {
+			t.Errorf("Label bounds were %v, expected the top-right corner of %v", b, size)
+		}
+
+		overlay.SetHorizontalAlignment(gxui.AlignLeft)
+		overlay.SetVerticalAlignment(gxui.AlignBottom)
+		size = math.Size{W: 1200, H: 800}
+		overlay.SetSize(size)
+		b = child.Bounds().Expand(label.Margin())
+		if b.Min.X != padding.L || b.Max.Y != size.H-padding.B {
+			t.Errorf("Label bounds were %v, expected the bottom-left corner of %v", b, size)
+		}
+	})
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/robertt-smg/gxui/math"
 )
 
+const chevronStep = time.Millisecond * 50
+
 type ProgressBar struct {
 	mixins.ProgressBar
 	theme        *Theme
-	ticker       *time.Ticker
+	frame        gxui.EventSubscription
 	chevrons     gxui.Canvas
 	chevronWidth int
 	scroll       int
@@ -29,32 +31,27 @@ func CreateProgressBar(theme *Theme) gxui.ProgressBar {
 	b.chevronWidth = 10
 
 	b.OnAttach(func() {
-		driver := theme.Driver()
-		b.ticker = time.NewTicker(time.Millisecond * 50)
-		go func() {
-			for _ = range b.ticker.C {
-				if !driver.Call(b.animationTick) {
-					return
-				}
-			}
-		}()
+		b.frame = theme.Driver().OnFrame(b.animationTick)
 	})
 
 	b.OnDetach(func() {
-		if b.chevrons != nil {
-			b.chevrons = nil
-			b.ticker.Stop()
-			b.ticker = nil
-		}
+		b.frame.Unlisten()
+		b.frame = nil
+		b.chevrons = nil
 	})
 	b.SetBackgroundBrush(gxui.CreateBrush(gxui.Gray10))
 	b.SetBorderPen(gxui.CreatePen(1, gxui.Gray40))
 	return b
 }
 
-func (b *ProgressBar) animationTick() {
-	if b.Attached() {
-		b.scroll = (b.scroll + 1) % (b.chevronWidth * 2)
+func (b *ProgressBar) animationTick(t time.Duration) {
+	if b.chevronWidth <= 0 {
+		return
+	}
+	// The chevrons move one DIP every chevronStep.
+	scroll := int(t/chevronStep) % (b.chevronWidth * 2)
+	if b.scroll != scroll {
+		b.scroll = scroll
 		b.Redraw()
 	}
 }
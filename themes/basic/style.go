@@ -6,12 +6,15 @@ package basic
 
 import (
 	"github.com/robertt-smg/gxui"
+
+	"github.com/robertt-smg/gxui/math"
 )
 
 type Style struct {
 	FontColor gxui.Color
 	Brush     gxui.Brush
 	Pen       gxui.Pen
+	Shadow    gxui.Shadow
 }
 
 func CreateStyle(fontColor, brushColor, penColor gxui.Color, penWidth float32) Style {
@@ -21,3 +24,22 @@ func CreateStyle(fontColor, brushColor, penColor gxui.Color, penWidth float32) S
 		Brush:     gxui.CreateBrush(brushColor),
 	}
 }
+
+// CreateGradientStyle returns a Style with a brush that fades vertically from
+// topColor at the top of the control to bottomColor at the bottom.
+func CreateGradientStyle(fontColor, topColor, bottomColor, penColor gxui.Color, penWidth float32) Style {
+	return Style{
+		FontColor: fontColor,
+		Pen:       gxui.CreatePen(penWidth, penColor),
+		Brush: gxui.CreateLinearGradientBrush(math.Vec2{}, math.Vec2{Y: 1},
+			gxui.GradientStop{Offset: 0, Color: topColor},
+			gxui.GradientStop{Offset: 1, Color: bottomColor},
+		),
+	}
+}
+
+// WithShadow returns a copy of the style with the shadow s.
+func (s Style) WithShadow(shadow gxui.Shadow) Style {
+	s.Shadow = shadow
+	return s
+}
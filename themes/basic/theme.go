@@ -97,6 +97,10 @@ func (t *Theme) CreatePanelHolder() gxui.PanelHolder {
 	return CreatePanelHolder(t)
 }
 
+func (t *Theme) CreatePerfOverlay() gxui.PerfOverlay {
+	return CreatePerfOverlay(t)
+}
+
 func (t *Theme) CreateProgressBar() gxui.ProgressBar {
 	return CreateProgressBar(t)
 }
@@ -17,5 +17,6 @@ func CreateWindow(theme *Theme, width, height int, title string) gxui.Window {
 	w := &Window{}
 	w.Window.Init(w, theme.Driver(), width, height, title)
 	w.SetBackgroundBrush(gxui.CreateBrush(theme.WindowBackground))
+	w.SetDragOverlay(CreateBubbleOverlay(theme))
 	return w
 }
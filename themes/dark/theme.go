@@ -10,6 +10,8 @@ import (
 	"github.com/robertt-smg/gxui"
 	"github.com/robertt-smg/gxui/gxfont"
 	"github.com/robertt-smg/gxui/themes/basic"
+
+	"github.com/robertt-smg/gxui/math"
 )
 
 func CreateTheme(driver gxui.Driver) gxui.Theme {
@@ -36,6 +38,15 @@ func CreateTheme(driver gxui.Driver) gxui.Theme {
 	neonBlue := gxui.ColorFromHex(0xFF5C8CFF)
 	focus := gxui.ColorFromHex(0xA0C4D6FF)
 
+	popupShadow := gxui.Shadow{
+		Color:        gxui.Color{R: 0, G: 0, B: 0, A: 0.6},
+		Offset:       math.Point{X: 0, Y: 2},
+		Blur:         8,
+		CornerRadius: 3,
+	}
+	bubbleShadow := popupShadow
+	bubbleShadow.CornerRadius = 5
+
 	return &basic.Theme{
 		DriverInfo:               driver,
 		DefaultFontInfo:          defaultFont,
@@ -43,11 +54,11 @@ func CreateTheme(driver gxui.Driver) gxui.Theme {
 		WindowBackground:         gxui.Black,
 
 		//                                   fontColor    brushColor   penColor
-		BubbleOverlayStyle:        basic.CreateStyle(gxui.Gray80, gxui.Gray20, gxui.Gray40, 1.0),
+		BubbleOverlayStyle:        basic.CreateStyle(gxui.Gray80, gxui.Gray20, gxui.Gray40, 1.0).WithShadow(bubbleShadow),
 		ButtonDefaultStyle:        basic.CreateStyle(gxui.Gray80, gxui.Gray10, gxui.Gray20, 1.0),
 		ButtonOverStyle:           basic.CreateStyle(gxui.Gray90, gxui.Gray15, gxui.Gray50, 1.0),
 		ButtonPressedStyle:        basic.CreateStyle(gxui.Gray20, gxui.Gray70, gxui.Gray30, 1.0),
-		CodeSuggestionListStyle:   basic.CreateStyle(gxui.Gray80, gxui.Gray20, gxui.Gray10, 1.0),
+		CodeSuggestionListStyle:   basic.CreateStyle(gxui.Gray80, gxui.Gray20, gxui.Gray10, 1.0).WithShadow(popupShadow),
 		DropDownListDefaultStyle:  basic.CreateStyle(gxui.Gray80, gxui.Gray10, gxui.Gray20, 1.0),
 		DropDownListOverStyle:     basic.CreateStyle(gxui.Gray80, gxui.Gray15, gxui.Gray50, 1.0),
 		FocusedStyle:              basic.CreateStyle(gxui.Gray80, gxui.Transparent, focus, 1.0),
@@ -0,0 +1,16 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dark_test
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui/testing/uitest"
+	"github.com/robertt-smg/gxui/themes/dark"
+)
+
+func TestGoldenForm(t *testing.T) {
+	uitest.AssertFormGolden(t, dark.CreateTheme, "testdata/form.png")
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mock provides a Theme for unit tests. Its fonts have fixed glyph
+// metrics and its styles have no shadows, so that the layout and display
+// lists of controls can be tested with exact expected values. It is intended
+// to be used with the fake driver.
+package mock
+
+import (
+	"github.com/robertt-smg/gxui"
+	"github.com/robertt-smg/gxui/drivers/fake"
+	"github.com/robertt-smg/gxui/themes/basic"
+)
+
+// The metrics of the default fonts of the mock theme, in DIPs.
+const (
+	GlyphWidth  = 8
+	GlyphHeight = 16
+	Ascent      = 12
+)
+
+// CreateTheme returns a theme whose default and monospace fonts are
+// fake.Fonts with glyphs of GlyphWidth by GlyphHeight, and the baseline at
+// Ascent. The fonts can be replaced with SetDefaultFont and
+// SetDefaultMonospaceFont to test with other metrics.
+func CreateTheme(driver gxui.Driver) gxui.Theme {
+	font := fake.CreateFont(GlyphWidth, GlyphHeight, Ascent)
+
+	//                          fontColor   brushColor        penColor
+	plain := basic.CreateStyle(gxui.Black, gxui.White, gxui.Gray50, 1.0)
+	over := basic.CreateStyle(gxui.Black, gxui.Gray90, gxui.Gray30, 1.0)
+	pressed := basic.CreateStyle(gxui.White, gxui.Gray30, gxui.Gray10, 1.0)
+	text := basic.CreateStyle(gxui.Black, gxui.Transparent, gxui.Transparent, 0.0)
+	highlight := basic.CreateStyle(gxui.Black, gxui.Transparent, gxui.Blue, 2.0)
+
+	return &basic.Theme{
+		DriverInfo:               driver,
+		DefaultFontInfo:          font,
+		DefaultMonospaceFontInfo: font,
+		WindowBackground:         gxui.White,
+
+		BubbleOverlayStyle:        plain,
+		ButtonDefaultStyle:        plain,
+		ButtonOverStyle:           over,
+		ButtonPressedStyle:        pressed,
+		CodeSuggestionListStyle:   plain,
+		DropDownListDefaultStyle:  plain,
+		DropDownListOverStyle:     over,
+		FocusedStyle:              highlight,
+		HighlightStyle:            highlight,
+		LabelStyle:                text,
+		PanelBackgroundStyle:      plain,
+		ScrollBarBarDefaultStyle:  plain,
+		ScrollBarBarOverStyle:     over,
+		ScrollBarRailDefaultStyle: plain,
+		ScrollBarRailOverStyle:    over,
+		SplitterBarDefaultStyle:   plain,
+		SplitterBarOverStyle:      over,
+		TabActiveHighlightStyle:   highlight,
+		TabDefaultStyle:           plain,
+		TabOverStyle:              over,
+		TabPressedStyle:           pressed,
+		TextBoxDefaultStyle:       plain,
+		TextBoxOverStyle:          over,
+	}
+}
@@ -79,7 +79,7 @@ func TopControlsUnder(p math.Point, c Parent) ControlPointList {
 	children := c.Children()
 	for i := len(children) - 1; i >= 0; i-- {
 		child := children[i]
-		cp := p.Sub(child.Offset)
+		cp := child.ToChild(p)
 		if child.Control.ContainsPoint(cp) {
 			l := ControlPointList{ControlPoint{child.Control, cp}}
 			if cc, ok := child.Control.(Parent); ok {
@@ -99,7 +99,7 @@ func ControlsUnder(p math.Point, c Parent) ControlPointList {
 		p = toVisit[0].P
 		toVisit = toVisit[1:]
 		for _, child := range c.Children() {
-			cp := p.Sub(child.Offset)
+			cp := child.ToChild(p)
 			if child.Control.ContainsPoint(cp) {
 				l = append(l, ControlPoint{child.Control, cp})
 				if cc, ok := child.Control.(Parent); ok {
@@ -112,6 +112,9 @@ func ControlsUnder(p math.Point, c Parent) ControlPointList {
 }
 
 func WindowToChild(coord math.Point, to Control) math.Point {
+	// Collect the path from the window down to the control, then map the
+	// point through each child in turn.
+	path := []*Child{}
 	c := to
 	for {
 		p := c.Parent()
@@ -123,12 +126,16 @@ func WindowToChild(coord math.Point, to Control) math.Point {
 			Dump(p)
 			panic(fmt.Errorf("Control's parent (%p %T) did not contain control (%p %T).", &p, p, &c, c))
 		}
-		coord = coord.Sub(child.Offset)
+		path = append(path, child)
 		if _, ok := p.(Window); ok {
-			return coord
+			break
 		}
 		c = p.(Control)
 	}
+	for i := len(path) - 1; i >= 0; i-- {
+		coord = path[i].ToChild(coord)
+	}
+	return coord
 }
 
 func ChildToParent(coord math.Point, from Control, to Parent) math.Point {
@@ -143,7 +150,7 @@ func ChildToParent(coord math.Point, from Control, to Parent) math.Point {
 			Dump(p)
 			panic(fmt.Errorf("Control's parent (%p %T) did not contain control (%p %T).", &p, p, &c, c))
 		}
-		coord = coord.Add(child.Offset)
+		coord = child.ToParent(coord)
 		if p == to {
 			return coord
 		}
@@ -159,7 +166,34 @@ func ChildToParent(coord math.Point, from Control, to Parent) math.Point {
 }
 
 func ParentToChild(coord math.Point, from Parent, to Control) math.Point {
-	return coord.Sub(ChildToParent(math.ZeroPoint, to, from))
+	path := []*Child{}
+	c := to
+	for {
+		p := c.Parent()
+		if p == nil {
+			panic(fmt.Errorf("Control detached: %s", Path(c)))
+		}
+		child := p.Children().Find(c)
+		if child == nil {
+			Dump(p)
+			panic(fmt.Errorf("Control's parent (%p %T) did not contain control (%p %T).", &p, p, &c, c))
+		}
+		path = append(path, child)
+		if p == from {
+			break
+		}
+		if control, ok := p.(Control); ok {
+			c = control
+		} else {
+			Dump(p)
+			panic(fmt.Errorf("ParentToChild (%p %T) -> (%p %T) reached non-control parent (%p %T).",
+				&from, from, &to, to, &p, p))
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		coord = path[i].ToChild(coord)
+	}
+	return coord
 }
 
 func TransformCoordinate(coord math.Point, from, to Control) math.Point {
@@ -203,6 +237,17 @@ func FindControl(root Parent, test func(Control) (found bool)) Control {
 	return nil
 }
 
+// FindControlByTestID performs a depth-first search of the controls starting
+// from root, returning the first control that implements Testable and has the
+// test identifier id. If no control is found then FindControlByTestID returns
+// nil.
+func FindControlByTestID(root Parent, id string) Control {
+	return FindControl(root, func(c Control) bool {
+		t, ok := c.(Testable)
+		return ok && t.TestID() == id
+	})
+}
+
 func WindowContaining(c Control) Window {
 	for {
 		p := c.Parent()
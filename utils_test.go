@@ -0,0 +1,89 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gxui
+
+import (
+	"testing"
+
+	"github.com/robertt-smg/gxui/math"
+	test "github.com/robertt-smg/gxui/testing"
+)
+
+// testControl is a Control with a size and children. The Control methods not
+// used by the hit-testing functions panic.
+type testControl struct {
+	Control
+	parent   Parent
+	size     math.Size
+	children Children
+}
+
+func (c *testControl) Parent() Parent                  { return c.parent }
+func (c *testControl) Size() math.Size                 { return c.size }
+func (c *testControl) ContainsPoint(p math.Point) bool { return c.size.Rect().Contains(p) }
+func (c *testControl) Relayout()                       {}
+func (c *testControl) Redraw()                         {}
+func (c *testControl) Children() Children              { return c.children }
+
+type testWindow struct {
+	Window
+	children Children
+}
+
+func (w *testWindow) Children() Children { return w.children }
+
+// createTransformedTree returns a window holding the control a at (10, 10),
+// which holds the control c covering a, and above it the control b rotated
+// by 90 degrees clockwise about its top-left corner and offset by (50, 10),
+// so that b covers x in [30, 50] and y in [10, 50] of a.
+func createTransformedTree() (w *testWindow, a, b, c *testControl) {
+	w = &testWindow{}
+	a = &testControl{parent: w, size: math.Size{W: 100, H: 100}}
+	b = &testControl{parent: a, size: math.Size{W: 40, H: 20}}
+	c = &testControl{parent: a, size: math.Size{W: 100, H: 100}}
+	m := math.CreateMat3Rotate(math.Pi / 2)
+	w.children = Children{{Control: a, Offset: math.Point{X: 10, Y: 10}}}
+	a.children = Children{
+		{Control: c},
+		{Control: b, Offset: math.Point{X: 50, Y: 10}, Transform: &m},
+	}
+	return w, a, b, c
+}
+
+func TestTransformedChild(t *testing.T) {
+	_, a, _, _ := createTransformedTree()
+	child := a.children[1]
+	test.AssertEquals(t, math.CreateRect(30, 10, 50, 50), child.Bounds())
+	test.AssertEquals(t, math.Point{X: 30, Y: 5}, child.ToChild(math.Point{X: 45, Y: 40}))
+	test.AssertEquals(t, math.Point{X: 45, Y: 40}, child.ToParent(math.Point{X: 30, Y: 5}))
+}
+
+func TestTopControlsUnderTransformed(t *testing.T) {
+	w, a, b, c := createTransformedTree()
+	test.AssertEquals(t, ControlPointList{
+		{a, math.Point{X: 45, Y: 40}},
+		{b, math.Point{X: 30, Y: 5}},
+	}, TopControlsUnder(math.Point{X: 55, Y: 50}, w))
+
+	// Outside of b's transformed bounds, c is the top control.
+	test.AssertEquals(t, ControlPointList{
+		{a, math.Point{X: 70, Y: 15}},
+		{c, math.Point{X: 70, Y: 15}},
+	}, TopControlsUnder(math.Point{X: 80, Y: 25}, w))
+}
+
+func TestControlsUnderTransformed(t *testing.T) {
+	w, a, b, c := createTransformedTree()
+	test.AssertEquals(t, ControlPointList{
+		{a, math.Point{X: 45, Y: 40}},
+		{c, math.Point{X: 45, Y: 40}},
+		{b, math.Point{X: 30, Y: 5}},
+	}, ControlsUnder(math.Point{X: 55, Y: 50}, w))
+}
+
+func TestWindowToChildTransformed(t *testing.T) {
+	_, _, b, _ := createTransformedTree()
+	test.AssertEquals(t, math.Point{X: 30, Y: 5}, WindowToChild(math.Point{X: 55, Y: 50}, b))
+}
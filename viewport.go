@@ -8,8 +8,6 @@ import (
 	"image"
 
 	"github.com/robertt-smg/gxui/math"
-
-	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
 type Viewport interface {
@@ -56,8 +54,15 @@ type Viewport interface {
 	// SetPosition changes position of the viewport.
 	SetPosition(math.Point)
 
-	// SetCursor sets the cursor to c.
-	SetCursor(*glfw.Cursor)
+	// SetCursor sets the mouse cursor displayed over the viewport to c.
+	// Drivers that cannot display a shape display the closest shape they
+	// support.
+	SetCursor(c Cursor)
+
+	// SetCompositionRect sets the rectangle of the caret of the text being
+	// composed with an input method, in DIPs local to the viewport. Input
+	// methods position their candidate windows next to the rectangle.
+	SetCompositionRect(r math.Rect)
 
 	// Show makes the viewport visible.
 	Show()
@@ -74,6 +79,17 @@ type Viewport interface {
 	// viewport will require a call to SetCanvas.
 	SetCanvas(Canvas)
 
+	// SetCanvasDamaged changes the displayed content of the viewport to the
+	// specified Canvas, like SetCanvas, but only redraws the rectangles in
+	// damage, which are in DIPs. The Canvas must be unchanged from the
+	// previous canvas outside of damage. Viewports may redraw more than the
+	// damaged rectangles, for instance after the viewport was resized.
+	SetCanvasDamaged(c Canvas, damage []math.Rect)
+
+	// RedrawStats returns the counters of the frames presented by SetCanvas
+	// and SetCanvasDamaged.
+	RedrawStats() RedrawStats
+
 	// OnClose subscribes f to be called when the viewport closes.
 	OnClose(f func()) EventSubscription
 
@@ -119,4 +135,12 @@ type Viewport interface {
 	// OnKeyStroke subscribes f to be called whenever a keyboard key-stroke event
 	// is raised while the viewport has focus.
 	OnKeyStroke(f func(KeyStrokeEvent)) EventSubscription
+
+	// OnComposition subscribes f to be called whenever an input method starts,
+	// updates or ends the composition of text while the viewport has focus.
+	OnComposition(f func(CompositionEvent)) EventSubscription
+
+	// OnFileDrop subscribes f to be called whenever files are dropped onto the
+	// viewport from the operating system, where at is the point of the drop.
+	OnFileDrop(f func(paths []string, at math.Point)) EventSubscription
 }
@@ -12,6 +12,7 @@ import (
 
 type Window interface {
 	Container
+	InputInjector
 
 	// Title returns the title of the window.
 	// This is usually the text displayed at the top of the window.
@@ -104,4 +105,6 @@ type Window interface {
 	OnKeyUp(func(KeyboardEvent)) EventSubscription
 	OnKeyRepeat(func(KeyboardEvent)) EventSubscription
 	OnKeyStroke(func(KeyStrokeEvent)) EventSubscription
+	OnComposition(func(CompositionEvent)) EventSubscription
+	OnFileDrop(func(paths []string, at math.Point)) EventSubscription
 }